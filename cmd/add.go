@@ -3,80 +3,136 @@
 package cmd
 
 import (
-	"errors"
-	"fmt"
 	"log"
+	"strings"
 
 	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/bgreenwell/gitego/utils"
 	"github.com/spf13/cobra"
 )
 
 var (
-	addName       string
-	addEmail      string
-	addUsername   string
-	addSSHKey     string
-	addSigningKey string
-	addPAT        string
+	addName          string
+	addEmail         string
+	addUsername      string
+	addSSHKey        string
+	addSSHUser       string
+	addSSHPort       int
+	addSSHAgent      string
+	addSigningKey    string
+	addSigningFormat string
+	addSignoff       string
+	addExtends       string
+	addPAT           string
+	addForce         bool
 )
 
 // adder holds the dependencies for the add command, allowing them to be mocked for testing.
 type adder struct {
-	load     func() (*config.Config, error)
-	save     func(*config.Config) error
-	setToken func(string, string) error
+	load        func() (*config.Config, error)
+	save        func(*config.Config) error
+	setToken    func(string, string) error
+	listGPGKeys func() ([]utils.GPGKey, error)
+	loadPolicy  func(*config.Config) (*config.Policy, error)
 }
 
 // run is the core logic for the add command.
-func (a *adder) run(cmd *cobra.Command, args []string) {
+func (a *adder) run(cmd *cobra.Command, args []string) error {
 	profileName := args[0]
 
 	cfg, err := a.load()
 	if err != nil {
-		fmt.Printf("Error loading configuration: %v\n", err)
-
-		return
+		return errLoadConfig(err)
 	}
 
 	if _, exists := cfg.Profiles[profileName]; exists {
-		fmt.Printf("Error: Profile '%s' already exists.\n", profileName)
-		fmt.Printf("Use 'gitego edit %s' to modify it, or 'gitego rm %s' to remove it.\n", profileName, profileName)
+		return errors.AlreadyExists("profile '%s' already exists", profileName).
+			WithHint("Use 'gitego edit %s' to modify it, or 'gitego rm %s' to remove it.", profileName, profileName)
+	}
+
+	if addSigningFormat != "" && !config.IsValidSigningMode(addSigningFormat) {
+		return errors.InvalidInput("invalid signing format '%s'", addSigningFormat).
+			WithHint("Use one of: %s.", strings.Join(config.SigningModes, ", "))
+	}
 
-		return
+	if addSignoff != "" && !config.IsValidSignoffMode(addSignoff) {
+		return errors.InvalidInput("invalid sign-off mode '%s'", addSignoff).
+			WithHint("Use one of: %s.", strings.Join(config.SignoffModes, ", "))
+	}
+
+	if addExtends != "" {
+		if _, exists := cfg.Profiles[addExtends]; !exists {
+			return errors.NotFound("base profile '%s' not found", addExtends)
+		}
+	} else if addName == "" {
+		return errors.InvalidInput("--name is required unless the profile extends another with --extends")
 	}
 
 	newProfile := &config.Profile{
-		Name:       addName,
-		Email:      addEmail,
-		Username:   addUsername,
-		SSHKey:     addSSHKey,
-		SigningKey: addSigningKey,
+		Extends:          addExtends,
+		Name:             addName,
+		Email:            addEmail,
+		Username:         addUsername,
+		SSHKey:           addSSHKey,
+		SSHUser:          addSSHUser,
+		SSHPort:          addSSHPort,
+		SSHIdentityAgent: addSSHAgent,
+		SigningKey:       addSigningKey,
+		SigningFormat:    addSigningFormat,
+		Signoff:          addSignoff,
+	}
+
+	if err := resolveGPGSigningKey(a.listGPGKeys, newProfile); err != nil {
+		return errors.InvalidInput("invalid GPG signing key: %v", err)
 	}
 
+	// Fill in the fields the new profile inherits, so the policy checks its effective values.
 	cfg.Profiles[profileName] = newProfile
+	cfg.ResolveProfiles()
 
-	if err := a.save(cfg); err != nil {
-		fmt.Printf("Error saving configuration: %v\n", err)
+	if err := checkProfile(profileName, newProfile, addForce); err != nil {
+		delete(cfg.Profiles, profileName)
 
-		return
+		return err
+	}
+
+	if err := enforcePolicy(a.loadPolicy, cfg, func(p *config.Policy) []config.PolicyViolation {
+		return p.CheckProfile(cfg, profileName, newProfile, addPAT != "")
+	}); err != nil {
+		delete(cfg.Profiles, profileName)
+
+		return err
+	}
+
+	if err := a.save(cfg); err != nil {
+		return errSaveConfig(err)
 	}
 
 	if addPAT != "" {
 		if err := a.setToken(profileName, addPAT); err != nil {
-			fmt.Printf("Warning: Profile saved, but failed to store PAT securely: %v\n", err)
-
-			return
+			return errKeyring(err, "profile saved, but the PAT could not be stored securely")
 		}
 	}
 
-	fmt.Printf("✓ Profile '%s' added successfully.\n", profileName)
+	printSuccess("✓ Profile '%s' added successfully.\n", profileName)
+
+	return nil
 }
 
 var addCmd = &cobra.Command{
 	Use:   "add <profile_name>",
 	Short: "Adds a new user profile to the gitego config.",
 	Long: `Adds a new user profile, associating a profile name (e.g., "work")
-with a specific Git user name and email address.`,
+with a specific Git user name and email address.
+
+With --extends, the profile inherits every field it leaves empty from the
+base profile, so profiles that differ only in email and token can share the
+name, signing and SSH settings of one base.
+
+The profile name may contain letters, digits, '.', '_' and '-'. The email
+must be a valid address, the username a valid login, and the SSH key a file
+that only you can read; --force saves a profile that fails these checks.`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
 			return errors.New("requires exactly one argument: the profile name")
@@ -84,30 +140,38 @@ with a specific Git user name and email address.`,
 
 		return nil
 	},
-	// The Run function is a wrapper around our testable run method.
-	Run: func(cmd *cobra.Command, args []string) {
+	// The RunE function is a wrapper around our testable run method.
+	RunE: func(cmd *cobra.Command, args []string) error {
 		a := &adder{
-			load:     config.Load,
-			save:     func(c *config.Config) error { return c.Save() },
-			setToken: config.SetToken,
+			load:        config.Load,
+			save:        func(c *config.Config) error { return c.Save() },
+			setToken:    config.SetToken,
+			listGPGKeys: utils.ListGPGSecretKeys,
+			loadPolicy:  config.LoadPolicy,
 		}
-		a.run(cmd, args)
+		return a.run(cmd, args)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(addCmd)
 
-	addCmd.Flags().StringVarP(&addName, "name", "n", "", "The user.name for the profile")
+	addCmd.Flags().StringVarP(&addName, "name", "n", "", "The user.name for the profile (optional with --extends)")
 	addCmd.Flags().StringVarP(&addEmail, "email", "e", "", "The user.email for the profile")
 	addCmd.Flags().StringVar(&addUsername, "username", "", "Login username for the service (e.g., GitHub username)")
 	addCmd.Flags().StringVar(&addSSHKey, "ssh-key", "", "Path to the SSH key for this profile (optional)")
-	addCmd.Flags().StringVar(&addSigningKey, "signing-key", "", "GPG key ID or SSH key path for commit signing (optional)")
+	addCmd.Flags().StringVar(&addSSHUser, "ssh-user", "", "SSH login user for this profile's hosts (optional)")
+	addCmd.Flags().IntVar(&addSSHPort, "ssh-port", 0, "SSH port for this profile's hosts (optional)")
+	addCmd.Flags().StringVar(&addSSHAgent, "ssh-agent", "", "IdentityAgent socket for this profile, or 'none' (optional)")
+	addCmd.Flags().StringVar(&addSigningKey, "signing-key", "", "GPG key ID, 'auto' to pick a GPG key by email, or SSH key path for commit signing (optional)")
+	addCmd.Flags().StringVar(&addSigningFormat, "signing-format", "",
+		"Commit signing mode: gpg, ssh, x509 or none (inferred from the signing key if omitted)")
+	addCmd.Flags().StringVar(&addExtends, "extends", "", "Base profile whose values fill in the fields left empty (optional)")
+	addCmd.Flags().StringVar(&addSignoff, "signoff", "",
+		"Signed-off-by trailer for this profile's commits: append or require (optional)")
 	addCmd.Flags().StringVar(&addPAT, "pat", "", "Personal Access Token for this profile (stored securely)")
+	addCmd.Flags().BoolVar(&addForce, "force", false, "Save the profile even if its email, username or keys fail validation")
 
-	if err := addCmd.MarkFlagRequired("name"); err != nil {
-		log.Fatalf("Failed to mark name flag as required: %v", err)
-	}
 	if err := addCmd.MarkFlagRequired("email"); err != nil {
 		log.Fatalf("Failed to mark email flag as required: %v", err)
 	}
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/bgreenwell/gitego/utils"
 )
 
 func TestAddCommand(t *testing.T) {
@@ -65,3 +67,135 @@ func TestAddCommand(t *testing.T) {
 		t.Error("SetToken was not called with the correct profile name and PAT")
 	}
 }
+
+func TestAddCommand_AutoSigningKey(t *testing.T) {
+	mockCfg := &config.Config{
+		Profiles: make(map[string]*config.Profile),
+	}
+
+	a := &adder{
+		load:     func() (*config.Config, error) { return mockCfg, nil },
+		save:     func(c *config.Config) error { return nil },
+		setToken: func(string, string) error { return nil },
+		listGPGKeys: func() ([]utils.GPGKey, error) {
+			return []utils.GPGKey{
+				{KeyID: "AAAA1111", Fingerprint: "FFFFAAAA1111", UIDs: []string{"Other <other@example.com>"}},
+				{KeyID: "BBBB2222", Fingerprint: "FFFFBBBB2222", UIDs: []string{"Signer <signer@example.com>"}},
+			}, nil
+		},
+	}
+
+	addName = "Signer"
+	addEmail = "signer@example.com"
+	addPAT = ""
+	addSigningKey = signingKeyAuto
+
+	defer func() { addSigningKey = "" }()
+
+	a.run(addCmd, []string{"signer"})
+
+	profile, ok := mockCfg.Profiles["signer"]
+	if !ok {
+		t.Fatal("Profile 'signer' was not added to the config")
+	}
+
+	if profile.SigningKey != "FFFFBBBB2222" || profile.SigningFormat != config.SigningGPG {
+		t.Errorf("Expected the matching GPG key to be selected, got '%s' (%s)", profile.SigningKey, profile.SigningFormat)
+	}
+}
+
+func TestAddCommand_InvalidGPGKey(t *testing.T) {
+	mockCfg := &config.Config{
+		Profiles: make(map[string]*config.Profile),
+	}
+
+	a := &adder{
+		load:        func() (*config.Config, error) { return mockCfg, nil },
+		save:        func(c *config.Config) error { return nil },
+		setToken:    func(string, string) error { return nil },
+		listGPGKeys: func() ([]utils.GPGKey, error) { return nil, nil },
+	}
+
+	addName = "Typo"
+	addEmail = "typo@example.com"
+	addPAT = ""
+	addSigningKey = "DEADBEEF"
+
+	defer func() { addSigningKey = "" }()
+
+	a.run(addCmd, []string{"typo"})
+
+	if _, ok := mockCfg.Profiles["typo"]; ok {
+		t.Error("Expected profile with an unknown GPG key to be rejected, but it was added.")
+	}
+}
+
+func TestAddCommand_PolicyViolation(t *testing.T) {
+	mockCfg := &config.Config{
+		Profiles: make(map[string]*config.Profile),
+	}
+
+	a := &adder{
+		load:        func() (*config.Config, error) { return mockCfg, nil },
+		save:        func(c *config.Config) error { return nil },
+		setToken:    func(string, string) error { return nil },
+		listGPGKeys: func() ([]utils.GPGKey, error) { return nil, nil },
+		loadPolicy: func(*config.Config) (*config.Policy, error) {
+			return &config.Policy{Rules: []config.PolicyRule{{AllowedEmailDomains: []string{"corp.com"}}}}, nil
+		},
+	}
+
+	addName = "Personal"
+	addEmail = "me@gmail.com"
+	addPAT = ""
+
+	err := a.run(addCmd, []string{"personal"})
+
+	if _, ok := mockCfg.Profiles["personal"]; ok || errors.KindOf(err) != errors.KindInvalidInput {
+		t.Errorf("Expected profile violating the policy to be rejected, got %v", err)
+	}
+
+	addEmail = "me@corp.com"
+
+	a.run(addCmd, []string{"personal"})
+
+	if _, ok := mockCfg.Profiles["personal"]; !ok {
+		t.Error("Expected compliant profile to be added.")
+	}
+}
+
+func TestAddCommand_Extends(t *testing.T) {
+	mockCfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"consultant": {Name: "Jane Doe", Email: "jane@example.com", SSHKey: "~/.ssh/id_consulting"},
+		},
+	}
+
+	a := &adder{
+		load:     func() (*config.Config, error) { return mockCfg, nil },
+		save:     func(c *config.Config) error { return nil },
+		setToken: func(string, string) error { return nil },
+	}
+
+	addName, addEmail, addPAT, addExtends = "", "jane@acme.com", "", "consultant"
+
+	defer func() { addEmail, addExtends = "", "" }()
+
+	a.run(addCmd, []string{"acme"})
+
+	profile, ok := mockCfg.Profiles["acme"]
+	if !ok {
+		t.Fatal("Profile 'acme' was not added to the config")
+	}
+
+	if profile.Name != "Jane Doe" || !profile.Inherits("name") || profile.Inherits("email") {
+		t.Errorf("Expected 'acme' to inherit only its name, got %+v (inherited %v)", profile, profile.InheritedFields())
+	}
+
+	addExtends = "missing"
+
+	err := a.run(addCmd, []string{"other"})
+	if _, exists := mockCfg.Profiles["other"]; exists || errors.KindOf(err) != errors.KindNotFound {
+		t.Errorf("Expected a missing base to be rejected, got: %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+// cmd/audit.go
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/bgreenwell/gitego/utils"
+	"github.com/spf13/cobra"
+)
+
+// Kinds of audit findings.
+const (
+	findingAuthor    = "author"
+	findingCommitter = "committer"
+	findingSignature = "signature"
+)
+
+var (
+	auditSince   string
+	auditBranch  string
+	auditProfile string
+	auditJSON    bool
+)
+
+// auditFinding is a commit that doesn't match the expected profile.
+type auditFinding struct {
+	Commit   string `json:"commit"`
+	Date     string `json:"date"`
+	Subject  string `json:"subject"`
+	Kind     string `json:"kind"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	// Name is the author or committer name recorded with Actual, for identity findings.
+	Name string `json:"name,omitempty"`
+}
+
+// auditRunner holds the dependencies for the audit command for mocking.
+type auditRunner struct {
+	load       func() (*config.Config, error)
+	getCommits func(...string) ([]utils.Commit, error)
+}
+
+// run is the core logic for the audit command. Findings are returned as an error, so the
+// command fails when it finds anything.
+func (r *auditRunner) run(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	profileName, profile, findings, err := r.audit(args)
+	if err != nil {
+		return err
+	}
+
+	if auditJSON {
+		if findings == nil {
+			findings = []auditFinding{}
+		}
+
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(findings)
+	} else if len(findings) == 0 {
+		fprintSuccess(out, "✓ All commits match profile '%s' (%s).\n", profileName, profile.Email)
+	} else {
+		printAuditTable(out, findings)
+	}
+
+	if len(findings) > 0 {
+		return errors.General(nil, "%d finding(s) for profile '%s' (%s)", len(findings), profileName, profile.Email)
+	}
+
+	return nil
+}
+
+// audit resolves the expected profile and checks the selected commits against it.
+func (r *auditRunner) audit(args []string) (string, *config.Profile, []auditFinding, error) {
+	cfg, err := r.load()
+	if err != nil {
+		return "", nil, nil, errLoadConfig(err)
+	}
+
+	return r.auditConfig(cfg, args)
+}
+
+// auditConfig is audit with the configuration already loaded.
+func (r *auditRunner) auditConfig(cfg *config.Config, args []string) (string, *config.Profile, []auditFinding, error) {
+	profileName := auditProfile
+	if profileName == "" {
+		profileName, _ = cfg.GetActiveProfileForCurrentDir()
+	}
+
+	if profileName == "" {
+		return "", nil, nil, errors.NotFound("no profile is expected for this repository").
+			WithHint("Choose one with --profile.")
+	}
+
+	profile, exists := cfg.Profiles[profileName]
+	if !exists {
+		return "", nil, nil, errProfileNotFound(profileName)
+	}
+
+	logArgs, err := auditLogArgs(args)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	commits, err := r.getCommits(logArgs...)
+	if err != nil {
+		return "", nil, nil, errors.IO(err, "could not read the commit history")
+	}
+
+	return profileName, profile, auditCommits(commits, profile), nil
+}
+
+// auditLogArgs turns the command's arguments and filters into 'git log' arguments.
+func auditLogArgs(args []string) ([]string, error) {
+	if len(args) > 0 && auditBranch != "" {
+		return nil, errors.InvalidInput("give either a revision range or --branch, not both")
+	}
+
+	var logArgs []string
+
+	if auditSince != "" {
+		logArgs = append(logArgs, "--since="+auditSince)
+	}
+
+	switch {
+	case len(args) > 0:
+		logArgs = append(logArgs, args[0])
+	case auditBranch != "":
+		logArgs = append(logArgs, auditBranch)
+	}
+
+	// Separate revisions from paths so a branch named like a file isn't misread.
+	return append(logArgs, "--"), nil
+}
+
+// auditCommits returns the findings for commits that don't match the profile: a different
+// author or committer email, or a signature made with a key the profile doesn't own.
+func auditCommits(commits []utils.Commit, profile *config.Profile) []auditFinding {
+	var findings []auditFinding
+
+	ownsKey := profileKeyMatcher(profile)
+
+	expectedKey := profile.SigningKey
+	if expectedKey == "" {
+		expectedKey = "(no signing key)"
+	}
+
+	for _, commit := range commits {
+		add := func(kind, expected, actual, name string) {
+			findings = append(findings, auditFinding{
+				Commit: commit.Hash, Date: commit.Date, Subject: commit.Subject,
+				Kind: kind, Expected: expected, Actual: actual, Name: name,
+			})
+		}
+
+		if !strings.EqualFold(commit.AuthorEmail, profile.Email) {
+			add(findingAuthor, profile.Email, commit.AuthorEmail, commit.AuthorName)
+		}
+
+		if !strings.EqualFold(commit.CommitterEmail, profile.Email) {
+			add(findingCommitter, profile.Email, commit.CommitterEmail, commit.CommitterName)
+		}
+
+		if commit.IsSigned() && !ownsKey(commit.SignatureKeys) {
+			add(findingSignature, expectedKey, strings.Join(commit.SignatureKeys, " "), "")
+		}
+	}
+
+	return findings
+}
+
+// profileKeyMatcher returns a function reporting whether any of a signature's key IDs
+// belongs to the profile's signing key.
+func profileKeyMatcher(profile *config.Profile) func([]string) bool {
+	var owns func(string) bool
+
+	switch profile.SigningMode() {
+	case config.SigningSSH:
+		fingerprint, err := config.SSHSigningFingerprint(profile.SigningKey)
+		if err != nil {
+			// Without the public key the signature can't be attributed either way.
+			return func([]string) bool { return true }
+		}
+
+		owns = func(key string) bool { return key == fingerprint }
+	case config.SigningGPG, config.SigningX509:
+		if profile.SigningKey == "" {
+			// git picks a key by the committer email, which the identity checks cover.
+			return func([]string) bool { return true }
+		}
+
+		owns = func(key string) bool { return utils.GPGKey{Fingerprint: key}.Matches(profile.SigningKey) }
+	default:
+		return func([]string) bool { return false }
+	}
+
+	return func(keys []string) bool {
+		for _, key := range keys {
+			if owns(key) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// printAuditTable writes findings as an aligned table.
+func printAuditTable(out io.Writer, findings []auditFinding) {
+	w := tabwriter.NewWriter(out, minwidth, tabwidth, padding, padchar, flags)
+
+	_, _ = fmt.Fprintln(w, "COMMIT\tDATE\tISSUE\tFOUND\tEXPECTED\tSUBJECT")
+	_, _ = fmt.Fprintln(w, "------\t----\t-----\t-----\t--------\t-------")
+
+	for _, finding := range findings {
+		date, _, _ := strings.Cut(finding.Date, "T")
+		_, _ = fmt.Fprintf(w, "%.10s\t%s\t%s\t%s\t%s\t%s\n",
+			finding.Commit, date, finding.Kind, finding.Actual, finding.Expected, finding.Subject)
+	}
+
+	_ = w.Flush()
+}
+
+// auditCmd represents the audit command.
+var auditCmd = &cobra.Command{
+	Use:   "audit [revision-range]",
+	Short: "Reports commits that don't match the profile expected for this repository.",
+	Long: `Walks the history of the current repository (HEAD, a revision range such as
+origin/main..HEAD, or a --branch) and reports every commit whose author or
+committer email differs from the profile expected here, or that is signed with
+a key the profile doesn't own.
+
+The expected profile is the one 'gitego status' resolves for the repository,
+or the one given with --profile. Use --json for machine-readable output. The
+command exits with status 1 when it finds anything, so it can run in CI.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &auditRunner{
+			load:       config.Load,
+			getCommits: utils.GetCommits,
+		}
+
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	auditCmd.Flags().StringVar(&auditSince, "since", "", "Only audit commits more recent than a date, e.g. '2 weeks ago'")
+	auditCmd.Flags().StringVar(&auditBranch, "branch", "", "Audit the history of this branch instead of HEAD")
+	auditCmd.Flags().StringVar(&auditProfile, "profile", "", "Audit against this profile instead of the one expected here")
+	auditCmd.Flags().BoolVar(&auditJSON, "json", false, "Print findings as JSON")
+}
@@ -0,0 +1,105 @@
+// cmd/audit_test.go
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/bgreenwell/gitego/utils"
+	"github.com/spf13/cobra"
+)
+
+func TestAuditCommand(t *testing.T) {
+	mockCfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"work": {Name: "Work User", Email: "work@corp.com", SigningKey: "1234ABCD"},
+		},
+		ActiveProfile: "work",
+	}
+
+	commits := []utils.Commit{
+		{Hash: "aaaa", AuthorEmail: "work@corp.com", CommitterEmail: "work@corp.com",
+			SignatureStatus: "G", SignatureKeys: []string{"FFFF1234ABCD"}},
+		{Hash: "bbbb", AuthorName: "Me", AuthorEmail: "me@gmail.com", CommitterEmail: "work@corp.com",
+			SignatureStatus: "N"},
+		{Hash: "cccc", AuthorEmail: "work@corp.com", CommitterEmail: "work@corp.com",
+			SignatureStatus: "G", SignatureKeys: []string{"9999FFFF"}},
+	}
+
+	var gotArgs []string
+
+	runner := &auditRunner{
+		load: func() (*config.Config, error) { return mockCfg, nil },
+		getCommits: func(args ...string) ([]utils.Commit, error) {
+			gotArgs = args
+
+			return commits, nil
+		},
+	}
+
+	auditJSON = true
+	auditSince = "2 weeks ago"
+
+	defer func() {
+		auditJSON = false
+		auditSince = ""
+	}()
+
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	err := runner.run(cmd, []string{"origin/main..HEAD"})
+	if errors.ExitCode(err) != 1 {
+		t.Errorf("Expected exit code 1 with findings, got %v", err)
+	}
+
+	if strings.Join(gotArgs, " ") != "--since=2 weeks ago origin/main..HEAD --" {
+		t.Errorf("Unexpected git log arguments: %v", gotArgs)
+	}
+
+	var findings []auditFinding
+	if err := json.Unmarshal(out.Bytes(), &findings); err != nil {
+		t.Fatalf("Expected JSON output, got %v:\n%s", err, out.String())
+	}
+
+	if len(findings) != 2 {
+		t.Fatalf("Expected 2 findings, got %+v", findings)
+	}
+
+	if findings[0].Commit != "bbbb" || findings[0].Kind != findingAuthor || findings[0].Name != "Me" {
+		t.Errorf("Expected an author finding for bbbb, got %+v", findings[0])
+	}
+
+	if findings[1].Commit != "cccc" || findings[1].Kind != findingSignature {
+		t.Errorf("Expected a signature finding for cccc, got %+v", findings[1])
+	}
+}
+
+func TestAuditCommand_Clean(t *testing.T) {
+	runner := &auditRunner{
+		load: func() (*config.Config, error) {
+			return &config.Config{
+				Profiles:      map[string]*config.Profile{"work": {Email: "work@corp.com"}},
+				ActiveProfile: "work",
+			}, nil
+		},
+		getCommits: func(...string) ([]utils.Commit, error) {
+			return []utils.Commit{{Hash: "aaaa", AuthorEmail: "Work@Corp.com", CommitterEmail: "work@corp.com"}}, nil
+		},
+	}
+
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	err := runner.run(cmd, []string{})
+	if err != nil || !strings.Contains(out.String(), "All commits match profile 'work'") {
+		t.Errorf("Expected a clean audit, got %v and output:\n%s", err, out.String())
+	}
+}
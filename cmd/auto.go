@@ -3,12 +3,12 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -17,55 +17,74 @@ const (
 	exactArgs = 2
 )
 
+var autoSignoff string
+
 // autoRunner holds the dependencies for the auto command for mocking.
 type autoRunner struct {
 	load                   func() (*config.Config, error)
 	save                   func(*config.Config) error
 	ensureProfileGitconfig func(string, *config.Profile) error
 	addIncludeIf           func(string, string) error
+	getToken               func(string) (string, error)
+	loadPolicy             func(*config.Config) (*config.Policy, error)
 }
 
 // run is the core logic for the auto command.
-func (ar *autoRunner) run(cmd *cobra.Command, args []string) {
+func (ar *autoRunner) run(cmd *cobra.Command, args []string) error {
 	path := args[0]
 	profileName := args[1]
 
 	cfg, profile, err := ar.validateInputs(profileName)
 	if err != nil {
-		fmt.Println(err)
+		return err
+	}
 
-		return
+	if autoSignoff != "" && !config.IsValidSignoffMode(autoSignoff) {
+		return errors.InvalidInput("invalid sign-off mode '%s'", autoSignoff).
+			WithHint("Use one of: %s.", strings.Join(config.SignoffModes, ", "))
 	}
 
 	cleanPath, err := ar.processPath(path)
 	if err != nil {
-		fmt.Printf("Error resolving path '%s': %v\n", path, err)
+		return errors.InvalidInput("could not resolve path '%s': %v", path, err)
+	}
 
-		return
+	if cmd.Flags().Changed("signoff") {
+		if updated, err := ar.updateRuleSignoff(cfg, cleanPath, profileName); updated || err != nil {
+			return err
+		}
 	}
 
 	if ar.ruleExists(cfg, cleanPath, profileName, path) {
-		return
+		return nil
 	}
 
-	if err := ar.setupAutoRule(cfg, profileName, profile, cleanPath); err != nil {
-		fmt.Println(err)
+	if err := enforcePolicy(ar.loadPolicy, cfg, func(p *config.Policy) []config.PolicyViolation {
+		token, err := ar.getToken(profileName)
+
+		return p.CheckRule(cleanPath, profile, err == nil && token != "")
+	}); err != nil {
+		return err
+	}
 
-		return
+	if err := ar.setupAutoRule(cfg, profileName, profile, cleanPath, autoSignoff); err != nil {
+		return err
 	}
 
-	fmt.Println("✓ Rule setup complete.")
+	printSuccess("✓ Rule setup complete.\n")
+
+	return nil
 }
 
 func (ar *autoRunner) validateInputs(profileName string) (*config.Config, *config.Profile, error) {
 	cfg, err := ar.load()
 	if err != nil {
-		return nil, nil, fmt.Errorf("error loading configuration: %v", err)
+		return nil, nil, errLoadConfig(err)
 	}
 
 	profile, exists := cfg.Profiles[profileName]
 	if !exists {
-		return nil, nil, fmt.Errorf("profile '%s' not found in gitego", profileName)
+		return nil, nil, errProfileNotFound(profileName)
 	}
 
 	return cfg, profile, nil
@@ -93,7 +112,7 @@ func (ar *autoRunner) processPath(path string) (string, error) {
 func (ar *autoRunner) ruleExists(cfg *config.Config, cleanPath, profileName, originalPath string) bool {
 	for _, rule := range cfg.AutoRules {
 		if rule.Path == cleanPath && rule.Profile == profileName {
-			fmt.Printf("✓ Auto-switch rule for profile '%s' on path '%s' already exists.\n", profileName, originalPath)
+			printSuccess("✓ Auto-switch rule for profile '%s' on path '%s' already exists.\n", profileName, originalPath)
 
 			return true
 		}
@@ -102,30 +121,57 @@ func (ar *autoRunner) ruleExists(cfg *config.Config, cleanPath, profileName, ori
 	return false
 }
 
+// updateRuleSignoff sets the sign-off mode of an existing rule for profileName on cleanPath.
+// It reports false if there is no such rule.
+func (ar *autoRunner) updateRuleSignoff(cfg *config.Config, cleanPath, profileName string) (bool, error) {
+	for _, rule := range cfg.AutoRules {
+		if rule.Path != cleanPath || rule.Profile != profileName {
+			continue
+		}
+
+		rule.Signoff = autoSignoff
+		if err := ar.save(cfg); err != nil {
+			return true, errSaveConfig(err)
+		}
+
+		if autoSignoff == "" {
+			printSuccess("✓ Sign-off turned off for the rule on '%s'; the profile's own setting applies.\n", cleanPath)
+		} else {
+			printSuccess("✓ Sign-off mode for the rule on '%s' set to '%s'.\n", cleanPath, autoSignoff)
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
 func (ar *autoRunner) setupAutoRule(
 	cfg *config.Config,
 	profileName string,
 	profile *config.Profile,
 	cleanPath string,
+	signoff string,
 ) error {
-	fmt.Printf("Setting up new auto-switch rule for profile '%s'...\n", profileName)
+	printSuccess("Setting up new auto-switch rule for profile '%s'...\n", profileName)
 
 	if err := ar.ensureProfileGitconfig(profileName, profile); err != nil {
-		return fmt.Errorf("error creating profile gitconfig: %v", err)
+		return errors.IO(err, "could not create the profile gitconfig")
 	}
 
 	if err := ar.addIncludeIf(profileName, cleanPath); err != nil {
-		return fmt.Errorf("error updating global .gitconfig: %v", err)
+		return errors.IO(err, "could not update the global .gitconfig")
 	}
 
 	newRule := &config.AutoRule{
 		Path:    cleanPath,
 		Profile: profileName,
+		Signoff: signoff,
 	}
 
 	cfg.AutoRules = append(cfg.AutoRules, newRule)
 	if err := ar.save(cfg); err != nil {
-		return fmt.Errorf("warning: Git config updated, but failed to save rule to gitego config: %v", err)
+		return errors.IO(err, "git config updated, but the rule could not be saved to the gitego config")
 	}
 
 	return nil
@@ -135,19 +181,29 @@ var autoCmd = &cobra.Command{
 	Use:   "auto <path> <profile_name>",
 	Short: "Automatically switch profiles based on directory.",
 	Long: `Configures your global .gitconfig to automatically use a specific
-profile whenever you are working inside the given directory path.`,
+profile whenever you are working inside the given directory path.
+
+--signoff sets how the commit-msg hook handles Signed-off-by trailers for
+commits under the path, overriding the profile's own setting: append adds the
+profile's sign-off, require rejects commits without it. Run it again on an
+existing rule to change the mode; an empty value falls back to the profile.`,
 	Args: cobra.ExactArgs(exactArgs),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		runner := &autoRunner{
 			load:                   config.Load,
 			save:                   func(c *config.Config) error { return c.Save() },
 			ensureProfileGitconfig: config.EnsureProfileGitconfig,
 			addIncludeIf:           config.AddIncludeIf,
+			getToken:               config.GetToken,
+			loadPolicy:             config.LoadPolicy,
 		}
-		runner.run(cmd, args)
+		return runner.run(cmd, args)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(autoCmd)
+
+	autoCmd.Flags().StringVar(&autoSignoff, "signoff", "",
+		"Signed-off-by trailer for commits under the path: append or require (overrides the profile)")
 }
@@ -17,6 +17,10 @@ import (
 type checkCommitRunner struct {
 	getGitConfig func(string) (string, error)
 	loadConfig   func() (*config.Config, error)
+	loadPolicy   func(*config.Config) (*config.Policy, error)
+	getRemotes   func() ([]string, error)
+	findRepo     func() (*config.RepoConfig, error)
+	trustRepo    func(*config.RepoConfig) error
 	stdin        io.Reader
 	stderr       io.Writer
 	exit         func(int)
@@ -32,7 +36,37 @@ func (r *checkCommitRunner) run(cmd *cobra.Command, args []string) {
 	}
 
 	cfg, err := r.loadConfig()
-	if err != nil || len(cfg.AutoRules) == 0 {
+	if err != nil {
+		r.exit(0)
+
+		return
+	}
+
+	if !r.checkPolicy(cfg, gitEmail) {
+		_, _ = fmt.Fprintln(r.stderr, "Commit blocked by team policy.")
+		r.exit(1)
+
+		return
+	}
+
+	reader := bufio.NewReader(r.stdin)
+
+	repoConfig := r.loadRepoConfig(reader)
+	if repoConfig != nil {
+		signing, _ := r.getGitConfig("commit.gpgsign")
+
+		violations := repoConfig.CheckCommit(gitEmail, strings.EqualFold(signing, "true"))
+		if len(violations) > 0 {
+			_, _ = fmt.Fprintf(r.stderr, "\n--- gitego Repository Check ---\n")
+			printPolicyViolations(r.stderr, violations)
+			_, _ = fmt.Fprintf(r.stderr, "Commit blocked by %s.\n", config.RepoConfigName)
+			r.exit(1)
+
+			return
+		}
+	}
+
+	if len(cfg.AutoRules) == 0 && repoConfig == nil {
 		r.exit(0)
 
 		return
@@ -67,7 +101,6 @@ func (r *checkCommitRunner) run(cmd *cobra.Command, args []string) {
 	_, _ = fmt.Fprintf(r.stderr, "---------------------------\n")
 	_, _ = fmt.Fprintf(r.stderr, "Do you want to abort the commit? [Y/n]: ")
 
-	reader := bufio.NewReader(r.stdin)
 	response, _ := reader.ReadString('\n')
 
 	if strings.TrimSpace(strings.ToLower(response)) == "n" {
@@ -79,6 +112,77 @@ func (r *checkCommitRunner) run(cmd *cobra.Command, args []string) {
 	}
 }
 
+// loadRepoConfig returns the repository's .gitego.yaml if it is trusted. The first time a
+// file (or a changed version of it) is seen, the user is asked whether to trust it.
+func (r *checkCommitRunner) loadRepoConfig(reader *bufio.Reader) *config.RepoConfig {
+	if r.findRepo == nil {
+		return nil
+	}
+
+	repoConfig, err := r.findRepo()
+	if err != nil {
+		_, _ = fmt.Fprintf(r.stderr, "gitego: ignoring %s: %v\n", config.RepoConfigName, err)
+
+		return nil
+	}
+
+	if repoConfig == nil || repoConfig.Trusted {
+		return repoConfig
+	}
+
+	_, _ = fmt.Fprintf(r.stderr, "\n--- gitego Repository Check ---\n")
+	_, _ = fmt.Fprintf(r.stderr, "This repository has a %s that gitego has not seen before:\n", config.RepoConfigName)
+	_, _ = fmt.Fprintf(r.stderr, "  %s\n", repoConfig.Path)
+	_, _ = fmt.Fprintf(r.stderr, "It can change which profile is expected here. Trust it? [y/N]: ")
+
+	if !readYes(reader) {
+		_, _ = fmt.Fprintf(r.stderr, "Ignoring %s. Run 'gitego trust' to trust it later.\n", config.RepoConfigName)
+
+		return nil
+	}
+
+	if err := r.trustRepo(repoConfig); err != nil {
+		_, _ = fmt.Fprintf(r.stderr, "gitego: could not record trust: %v\n", err)
+
+		return nil
+	}
+
+	return repoConfig
+}
+
+// checkPolicy checks the commit identity against the team policy, printing any violations.
+// Policy violations can't be overridden at the prompt; the identity must be fixed.
+func (r *checkCommitRunner) checkPolicy(cfg *config.Config, gitEmail string) bool {
+	if r.loadPolicy == nil {
+		return true
+	}
+
+	policy, err := r.loadPolicy(cfg)
+	if err != nil {
+		_, _ = fmt.Fprintf(r.stderr, "gitego: could not load policy: %v\n", err)
+
+		return false
+	}
+
+	if policy.IsEmpty() {
+		return true
+	}
+
+	remotes, _ := r.getRemotes()
+	signing, _ := r.getGitConfig("commit.gpgsign")
+	dir, _ := os.Getwd()
+
+	violations := policy.CheckCommit(dir, remotes, gitEmail, strings.EqualFold(signing, "true"))
+	if len(violations) == 0 {
+		return true
+	}
+
+	_, _ = fmt.Fprintf(r.stderr, "\n--- gitego Policy Check ---\n")
+	printPolicyViolations(r.stderr, violations)
+
+	return false
+}
+
 // checkCommitCmd represents the check-commit command.
 var checkCommitCmd = &cobra.Command{
 	Use:    "check-commit",
@@ -88,6 +192,10 @@ var checkCommitCmd = &cobra.Command{
 		runner := &checkCommitRunner{
 			getGitConfig: utils.GetEffectiveGitConfig,
 			loadConfig:   config.Load,
+			loadPolicy:   config.LoadPolicy,
+			getRemotes:   utils.GetRemoteURLs,
+			findRepo:     func() (*config.RepoConfig, error) { return config.FindRepoConfig(".") },
+			trustRepo:    (*config.RepoConfig).Trust,
 			stdin:        os.Stdin,
 			stderr:       os.Stderr,
 			exit:         os.Exit,
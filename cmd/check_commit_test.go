@@ -112,3 +112,94 @@ func TestCheckCommitCommand(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckCommitCommand_Policy(t *testing.T) {
+	exitCode := -1
+
+	var stderrBuf bytes.Buffer
+
+	runner := &checkCommitRunner{
+		getGitConfig: func(key string) (string, error) {
+			if key == "user.email" {
+				return "me@gmail.com", nil
+			}
+
+			return "", nil
+		},
+		loadConfig: func() (*config.Config, error) { return &config.Config{}, nil },
+		loadPolicy: func(*config.Config) (*config.Policy, error) {
+			return &config.Policy{Rules: []config.PolicyRule{
+				{Remote: "github.com/corp", AllowedEmailDomains: []string{"corp.com"}},
+			}}, nil
+		},
+		getRemotes: func() ([]string, error) { return []string{"git@github.com:corp/app.git"}, nil },
+		stdin:      strings.NewReader(""),
+		stderr:     &stderrBuf,
+		exit:       func(code int) { exitCode = code },
+	}
+
+	runner.run(&cobra.Command{}, []string{})
+
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1 for a policy violation, but got %d", exitCode)
+	}
+
+	if !strings.Contains(stderrBuf.String(), "email 'me@gmail.com' is not in an allowed domain") {
+		t.Errorf("Expected the violation in stderr. Got:\n%s", stderrBuf.String())
+	}
+}
+
+func TestCheckCommitCommand_RepoConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		trusted       bool
+		userInput     string
+		expectedExit  int
+		expectTrusted bool
+	}{
+		{"untrusted and declined", false, "n\n", 0, false},
+		{"untrusted and accepted", false, "y\n", 1, true},
+		{"already trusted", true, "", 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode := -1
+			trustCalled := false
+
+			var stderrBuf bytes.Buffer
+
+			runner := &checkCommitRunner{
+				getGitConfig: func(key string) (string, error) {
+					if key == "user.email" {
+						return "me@gmail.com", nil
+					}
+
+					return "", nil
+				},
+				loadConfig: func() (*config.Config, error) { return &config.Config{}, nil },
+				findRepo: func() (*config.RepoConfig, error) {
+					return &config.RepoConfig{
+						Path:                "/src/app/.gitego.yaml",
+						AllowedEmailDomains: []string{"corp.com"},
+						Trusted:             tt.trusted,
+					}, nil
+				},
+				trustRepo: func(*config.RepoConfig) error { trustCalled = true; return nil },
+				stdin:     strings.NewReader(tt.userInput),
+				stderr:    &stderrBuf,
+				exit:      func(code int) { exitCode = code },
+			}
+
+			runner.run(&cobra.Command{}, []string{})
+
+			if exitCode != tt.expectedExit {
+				t.Errorf("Expected exit code %d, got %d.\nStderr:\n%s", tt.expectedExit, exitCode, stderrBuf.String())
+			}
+
+			if trustCalled != tt.expectTrusted {
+				t.Errorf("Expected trust recorded = %v, got %v", tt.expectTrusted, trustCalled)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+// cmd/chpwd.go
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/utils"
+	"github.com/spf13/cobra"
+)
+
+// exportedVarsEnv lists the variables the shell hook exported, so it only ever unsets its own.
+const exportedVarsEnv = "GITEGO_EXPORTED_VARS"
+
+var (
+	chpwdShell     string
+	chpwdExportEnv bool
+)
+
+// chpwdRunner holds the dependencies for the chpwd command for mocking.
+type chpwdRunner struct {
+	load         func() (*config.Config, error)
+	getGitConfig func(string) (string, error)
+	inRepo       func() bool
+	getSecret    func(profileName, name string) (string, error)
+	getenv       func(string) string
+	stderr       io.Writer
+}
+
+// run is the core logic for the chpwd command. It prints shell code for the hook to
+// evaluate, and any notice on stderr. Failures are silent so a cd never breaks.
+func (r *chpwdRunner) run(cmd *cobra.Command, args []string) {
+	cfg, err := r.load()
+	if err != nil {
+		return
+	}
+
+	profileName, source := cfg.GetActiveProfileForCurrentDir()
+	profile := cfg.Profiles[profileName]
+
+	if profile != nil {
+		r.warnOnMismatch(profileName, profile)
+	}
+
+	if !chpwdExportEnv {
+		return
+	}
+
+	// Variables are only scoped to directories that select a profile themselves.
+	scoped := profile != nil && source != config.DefaultProfileSource
+
+	want := make(map[string]string)
+
+	if scoped {
+		// A secret that can't be read is left out rather than failing the whole hook.
+		for key, value := range profile.Env {
+			if name, isRef := config.ParseSecretRef(value); isRef {
+				secret, err := profile.EnvSecret(profileName, key, name, r.getSecret)
+				if err != nil {
+					continue
+				}
+
+				value = secret
+			}
+
+			want[key] = value
+		}
+
+		if _, set := want["GH_TOKEN"]; !set {
+			if token, err := r.getSecret(profileName, config.PATSecretName); err == nil && token != "" {
+				want["GH_TOKEN"] = token
+			}
+		}
+	}
+
+	_, _ = fmt.Fprint(cmd.OutOrStdout(), r.envScript(want))
+}
+
+// warnOnMismatch prints a one-line notice when the environment or git config would give
+// a different identity than the profile expected here.
+func (r *chpwdRunner) warnOnMismatch(profileName string, profile *config.Profile) {
+	var problems []string
+
+	for _, name := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		if value := r.getenv(name); value != "" && !strings.EqualFold(value, profile.Email) {
+			problems = append(problems, fmt.Sprintf("%s is %s", name, value))
+		}
+	}
+
+	// Outside a repository includeIf rules don't apply, so git config says nothing useful.
+	if r.inRepo() {
+		if email, err := r.getGitConfig("user.email"); err == nil && email != "" && !strings.EqualFold(email, profile.Email) {
+			problems = append(problems, fmt.Sprintf("git user.email is %s", email))
+		}
+	}
+
+	if len(problems) > 0 {
+		_, _ = fmt.Fprintf(r.stderr, "gitego: this directory expects profile '%s' <%s>, but %s.\n",
+			profileName, profile.Email, strings.Join(problems, " and "))
+	}
+}
+
+// envScript returns shell code that exports want and unsets the variables the hook exported
+// earlier that are no longer wanted. Variables the user set themselves are left alone.
+func (r *chpwdRunner) envScript(want map[string]string) string {
+	previous := make(map[string]bool)
+	for _, name := range strings.Fields(r.getenv(exportedVarsEnv)) {
+		previous[name] = true
+	}
+
+	var b strings.Builder
+
+	for _, name := range slices.Sorted(maps.Keys(previous)) {
+		if _, keep := want[name]; !keep {
+			b.WriteString(unsetLine(chpwdShell, name))
+		}
+	}
+
+	var exported []string
+
+	for _, name := range slices.Sorted(maps.Keys(want)) {
+		if !previous[name] && r.getenv(name) != "" {
+			continue
+		}
+
+		exported = append(exported, name)
+		b.WriteString(exportLine(chpwdShell, name, want[name]))
+	}
+
+	if len(exported) > 0 {
+		b.WriteString(exportLine(chpwdShell, exportedVarsEnv, strings.Join(exported, " ")))
+	} else if len(previous) > 0 {
+		b.WriteString(unsetLine(chpwdShell, exportedVarsEnv))
+	}
+
+	return b.String()
+}
+
+// exportLine returns the shell statement that exports name with value.
+func exportLine(shell, name, value string) string {
+	if shell == "fish" {
+		return fmt.Sprintf("set -gx %s %s;\n", name, fishQuote(value))
+	}
+
+	return fmt.Sprintf("export %s=%s;\n", name, config.ShellQuote(value))
+}
+
+// unsetLine returns the shell statement that removes name from the environment.
+func unsetLine(shell, name string) string {
+	if shell == "fish" {
+		return fmt.Sprintf("set -e %s;\n", name)
+	}
+
+	return fmt.Sprintf("unset %s;\n", name)
+}
+
+// fishQuote quotes a value for fish, where only \ and ' are special in single quotes.
+func fishQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
+
+// chpwdCmd is run by the 'gitego shell-init' hook whenever the directory changes.
+var chpwdCmd = &cobra.Command{
+	Use:   "chpwd",
+	Short: "Checks the identity for a new directory and prints shell code for the hook.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runner := &chpwdRunner{
+			load:         config.Load,
+			getGitConfig: utils.GetEffectiveGitConfig,
+			inRepo: func() bool {
+				_, err := findGitRoot(".")
+
+				return err == nil
+			},
+			getSecret: config.GetSecret,
+			getenv:    os.Getenv,
+			stderr:    os.Stderr,
+		}
+		runner.run(cmd, args)
+	},
+}
+
+func init() {
+	internalCmd.AddCommand(chpwdCmd)
+
+	chpwdCmd.Flags().StringVar(&chpwdShell, "shell", "bash", "Shell to print code for: bash, zsh or fish")
+	chpwdCmd.Flags().BoolVar(&chpwdExportEnv, "export-env", false,
+		"Export the profile's environment variables and GH_TOKEN")
+}
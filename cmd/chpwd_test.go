@@ -0,0 +1,116 @@
+// cmd/chpwd_test.go
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/spf13/cobra"
+)
+
+func TestChpwdCommand(t *testing.T) {
+	workDir := t.TempDir()
+
+	originalWd, _ := os.Getwd()
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = os.Chdir(originalWd) }()
+
+	mockCfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"work": {Name: "Work User", Email: "work@corp.com", Env: map[string]string{"GH_HOST": "github.corp.com"}},
+			"me":   {Name: "Me", Email: "me@gmail.com"},
+		},
+		AutoRules:     []*config.AutoRule{{Path: workDir, Profile: "work"}},
+		ActiveProfile: "me",
+	}
+
+	testCases := []struct {
+		name           string
+		rules          []*config.AutoRule
+		env            map[string]string
+		expectedNotice string
+		expectedScript string
+	}{
+		{
+			name:           "entering a rule directory exports the token",
+			rules:          mockCfg.AutoRules,
+			env:            map[string]string{"GIT_AUTHOR_EMAIL": "me@gmail.com"},
+			expectedNotice: "gitego: this directory expects profile 'work' <work@corp.com>, but GIT_AUTHOR_EMAIL is me@gmail.com and git user.email is me@gmail.com.\n",
+			expectedScript: "export GH_HOST='github.corp.com';\nexport GH_TOKEN='work-token';\n" +
+				"export GITEGO_EXPORTED_VARS='GH_HOST GH_TOKEN';\n",
+		},
+		{
+			name:           "leaving it unsets what the hook exported",
+			env:            map[string]string{"GITEGO_EXPORTED_VARS": "GH_HOST GH_TOKEN", "GH_TOKEN": "work-token"},
+			expectedScript: "unset GH_HOST;\nunset GH_TOKEN;\nunset GITEGO_EXPORTED_VARS;\n",
+		},
+		{
+			name:           "a token the user set is left alone",
+			rules:          mockCfg.AutoRules,
+			env:            map[string]string{"GH_TOKEN": "my-own"},
+			expectedNotice: "gitego: this directory expects profile 'work' <work@corp.com>, but git user.email is me@gmail.com.\n",
+			expectedScript: "export GH_HOST='github.corp.com';\nexport GITEGO_EXPORTED_VARS='GH_HOST';\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := *mockCfg
+			cfg.AutoRules = tc.rules
+
+			stderr := &bytes.Buffer{}
+
+			runner := &chpwdRunner{
+				load:         func() (*config.Config, error) { return &cfg, nil },
+				getGitConfig: func(string) (string, error) { return "me@gmail.com", nil },
+				inRepo:       func() bool { return true },
+				getSecret: func(profileName, name string) (string, error) {
+					if profileName == "work" && name == config.PATSecretName {
+						return "work-token", nil
+					}
+
+					return "", errors.New("no secret")
+				},
+				getenv: func(name string) string { return tc.env[name] },
+				stderr: stderr,
+			}
+
+			chpwdShell = "bash"
+			chpwdExportEnv = true
+
+			defer func() { chpwdExportEnv = false }()
+
+			out := &bytes.Buffer{}
+			cmd := &cobra.Command{}
+			cmd.SetOut(out)
+
+			runner.run(cmd, []string{})
+
+			if stderr.String() != tc.expectedNotice {
+				t.Errorf("Expected notice %q, got %q", tc.expectedNotice, stderr.String())
+			}
+
+			if out.String() != tc.expectedScript {
+				t.Errorf("Expected script %q, got %q", tc.expectedScript, out.String())
+			}
+		})
+	}
+}
+
+func TestExportLine_Fish(t *testing.T) {
+	if got := exportLine("fish", "GH_TOKEN", `it's a \ token`); got != `set -gx GH_TOKEN 'it\'s a \\ token';`+"\n" {
+		t.Errorf("Unexpected fish export: %q", got)
+	}
+
+	if got := unsetLine("fish", "GH_TOKEN"); !strings.HasPrefix(got, "set -e GH_TOKEN") {
+		t.Errorf("Unexpected fish unset: %q", got)
+	}
+}
@@ -0,0 +1,186 @@
+// cmd/commit_msg.go
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/spf13/cobra"
+)
+
+// commitMsgRunner holds dependencies for mocking.
+type commitMsgRunner struct {
+	loadConfig    func() (*config.Config, error)
+	authorIdent   func() (name, email string, err error)
+	parseTrailers func(messageFile string) ([]string, error)
+	addTrailers   func(messageFile string, trailers []string) error
+	stderr        io.Writer
+	exit          func(int)
+}
+
+// run checks the sign-off trailers in the commit message file and adds the trailers the
+// profile's sign-off mode and the current pairing session call for.
+func (r *commitMsgRunner) run(cmd *cobra.Command, args []string) {
+	messageFile := args[0]
+
+	cfg, err := r.loadConfig()
+	if err != nil {
+		r.exit(0)
+
+		return
+	}
+
+	profileName, mode := cfg.SignoffForDir(".")
+	if mode == "" && len(cfg.Pair) == 0 {
+		r.exit(0)
+
+		return
+	}
+
+	authorName, authorEmail, err := r.authorIdent()
+	if err != nil {
+		_, _ = fmt.Fprintf(r.stderr, "gitego: could not determine the commit author: %v\n", err)
+		r.exit(1)
+
+		return
+	}
+
+	trailers, err := r.parseTrailers(messageFile)
+	if err != nil {
+		_, _ = fmt.Fprintf(r.stderr, "gitego: could not read the commit message trailers: %v\n", err)
+		r.exit(1)
+
+		return
+	}
+
+	var add []string
+
+	if mode != "" {
+		signoff, ok := r.checkSignoff(cfg, profileName, mode, authorName, authorEmail, trailers)
+		if !ok {
+			_, _ = fmt.Fprintln(r.stderr, "Commit aborted by gitego.")
+			r.exit(1)
+
+			return
+		}
+
+		if signoff != "" {
+			add = append(add, signoff)
+		}
+	}
+
+	add = append(add, r.coAuthors(cfg, authorEmail, trailers)...)
+
+	if len(add) > 0 {
+		if err := r.addTrailers(messageFile, add); err != nil {
+			_, _ = fmt.Fprintf(r.stderr, "gitego: could not add trailers to the commit message: %v\n", err)
+			r.exit(1)
+
+			return
+		}
+	}
+
+	r.exit(0)
+}
+
+// checkSignoff checks the message's Signed-off-by trailers against the commit author and
+// the profile expected here. A sign-off by someone else is only accepted when the author's
+// own follows it, as when a cherry-picked commit is signed off with 'git cherry-pick -s'.
+// It returns the trailer to append, if any, and false if the commit must be rejected.
+func (r *commitMsgRunner) checkSignoff(cfg *config.Config, profileName, mode, authorName, authorEmail string,
+	trailers []string) (string, bool) {
+	profile, exists := cfg.Profiles[profileName]
+	if !exists {
+		return "", true // Rule points to a non-existent profile, let validation handle warnings.
+	}
+
+	if !strings.EqualFold(authorEmail, profile.Email) {
+		_, _ = fmt.Fprintf(r.stderr, "\n--- gitego Sign-off Check ---\n")
+		_, _ = fmt.Fprintf(r.stderr, "The commit author is '%s <%s>', but commits here are signed off as profile '%s' (%s).\n",
+			authorName, authorEmail, profileName, profile.Identity())
+
+		return "", false
+	}
+
+	signedOff := false
+	foreign := ""
+
+	for _, trailer := range trailers {
+		key, value, ok := config.ParseTrailer(trailer)
+		if !ok || !strings.EqualFold(key, config.SignedOffByTrailer) {
+			continue
+		}
+
+		if strings.EqualFold(trailerEmail(value), authorEmail) {
+			signedOff = true
+			foreign = ""
+		} else if foreign == "" {
+			foreign = trailer
+		}
+	}
+
+	switch {
+	case foreign != "":
+		_, _ = fmt.Fprintf(r.stderr, "\n--- gitego Sign-off Check ---\n")
+		_, _ = fmt.Fprintf(r.stderr, "'%s' does not match the commit author '%s <%s>'.\n", foreign, authorName, authorEmail)
+
+		return "", false
+	case signedOff:
+		return "", true
+	case mode == config.SignoffRequire:
+		_, _ = fmt.Fprintf(r.stderr, "\n--- gitego Sign-off Check ---\n")
+		_, _ = fmt.Fprintf(r.stderr, "Commits here need a '%s: %s' trailer. Commit with 'git commit -s' to add it.\n",
+			config.SignedOffByTrailer, profile.Identity())
+
+		return "", false
+	default:
+		return config.SignedOffByTrailer + ": " + profile.Identity(), true
+	}
+}
+
+// coAuthors returns a Co-authored-by trailer for each profile in the pairing session that
+// is neither the author nor already credited in the message.
+func (r *commitMsgRunner) coAuthors(cfg *config.Config, authorEmail string, trailers []string) []string {
+	credited := map[string]bool{strings.ToLower(authorEmail): true}
+
+	for _, trailer := range trailers {
+		if key, value, ok := config.ParseTrailer(trailer); ok && strings.EqualFold(key, config.CoAuthoredByTrailer) {
+			credited[strings.ToLower(trailerEmail(value))] = true
+		}
+	}
+
+	var coAuthors []string
+
+	for _, name := range cfg.Pair {
+		profile, exists := cfg.Profiles[name]
+		if !exists {
+			_, _ = fmt.Fprintf(r.stderr, "gitego: pairing profile '%s' no longer exists; skipping it.\n", name)
+
+			continue
+		}
+
+		if credited[strings.ToLower(profile.Email)] {
+			continue
+		}
+
+		credited[strings.ToLower(profile.Email)] = true
+		coAuthors = append(coAuthors, config.CoAuthoredByTrailer+": "+profile.Identity())
+	}
+
+	return coAuthors
+}
+
+// trailerEmail returns the email in a "Name <email>" trailer value.
+func trailerEmail(value string) string {
+	start := strings.Index(value, "<")
+	end := strings.LastIndex(value, ">")
+
+	if start < 0 || end < start {
+		return strings.TrimSpace(value)
+	}
+
+	return value[start+1 : end]
+}
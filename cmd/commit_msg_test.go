@@ -0,0 +1,104 @@
+// cmd/commit_msg_test.go
+
+package cmd
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/spf13/cobra"
+)
+
+func TestCommitMsgHook(t *testing.T) {
+	const author = "Work User <work@example.com>"
+
+	tests := []struct {
+		name           string
+		signoff        string
+		pair           []string
+		authorEmail    string
+		trailers       []string
+		expectedExit   int
+		expectedAdded  []string
+		expectedStderr string
+	}{
+		{name: "nothing configured", expectedExit: 0},
+		{name: "append adds the sign-off", signoff: config.SignoffAppend, expectedExit: 0,
+			expectedAdded: []string{"Signed-off-by: " + author}},
+		{name: "append keeps an existing sign-off", signoff: config.SignoffAppend,
+			trailers: []string{"Signed-off-by: " + author}, expectedExit: 0},
+		{name: "require rejects a missing sign-off", signoff: config.SignoffRequire, expectedExit: 1,
+			expectedStderr: "git commit -s"},
+		{name: "require accepts the author's sign-off", signoff: config.SignoffRequire,
+			trailers: []string{"Signed-off-by: Work User <WORK@example.com>"}, expectedExit: 0},
+		{name: "sign-off by someone else is rejected", signoff: config.SignoffAppend,
+			trailers: []string{"Signed-off-by: Pal <pal@example.com>"}, expectedExit: 1,
+			expectedStderr: "does not match the commit author"},
+		{name: "sign-off by someone else is rejected after the author's", signoff: config.SignoffRequire,
+			trailers: []string{"Signed-off-by: " + author, "Signed-off-by: Pal <pal@example.com>"}, expectedExit: 1,
+			expectedStderr: "does not match the commit author"},
+		{name: "cherry-picked sign-off followed by the author's is accepted", signoff: config.SignoffRequire,
+			trailers:     []string{"Signed-off-by: Pal <pal@example.com>", "Signed-off-by: " + author},
+			expectedExit: 0},
+		{name: "author is not the profile", signoff: config.SignoffAppend, authorEmail: "me@gmail.com",
+			expectedExit: 1, expectedStderr: "signed off as profile 'work'"},
+		{name: "pairing adds co-authors", pair: []string{"pal", "work", "gone"}, expectedExit: 0,
+			expectedAdded:  []string{"Co-authored-by: Pal <pal@example.com>"},
+			expectedStderr: "pairing profile 'gone' no longer exists"},
+		{name: "pairing skips credited co-authors", pair: []string{"pal"},
+			trailers: []string{"Co-authored-by: Pal <pal@example.com>"}, expectedExit: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				ActiveProfile: "work",
+				Profiles: map[string]*config.Profile{
+					"work": {Name: "Work User", Email: "work@example.com", Signoff: tt.signoff},
+					"pal":  {Name: "Pal", Email: "pal@example.com"},
+				},
+				Pair: tt.pair,
+			}
+
+			authorEmail := tt.authorEmail
+			if authorEmail == "" {
+				authorEmail = "work@example.com"
+			}
+
+			var added []string
+
+			var stderr bytes.Buffer
+
+			exitCode := -1
+
+			runner := &commitMsgRunner{
+				loadConfig:    func() (*config.Config, error) { return cfg, nil },
+				authorIdent:   func() (string, string, error) { return "Work User", authorEmail, nil },
+				parseTrailers: func(string) ([]string, error) { return tt.trailers, nil },
+				addTrailers: func(_ string, trailers []string) error {
+					added = trailers
+
+					return nil
+				},
+				stderr: &stderr,
+				exit:   func(code int) { exitCode = code },
+			}
+
+			runner.run(&cobra.Command{}, []string{"COMMIT_EDITMSG"})
+
+			if exitCode != tt.expectedExit {
+				t.Errorf("Expected exit code %d, got %d (stderr: %s)", tt.expectedExit, exitCode, stderr.String())
+			}
+
+			if !slices.Equal(added, tt.expectedAdded) {
+				t.Errorf("Expected trailers %q to be added, got %q", tt.expectedAdded, added)
+			}
+
+			if tt.expectedStderr != "" && !bytes.Contains(stderr.Bytes(), []byte(tt.expectedStderr)) {
+				t.Errorf("Expected stderr to contain %q, got: %s", tt.expectedStderr, stderr.String())
+			}
+		})
+	}
+}
@@ -3,9 +3,9 @@
 package cmd
 
 import (
-	"log"
 	"os"
 
+	"github.com/bgreenwell/gitego/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -54,25 +54,25 @@ var completionCmd = &cobra.Command{
 	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
 	//Args:                  cobra.ExactValidArgs(1),
 	Args: cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+
 		switch args[0] {
 		case "bash":
-			if err := cmd.Root().GenBashCompletion(os.Stdout); err != nil {
-				log.Fatalf("Failed to generate bash completion: %v", err)
-			}
+			err = cmd.Root().GenBashCompletion(os.Stdout)
 		case "zsh":
-			if err := cmd.Root().GenZshCompletion(os.Stdout); err != nil {
-				log.Fatalf("Failed to generate zsh completion: %v", err)
-			}
+			err = cmd.Root().GenZshCompletion(os.Stdout)
 		case "fish":
-			if err := cmd.Root().GenFishCompletion(os.Stdout, true); err != nil {
-				log.Fatalf("Failed to generate fish completion: %v", err)
-			}
+			err = cmd.Root().GenFishCompletion(os.Stdout, true)
 		case "powershell":
-			if err := cmd.Root().GenPowerShellCompletion(os.Stdout); err != nil {
-				log.Fatalf("Failed to generate powershell completion: %v", err)
-			}
+			err = cmd.Root().GenPowerShellCompletion(os.Stdout)
+		}
+
+		if err != nil {
+			return errors.IO(err, "could not generate %s completion", args[0])
 		}
+
+		return nil
 	},
 }
 
@@ -0,0 +1,107 @@
+// cmd/copy.go
+
+package cmd
+
+import (
+	"github.com/bgreenwell/gitego/config"
+	"github.com/spf13/cobra"
+)
+
+var copyWithSecrets bool
+
+// copyRunner holds the dependencies for the copy command for mocking.
+type copyRunner struct {
+	load       func() (*config.Config, error)
+	save       func(*config.Config) error
+	loadPolicy func(*config.Config) (*config.Policy, error)
+	vault      vaultAccess
+}
+
+// run is the core logic for the copy command.
+func (r *copyRunner) run(cmd *cobra.Command, args []string) error {
+	srcName, dstName := args[0], args[1]
+
+	cfg, err := r.load()
+	if err != nil {
+		return errLoadConfig(err)
+	}
+
+	src, exists := cfg.Profiles[srcName]
+	if !exists {
+		return errProfileNotFound(srcName)
+	}
+
+	if err := cfg.ValidateNewProfileName(dstName); err != nil {
+		return errNewProfileName(err)
+	}
+
+	profile := src.Clone()
+
+	var copied []string
+
+	if copyWithSecrets {
+		copied, err = r.vault.copySecrets(srcName, dstName, profile)
+		if err != nil {
+			r.vault.deleteSecrets(dstName, copied)
+
+			return errKeyring(err, "could not copy the keychain entries")
+		}
+	}
+
+	if err := enforcePolicy(r.loadPolicy, cfg, func(p *config.Policy) []config.PolicyViolation {
+		return p.CheckProfile(cfg, dstName, profile, len(copied) > 0 && copied[0] == config.PATSecretName)
+	}); err != nil {
+		r.vault.deleteSecrets(dstName, copied)
+
+		return err
+	}
+
+	cfg.Profiles[dstName] = profile
+
+	if err := r.save(cfg); err != nil {
+		r.vault.deleteSecrets(dstName, copied)
+
+		return errSaveConfig(err)
+	}
+
+	printSuccess("✓ Profile '%s' created from '%s'.\n", dstName, srcName)
+
+	if copyWithSecrets {
+		printSuccess("  %d keychain entry(ies) copied.\n", len(copied))
+	} else if len(secretNames(profile)) > 1 {
+		printSuccess("  Its vault secrets were not copied; set them with 'gitego edit --secret-env' or copy with --with-secrets.\n")
+	}
+
+	printSuccess("Use 'gitego edit %s' to change its name, email or token.\n", dstName)
+
+	return nil
+}
+
+// copyCmd represents the copy command.
+var copyCmd = &cobra.Command{
+	Use:   "copy <source_profile> <new_profile>",
+	Short: "Creates a new profile from an existing one.",
+	Long: `Creates a new profile with every setting of an existing one, including the
+profile it extends. Auto-switch rules stay with the original profile.
+
+The token and vault secrets are not copied unless --with-secrets is given.`,
+	Example: `  gitego copy client-abc client-xyz
+  gitego edit client-xyz --email me@client-xyz.com --pat ghp_...`,
+	Aliases: []string{"cp"},
+	Args:    cobra.ExactArgs(exactArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &copyRunner{
+			load:       config.Load,
+			save:       func(c *config.Config) error { return c.Save() },
+			loadPolicy: config.LoadPolicy,
+			vault:      defaultVault,
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(copyCmd)
+
+	copyCmd.Flags().BoolVar(&copyWithSecrets, "with-secrets", false, "Also copy the profile's token and vault secrets")
+}
@@ -0,0 +1,68 @@
+// cmd/copy_test.go
+
+package cmd
+
+import (
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+)
+
+func TestCopyCommand(t *testing.T) {
+	cfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"acme": {Name: "Jane", Email: "jane@acme.com", SSHKey: "~/.ssh/id_acme",
+				Env: map[string]string{"NPM_TOKEN": "vault:NPM_TOKEN"}},
+		},
+		AutoRules: []*config.AutoRule{{Path: "/src/acme/", Profile: "acme"}},
+	}
+	entries := map[string]string{"acme/pat": "ghp_acme", "acme/NPM_TOKEN": "npm_acme"}
+
+	runner := &copyRunner{
+		load:  func() (*config.Config, error) { return cfg, nil },
+		save:  func(*config.Config) error { return nil },
+		vault: mockVault(entries),
+	}
+
+	defer func() { copyWithSecrets = false }()
+
+	err := runner.run(copyCmd, []string{"acme", "acme"})
+	if errors.KindOf(err) != errors.KindAlreadyExists || !strings.Contains(err.Error(), "profile 'acme' already exists") {
+		t.Errorf("Expected copying onto an existing profile to fail, got: %v", err)
+	}
+
+	err = runner.run(copyCmd, []string{"acme", "../acme"})
+	if errors.KindOf(err) != errors.KindInvalidInput || !strings.Contains(err.Error(), "not a valid profile name") {
+		t.Errorf("Expected an invalid profile name to be rejected, got: %v", err)
+	}
+
+	output := captureOutput(t, "", func() { runner.run(copyCmd, []string{"acme", "globex"}) })
+
+	globex := cfg.Profiles["globex"]
+	if globex == nil || globex.SSHKey != "~/.ssh/id_acme" || len(cfg.AutoRules) != 1 {
+		t.Fatalf("Expected 'globex' to be created without rules, got %+v, output: %s", globex, output)
+	}
+
+	globex.Env["NPM_TOKEN"] = "changed"
+
+	if cfg.Profiles["acme"].Env["NPM_TOKEN"] != "vault:NPM_TOKEN" {
+		t.Error("Expected the copy to share no env map with its source")
+	}
+
+	if len(entries) != 2 || !strings.Contains(output, "vault secrets were not copied") {
+		t.Errorf("Expected no secrets to be copied by default, got %v, output: %s", entries, output)
+	}
+
+	copyWithSecrets = true
+
+	captureOutput(t, "", func() { runner.run(copyCmd, []string{"acme", "initech"}) })
+
+	expected := map[string]string{"acme/pat": "ghp_acme", "acme/NPM_TOKEN": "npm_acme",
+		"initech/pat": "ghp_acme", "initech/NPM_TOKEN": "npm_acme"}
+	if !maps.Equal(entries, expected) {
+		t.Errorf("Expected the secrets to be copied with --with-secrets, got %v", entries)
+	}
+}
@@ -0,0 +1,283 @@
+// cmd/doctor.go
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/bgreenwell/gitego/utils"
+	"github.com/spf13/cobra"
+)
+
+// doctorCheck is the outcome of a single doctor check.
+type doctorCheck struct {
+	ok      bool
+	message string
+}
+
+// doctorRunner holds the dependencies for the doctor command for mocking.
+type doctorRunner struct {
+	load            func() (*config.Config, error)
+	getGlobalGitAll func(string) ([]string, error)
+	listGPGKeys     func() ([]utils.GPGKey, error)
+	stat            func(string) (os.FileInfo, error)
+	getToken        func(string) (string, error)
+	loadPolicy      func(*config.Config) (*config.Policy, error)
+}
+
+// run is the core logic for the doctor command. It returns an error if any check fails.
+func (r *doctorRunner) run(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	cfg, err := r.load()
+	if err != nil {
+		return errLoadConfig(err)
+	}
+
+	var checks []doctorCheck
+
+	checks = append(checks, r.checkCredentialHelper())
+	checks = append(checks, r.checkProfiles(cfg)...)
+	checks = append(checks, r.checkRules(cfg)...)
+	checks = append(checks, r.checkSigningKeys(cfg)...)
+	checks = append(checks, r.checkPolicy(cfg)...)
+
+	problems := 0
+
+	for _, check := range checks {
+		marker := "✓"
+		if !check.ok {
+			marker = "✗"
+			problems++
+		}
+
+		_, _ = fmt.Fprintf(out, "%s %s\n", marker, check.message)
+	}
+
+	if problems > 0 {
+		return errors.General(nil, "%d problem(s) found", problems)
+	}
+
+	_, _ = fmt.Fprintln(out, "\nNo problems found.")
+
+	return nil
+}
+
+func (r *doctorRunner) checkCredentialHelper() doctorCheck {
+	helpers, err := r.getGlobalGitAll("credential.helper")
+	if err != nil {
+		return doctorCheck{false, fmt.Sprintf("Could not read credential.helper: %v", err)}
+	}
+
+	for _, helper := range helpers {
+		if strings.Contains(helper, "gitego credential") {
+			return doctorCheck{true, "gitego is configured as a Git credential helper."}
+		}
+	}
+
+	return doctorCheck{false, "gitego is not a Git credential helper. Run: " +
+		"git config --global --add credential.helper '!gitego credential'"}
+}
+
+func (r *doctorRunner) checkProfiles(cfg *config.Config) []doctorCheck {
+	var checks []doctorCheck
+
+	if cfg.ActiveProfile != "" {
+		if _, exists := cfg.Profiles[cfg.ActiveProfile]; !exists {
+			checks = append(checks, doctorCheck{false,
+				fmt.Sprintf("Active profile '%s' does not exist.", cfg.ActiveProfile)})
+		}
+	}
+
+	for _, name := range sortedProfileNames(cfg) {
+		profile := cfg.Profiles[name]
+		if profile.SSHKey == "" {
+			continue
+		}
+
+		if _, err := r.stat(config.ExpandHome(profile.SSHKey)); err != nil {
+			checks = append(checks, doctorCheck{false,
+				fmt.Sprintf("Profile '%s': SSH key '%s' is not readable: %v", name, profile.SSHKey, err)})
+		} else {
+			checks = append(checks, doctorCheck{true, fmt.Sprintf("Profile '%s': SSH key found.", name)})
+		}
+	}
+
+	// Keys are checked above and by checkSigningKeys.
+	for _, err := range cfg.ValidateProfiles() {
+		switch {
+		case err.Field == "ssh_key" || err.Field == "signing_key":
+			continue
+		case err.Field == config.FieldProfileName:
+			checks = append(checks, doctorCheck{false, fmt.Sprintf("%v.", err)})
+		default:
+			_, problem, _ := strings.Cut(err.Error(), ": ")
+			checks = append(checks, doctorCheck{false, fmt.Sprintf("Profile '%s': %s.", err.Profile, problem)})
+		}
+	}
+
+	return checks
+}
+
+func (r *doctorRunner) checkRules(cfg *config.Config) []doctorCheck {
+	var checks []doctorCheck
+
+	for _, rule := range cfg.AutoRules {
+		if _, exists := cfg.Profiles[rule.Profile]; !exists {
+			checks = append(checks, doctorCheck{false,
+				fmt.Sprintf("Auto-rule for '%s' points to missing profile '%s'.", rule.Path, rule.Profile)})
+
+			continue
+		}
+
+		if _, err := r.stat(config.ProfileGitconfigPath(rule.Profile)); err != nil {
+			checks = append(checks, doctorCheck{false,
+				fmt.Sprintf("Auto-rule for '%s': profile gitconfig for '%s' is missing. Re-run 'gitego auto'.",
+					rule.Path, rule.Profile)})
+
+			continue
+		}
+
+		checks = append(checks, doctorCheck{true,
+			fmt.Sprintf("Auto-rule for '%s' uses profile '%s'.", rule.Path, rule.Profile)})
+	}
+
+	return checks
+}
+
+func (r *doctorRunner) checkSigningKeys(cfg *config.Config) []doctorCheck {
+	var checks []doctorCheck
+
+	var gpgKeys []utils.GPGKey
+
+	var gpgErr error
+
+	gpgLoaded := false
+
+	for _, name := range sortedProfileNames(cfg) {
+		profile := cfg.Profiles[name]
+
+		switch profile.SigningMode() {
+		case config.SigningSSH:
+			if _, err := config.SSHSigningPublicKey(profile.SigningKey); err != nil {
+				checks = append(checks, doctorCheck{false,
+					fmt.Sprintf("Profile '%s': SSH signing key is not readable: %v", name, err)})
+			} else {
+				checks = append(checks, doctorCheck{true, fmt.Sprintf("Profile '%s': SSH signing key found.", name)})
+			}
+		case config.SigningGPG:
+			if profile.SigningKey == "" {
+				continue
+			}
+
+			if !gpgLoaded {
+				gpgKeys, gpgErr = r.listGPGKeys()
+				gpgLoaded = true
+			}
+
+			if gpgErr != nil {
+				checks = append(checks, doctorCheck{false,
+					fmt.Sprintf("Profile '%s': could not list GPG keys: %v", name, gpgErr)})
+
+				continue
+			}
+
+			checks = append(checks, validateGPGCheck(name, profile, gpgKeys))
+		}
+	}
+
+	return checks
+}
+
+func (r *doctorRunner) checkPolicy(cfg *config.Config) []doctorCheck {
+	if r.loadPolicy == nil {
+		return nil
+	}
+
+	policy, err := r.loadPolicy(cfg)
+	if err != nil {
+		return []doctorCheck{{false, fmt.Sprintf("Could not load team policy: %v", err)}}
+	}
+
+	if policy.IsEmpty() {
+		return nil
+	}
+
+	checks := []doctorCheck{{true, fmt.Sprintf("Team policy loaded from %s.", strings.Join(policy.Sources, ", "))}}
+
+	for _, name := range sortedProfileNames(cfg) {
+		token, err := r.getToken(name)
+
+		violations := policy.CheckProfile(cfg, name, cfg.Profiles[name], err == nil && token != "")
+		if len(violations) == 0 {
+			checks = append(checks, doctorCheck{true, fmt.Sprintf("Profile '%s' complies with the team policy.", name)})
+
+			continue
+		}
+
+		for _, violation := range violations {
+			checks = append(checks, doctorCheck{false, fmt.Sprintf("Profile '%s': %s", name, violation)})
+		}
+	}
+
+	return checks
+}
+
+func validateGPGCheck(name string, profile *config.Profile, keys []utils.GPGKey) doctorCheck {
+	key, err := utils.FindGPGKey(keys, profile.SigningKey)
+	if err == nil {
+		err = key.Validate(profile.Email, time.Now())
+	}
+
+	if err != nil {
+		return doctorCheck{false, fmt.Sprintf("Profile '%s': GPG signing key is not usable: %v", name, err)}
+	}
+
+	return doctorCheck{true, fmt.Sprintf("Profile '%s': GPG signing key %s is valid.", name, key.KeyID)}
+}
+
+// sortedProfileNames returns the config's profile names in alphabetical order.
+func sortedProfileNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// doctorCmd represents the doctor command.
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Checks your gitego setup for common problems.",
+	Long: `Runs a series of checks against your gitego configuration: the Git
+credential helper, profile fields and SSH keys, auto-switch rules and their generated
+gitconfig files, signing keys, and the team policy if one is installed. GPG
+signing keys are checked against your keyring for existence, expiry, revocation
+and a user ID matching the profile's email. Exits with a non-zero status if any
+check fails.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &doctorRunner{
+			load:            config.Load,
+			getGlobalGitAll: utils.GetGlobalGitConfigAll,
+			listGPGKeys:     utils.ListGPGSecretKeys,
+			stat:            os.Stat,
+			getToken:        config.GetToken,
+			loadPolicy:      config.LoadPolicy,
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
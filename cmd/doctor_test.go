@@ -0,0 +1,138 @@
+// cmd/doctor_test.go
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/bgreenwell/gitego/utils"
+	"github.com/spf13/cobra"
+)
+
+// runDoctorTest executes the doctor command with mocks and returns its exit code and its
+// output followed by any error it printed.
+func runDoctorTest(t *testing.T, cfg *config.Config, helpers []string) (int, string) {
+	t.Helper()
+
+	return runDoctorPolicyTest(t, cfg, helpers, nil)
+}
+
+// runDoctorPolicyTest is runDoctorTest with a team policy installed.
+func runDoctorPolicyTest(t *testing.T, cfg *config.Config, helpers []string, policy *config.Policy) (int, string) {
+	t.Helper()
+
+	runner := &doctorRunner{
+		load:            func() (*config.Config, error) { return cfg, nil },
+		getGlobalGitAll: func(string) ([]string, error) { return helpers, nil },
+		listGPGKeys: func() ([]utils.GPGKey, error) {
+			return []utils.GPGKey{
+				{KeyID: "AAAA1111", Fingerprint: "FFFFAAAA1111", UIDs: []string{"Work <work@example.com>"}},
+			}, nil
+		},
+		stat: func(path string) (os.FileInfo, error) {
+			if strings.Contains(path, "missing") {
+				return nil, errors.New("no such file")
+			}
+
+			return nil, nil
+		},
+	}
+
+	if policy != nil {
+		runner.getToken = func(string) (string, error) { return "ghp_stored", nil }
+		runner.loadPolicy = func(*config.Config) (*config.Policy, error) { return policy, nil }
+	}
+
+	var buf bytes.Buffer
+
+	doctorTestCmd := &cobra.Command{}
+	doctorTestCmd.SetOut(&buf)
+
+	err := runner.run(doctorTestCmd, []string{})
+	if err != nil {
+		printError(&buf, err)
+	}
+
+	return errors.ExitCode(err), buf.String()
+}
+
+func TestDoctorCommand(t *testing.T) {
+	t.Run("healthy setup", func(t *testing.T) {
+		cfg := &config.Config{
+			Profiles: map[string]*config.Profile{
+				"work": {Email: "work@example.com", SigningKey: "AAAA1111", SSHKey: "~/.ssh/id_work"},
+			},
+			AutoRules: []*config.AutoRule{{Path: "/src/work/", Profile: "work"}},
+		}
+
+		exitCode, output := runDoctorTest(t, cfg, []string{"!gitego credential"})
+		if exitCode != 0 {
+			t.Errorf("Expected exit code 0, got %d.\nOutput:\n%s", exitCode, output)
+		}
+
+		if !strings.Contains(output, "GPG signing key AAAA1111 is valid") {
+			t.Errorf("Expected the GPG key to be reported valid.\nOutput:\n%s", output)
+		}
+	})
+
+	t.Run("broken setup", func(t *testing.T) {
+		cfg := &config.Config{
+			Profiles: map[string]*config.Profile{
+				"work":     {Email: "someone-else@example.com", SigningKey: "AAAA1111", SSHKey: "~/.ssh/missing"},
+				"personal": {Email: "jane at home"},
+			},
+			AutoRules: []*config.AutoRule{{Path: "/src/client/", Profile: "client"}},
+		}
+
+		exitCode, output := runDoctorTest(t, cfg, nil)
+		if exitCode != 1 {
+			t.Errorf("Expected exit code 1, got %d.\nOutput:\n%s", exitCode, output)
+		}
+
+		for _, want := range []string{
+			"not a Git credential helper",
+			"SSH key '~/.ssh/missing' is not readable",
+			"points to missing profile 'client'",
+			"has no user ID for 'someone-else@example.com'",
+			"Profile 'personal': email 'jane at home' is not a valid email address.",
+			"5 problem(s) found",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("Expected output to contain %q.\nOutput:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("policy violations", func(t *testing.T) {
+		cfg := &config.Config{
+			Profiles: map[string]*config.Profile{
+				"work": {Email: "work@example.com", SSHKey: "~/.ssh/id_work"},
+			},
+			AutoRules: []*config.AutoRule{{Path: "/src/work/", Profile: "work"}},
+		}
+		policy := &config.Policy{
+			Sources: []string{"/etc/gitego/policy.yaml"},
+			Rules:   []config.PolicyRule{{Path: "/src/work", RequireSigning: true, RequireSSH: true}},
+		}
+
+		exitCode, output := runDoctorPolicyTest(t, cfg, []string{"!gitego credential"}, policy)
+		if exitCode != 1 {
+			t.Errorf("Expected exit code 1, got %d.\nOutput:\n%s", exitCode, output)
+		}
+
+		for _, want := range []string{
+			"Team policy loaded from /etc/gitego/policy.yaml",
+			"commits must be signed",
+			"a stored personal access token is not allowed",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("Expected output to contain %q.\nOutput:\n%s", want, output)
+			}
+		}
+	})
+}
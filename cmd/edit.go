@@ -3,45 +3,70 @@
 package cmd
 
 import (
-	"fmt"
+	"strings"
 
 	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/bgreenwell/gitego/utils"
 	"github.com/spf13/cobra"
 )
 
 var (
 	// These variables will hold the values from the flags for the 'edit' command.
-	editName       string
-	editEmail      string
-	editUsername   string
-	editSSHKey     string
-	editSigningKey string
-	editPAT        string
+	editName          string
+	editEmail         string
+	editUsername      string
+	editSSHKey        string
+	editSSHUser       string
+	editSSHPort       int
+	editSSHAgent      string
+	editSigningKey    string
+	editSigningFormat string
+	editSignoff       string
+	editExtends       string
+	editPAT           string
+	editEnv           []string
+	editSecretEnv     []string
+	editUnsetEnv      []string
+	editForce         bool
 )
 
 // editor holds the dependencies for the edit command for mocking.
 type editor struct {
-	load     func() (*config.Config, error)
-	save     func(*config.Config) error
-	setToken func(string, string) error
+	load        func() (*config.Config, error)
+	save        func(*config.Config) error
+	setToken    func(string, string) error
+	listGPGKeys func() ([]utils.GPGKey, error)
+	loadPolicy  func(*config.Config) (*config.Policy, error)
+	setSecret   func(profileName, name, value string) error
 }
 
 // run is the core logic for the edit command.
-func (e *editor) run(cmd *cobra.Command, args []string) {
+func (e *editor) run(cmd *cobra.Command, args []string) error {
 	profileName := args[0]
 
 	cfg, err := e.load()
 	if err != nil {
-		fmt.Printf("Error loading configuration: %v\n", err)
-
-		return
+		return errLoadConfig(err)
 	}
 
 	profile, exists := cfg.Profiles[profileName]
 	if !exists {
-		fmt.Printf("Error: Profile '%s' not found.\n", profileName)
+		return errProfileNotFound(profileName)
+	}
+
+	// A new base comes first, so that fields set alongside it override what it provides.
+	if cmd.Flags().Changed("extends") {
+		previous := profile.Extends
+		profile.Extends = editExtends
+
+		if _, err := cfg.ExtendsChain(profileName); err != nil {
+			profile.Extends = previous
 
-		return
+			return errors.InvalidInput("%v", err)
+		}
+
+		cfg.ResolveProfiles()
 	}
 
 	// Update fields only if the corresponding flag was set by the user.
@@ -61,27 +86,127 @@ func (e *editor) run(cmd *cobra.Command, args []string) {
 		profile.SSHKey = editSSHKey
 	}
 
+	if cmd.Flags().Changed("ssh-user") {
+		profile.SSHUser = editSSHUser
+	}
+
+	if cmd.Flags().Changed("ssh-port") {
+		profile.SSHPort = editSSHPort
+	}
+
+	if cmd.Flags().Changed("ssh-agent") {
+		profile.SSHIdentityAgent = editSSHAgent
+	}
+
 	if cmd.Flags().Changed("signing-key") {
 		profile.SigningKey = editSigningKey
 	}
 
+	if cmd.Flags().Changed("signing-format") {
+		if editSigningFormat != "" && !config.IsValidSigningMode(editSigningFormat) {
+			return errors.InvalidInput("invalid signing format '%s'", editSigningFormat).
+				WithHint("Use one of: %s.", strings.Join(config.SigningModes, ", "))
+		}
+
+		profile.SigningFormat = editSigningFormat
+	}
+
+	if cmd.Flags().Changed("signoff") {
+		if editSignoff != "" && !config.IsValidSignoffMode(editSignoff) {
+			return errors.InvalidInput("invalid sign-off mode '%s'", editSignoff).
+				WithHint("Use one of: %s.", strings.Join(config.SignoffModes, ", "))
+		}
+
+		profile.Signoff = editSignoff
+	}
+
+	signingChanged := cmd.Flags().Changed("signing-key") || cmd.Flags().Changed("signing-format") ||
+		cmd.Flags().Changed("email")
+	if signingChanged {
+		if err := resolveGPGSigningKey(e.listGPGKeys, profile); err != nil {
+			return errors.InvalidInput("invalid GPG signing key: %v", err)
+		}
+	}
+
+	if err := checkProfile(profileName, profile, editForce); err != nil {
+		return err
+	}
+
+	secrets, err := updateEnv(profile)
+	if err != nil {
+		return err
+	}
+
+	hasToken := cmd.Flags().Changed("pat") && editPAT != ""
+	if err := enforcePolicy(e.loadPolicy, cfg, func(p *config.Policy) []config.PolicyViolation {
+		return p.CheckProfile(cfg, profileName, profile, hasToken)
+	}); err != nil {
+		return err
+	}
+
 	// Save the updated configuration.
 	if err := e.save(cfg); err != nil {
-		fmt.Printf("Error saving configuration: %v\n", err)
-
-		return
+		return errSaveConfig(err)
 	}
 
 	// If a new PAT was provided, update it in the secure keychain.
 	if cmd.Flags().Changed("pat") {
 		if err := e.setToken(profileName, editPAT); err != nil {
-			fmt.Printf("Warning: Profile updated, but failed to store new PAT securely: %v\n", err)
+			return errKeyring(err, "profile updated, but the new PAT could not be stored securely")
+		}
+	}
+
+	// Secrets are stored only once the profile referring to them has been saved.
+	for _, key := range sortedKeys(secrets) {
+		if err := e.setSecret(profileName, key, secrets[key]); err != nil {
+			return errKeyring(err, "profile updated, but the secret for %s could not be stored", key)
+		}
+	}
+
+	printSuccess("✓ Profile '%s' updated successfully.\n", profileName)
+
+	return nil
+}
+
+// updateEnv applies the --env, --secret-env and --unset-env flags to the profile. Only a
+// reference to each secret value is kept in the profile; the values are returned by
+// variable name, to be stored in the vault once the profile is saved.
+func updateEnv(profile *config.Profile) (map[string]string, error) {
+	set := make(map[string]string)
+	secrets := make(map[string]string)
 
-			return
+	for _, assignment := range editEnv {
+		key, value, err := parseEnvAssignment(assignment)
+		if err != nil {
+			return nil, err
 		}
+
+		set[key] = value
+	}
+
+	for _, assignment := range editSecretEnv {
+		key, value, err := parseEnvAssignment(assignment)
+		if err != nil {
+			return nil, err
+		}
+
+		secrets[key] = value
+		set[key] = config.SecretRef(key)
+	}
+
+	if len(set) > 0 && profile.Env == nil {
+		profile.Env = make(map[string]string)
 	}
 
-	fmt.Printf("✓ Profile '%s' updated successfully.\n", profileName)
+	for key, value := range set {
+		profile.Env[key] = value
+	}
+
+	for _, key := range editUnsetEnv {
+		delete(profile.Env, key)
+	}
+
+	return secrets, nil
 }
 
 // editCmd represents the edit command.
@@ -90,15 +215,26 @@ var editCmd = &cobra.Command{
 	Short: "Edits an existing user profile.",
 	Long: `Edits an existing user profile. You can update the user name, email,
 username, SSH key, or Personal Access Token (PAT).
-Only the flags you provide will be updated.`,
+Only the flags you provide will be updated.
+
+Environment variables for 'gitego env' and 'gitego exec' are set with --env
+KEY=VALUE. Use --secret-env KEY=VALUE for tokens: the value is stored in
+gitego's vault and the profile only records a vault:KEY reference. An --env
+value of vault:pat refers to the profile's Personal Access Token.
+
+The edited profile is validated like 'gitego add' does; --force saves it
+even if its email, username or keys fail validation.`,
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		e := &editor{
-			load:     config.Load,
-			save:     func(c *config.Config) error { return c.Save() },
-			setToken: config.SetToken,
+			load:        config.Load,
+			save:        func(c *config.Config) error { return c.Save() },
+			setToken:    config.SetToken,
+			listGPGKeys: utils.ListGPGSecretKeys,
+			loadPolicy:  config.LoadPolicy,
+			setSecret:   config.SetSecret,
 		}
-		e.run(cmd, args)
+		return e.run(cmd, args)
 	},
 }
 
@@ -110,6 +246,20 @@ func init() {
 	editCmd.Flags().StringVarP(&editEmail, "email", "e", "", "The new user.email for the profile")
 	editCmd.Flags().StringVar(&editUsername, "username", "", "The new login username for the service")
 	editCmd.Flags().StringVar(&editSSHKey, "ssh-key", "", "The new path to the SSH key for this profile")
-	editCmd.Flags().StringVar(&editSigningKey, "signing-key", "", "The new GPG key ID or SSH key path for commit signing")
+	editCmd.Flags().StringVar(&editSSHUser, "ssh-user", "", "The new SSH login user for this profile's hosts")
+	editCmd.Flags().IntVar(&editSSHPort, "ssh-port", 0, "The new SSH port for this profile's hosts")
+	editCmd.Flags().StringVar(&editSSHAgent, "ssh-agent", "", "The new IdentityAgent socket for this profile, or 'none'")
+	editCmd.Flags().StringVar(&editSigningKey, "signing-key", "", "The new GPG key ID, 'auto' to pick a GPG key by email, or SSH key path for commit signing")
+	editCmd.Flags().StringVar(&editSigningFormat, "signing-format", "",
+		"The new commit signing mode: gpg, ssh, x509 or none")
+	editCmd.Flags().StringVar(&editExtends, "extends", "",
+		"The new base profile to inherit empty fields from, or empty to keep the inherited values as the profile's own")
+	editCmd.Flags().StringVar(&editSignoff, "signoff", "",
+		"The new Signed-off-by mode for this profile's commits: append, require, or empty to turn it off")
 	editCmd.Flags().StringVar(&editPAT, "pat", "", "The new Personal Access Token for this profile")
+	editCmd.Flags().StringArrayVar(&editEnv, "env", nil, "Set an environment variable, as KEY=VALUE (repeatable)")
+	editCmd.Flags().StringArrayVar(&editSecretEnv, "secret-env", nil,
+		"Set an environment variable whose value is stored in the vault, as KEY=VALUE (repeatable)")
+	editCmd.Flags().StringArrayVar(&editUnsetEnv, "unset-env", nil, "Remove an environment variable (repeatable)")
+	editCmd.Flags().BoolVar(&editForce, "force", false, "Save the profile even if its email, username or keys fail validation")
 }
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/spf13/cobra"
 )
 
 // setupEditTestConfig creates a mock config for edit command testing.
@@ -108,3 +110,53 @@ func validateEditCommandResults(t *testing.T, mockCfg *config.Config, patSetForP
 		t.Error("Expected SetToken to be called with the new PAT for the 'work' profile.")
 	}
 }
+
+func TestEditCommand_Env(t *testing.T) {
+	mockCfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"work": {Name: "Work", Email: "work@corp.com", Env: map[string]string{"OLD": "1"}},
+		},
+	}
+
+	secrets := make(map[string]string)
+
+	runner := &editor{
+		load: func() (*config.Config, error) { return mockCfg, nil },
+		save: func(*config.Config) error { return nil },
+		setSecret: func(profileName, name, value string) error {
+			secrets[profileName+"/"+name] = value
+
+			return nil
+		},
+	}
+
+	editEnv = []string{"GH_HOST=github.corp.com"}
+	editSecretEnv = []string{"GITLAB_TOKEN=glpat_456"}
+	editUnsetEnv = []string{"OLD"}
+
+	defer func() {
+		editEnv, editSecretEnv, editUnsetEnv = nil, nil, nil
+	}()
+
+	// Nothing reaches the vault if the profile referring to it can't be saved.
+	runner.save = func(*config.Config) error { return errors.New("disk full") }
+
+	if err := runner.run(&cobra.Command{}, []string{"work"}); err == nil || len(secrets) != 0 {
+		t.Fatalf("Expected a failed save to store no secrets, got %v and %v", err, secrets)
+	}
+
+	runner.save = func(*config.Config) error { return nil }
+
+	if err := runner.run(&cobra.Command{}, []string{"work"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	env := mockCfg.Profiles["work"].Env
+	if len(env) != 2 || env["GH_HOST"] != "github.corp.com" || env["GITLAB_TOKEN"] != "vault:GITLAB_TOKEN" {
+		t.Errorf("Unexpected profile env: %v", env)
+	}
+
+	if secrets["work/GITLAB_TOKEN"] != "glpat_456" {
+		t.Errorf("Expected the secret to be stored in the vault, got %v", secrets)
+	}
+}
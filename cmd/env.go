@@ -0,0 +1,119 @@
+// cmd/env.go
+
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envProfile string
+	envShell   string
+)
+
+// envRunner holds the dependencies for the env command for mocking.
+type envRunner struct {
+	load      func() (*config.Config, error)
+	getSecret func(profileName, name string) (string, error)
+}
+
+// run is the core logic for the env command.
+func (r *envRunner) run(cmd *cobra.Command, args []string) error {
+	if envShell != "bash" && envShell != "zsh" && envShell != "fish" {
+		return errors.InvalidInput("unsupported shell '%s'", envShell).WithHint("Use bash, zsh or fish.")
+	}
+
+	_, env, err := resolveProfileEnv(r.load, r.getSecret, envProfile)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		_, _ = fmt.Fprint(cmd.OutOrStdout(), exportLine(envShell, key, env[key]))
+	}
+
+	return nil
+}
+
+// resolveProfileEnv returns the named profile, or the one expected in the current
+// directory, with its environment resolved from the vault.
+func resolveProfileEnv(
+	load func() (*config.Config, error),
+	getSecret func(string, string) (string, error),
+	profileName string,
+) (string, map[string]string, error) {
+	cfg, err := load()
+	if err != nil {
+		return "", nil, errLoadConfig(err)
+	}
+
+	if profileName == "" {
+		profileName, _ = cfg.GetActiveProfileForCurrentDir()
+	}
+
+	if profileName == "" {
+		return "", nil, errors.NotFound("no profile applies here").WithHint("Choose one with --profile.")
+	}
+
+	profile, exists := cfg.Profiles[profileName]
+	if !exists {
+		return "", nil, errProfileNotFound(profileName)
+	}
+
+	env, err := profile.ResolveEnv(profileName, getSecret)
+	if err != nil {
+		return "", nil, errKeyring(err, "could not resolve the environment of '%s'", profileName)
+	}
+
+	return profileName, env, nil
+}
+
+// parseEnvAssignment splits a KEY=VALUE flag value and validates the key.
+func parseEnvAssignment(assignment string) (string, string, error) {
+	key, value, found := strings.Cut(assignment, "=")
+	if !found {
+		return "", "", errors.InvalidInput("expected KEY=VALUE, got '%s'", assignment)
+	}
+
+	if !config.IsValidEnvName(key) {
+		return "", "", errors.InvalidInput("'%s' is not a valid environment variable name", key)
+	}
+
+	return key, value, nil
+}
+
+// envCmd represents the env command.
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Prints export statements for a profile's environment variables.",
+	Long: `Prints shell statements that export the environment variables of the profile
+expected in the current directory, or of the one given with --profile, with
+secret references resolved from gitego's vault. Load them into your shell with:
+
+  $ eval "$(gitego env)"
+
+Set a profile's variables with 'gitego edit <profile> --env KEY=VALUE', or
+--secret-env for values that must stay out of config.yaml.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &envRunner{
+			load:      config.Load,
+			getSecret: config.GetSecret,
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+
+	envCmd.Flags().StringVar(&envProfile, "profile", "", "Use this profile instead of the one expected here")
+	envCmd.Flags().StringVar(&envShell, "shell", "bash", "Shell to print statements for: bash, zsh or fish")
+}
@@ -0,0 +1,56 @@
+// cmd/env_test.go
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/spf13/cobra"
+)
+
+func TestEnvCommand(t *testing.T) {
+	runner := &envRunner{
+		load: func() (*config.Config, error) {
+			return &config.Config{
+				Profiles: map[string]*config.Profile{
+					"work": {Email: "work@corp.com", Env: map[string]string{
+						"GH_HOST":  "github.corp.com",
+						"GH_TOKEN": "vault:pat",
+					}},
+				},
+				ActiveProfile: "work",
+			}, nil
+		},
+		getSecret: func(profileName, name string) (string, error) { return profileName + "-" + name, nil },
+	}
+
+	envShell = "bash"
+
+	defer func() { envShell = "bash" }()
+
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	runner.run(cmd, []string{})
+
+	expected := "export GH_HOST='github.corp.com';\nexport GH_TOKEN='work-pat';\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestParseEnvAssignment(t *testing.T) {
+	key, value, err := parseEnvAssignment("NPM_TOKEN=a=b")
+	if err != nil || key != "NPM_TOKEN" || value != "a=b" {
+		t.Errorf("Unexpected result: %q %q %v", key, value, err)
+	}
+
+	for _, bad := range []string{"NPM_TOKEN", "NPM-TOKEN=x", "=x"} {
+		if _, _, err := parseEnvAssignment(bad); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+}
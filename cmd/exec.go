@@ -0,0 +1,98 @@
+// cmd/exec.go
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/spf13/cobra"
+)
+
+var execProfile string
+
+// execRunner holds the dependencies for the exec command for mocking.
+type execRunner struct {
+	load      func() (*config.Config, error)
+	getSecret func(profileName, name string) (string, error)
+	runCmd    func(name string, args []string, env []string) (int, error)
+	environ   func() []string
+	exit      func(int)
+}
+
+// run is the core logic for the exec command. Once the command has run, gitego exits
+// with its exit code.
+func (r *execRunner) run(cmd *cobra.Command, args []string) error {
+	_, env, err := resolveProfileEnv(r.load, r.getSecret, execProfile)
+	if err != nil {
+		return err
+	}
+
+	// Later entries win, so the profile's variables override inherited ones.
+	environ := r.environ()
+	for key, value := range env {
+		environ = append(environ, key+"="+value)
+	}
+
+	code, err := r.runCmd(args[0], args[1:], environ)
+	if err != nil {
+		return errors.General(err, "could not run '%s'", args[0])
+	}
+
+	r.exit(code)
+
+	return nil
+}
+
+// runWithEnv runs a command with the given environment and standard streams attached,
+// returning its exit code.
+func runWithEnv(name string, args []string, env []string) (int, error) {
+	command := exec.Command(name, args...)
+	command.Env = env
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	err := command.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+
+	return 0, err
+}
+
+// execCmd represents the exec command.
+var execCmd = &cobra.Command{
+	Use:   "exec [--profile <name>] -- <command> [args...]",
+	Short: "Runs a command with a profile's environment variables.",
+	Long: `Runs a command with the environment variables of the profile expected in the
+current directory, or of the one given with --profile, added to the current
+environment. Secret references are resolved from gitego's vault, so tokens are
+only ever held in the command's environment. The command's exit code is
+passed through.
+
+  $ gitego exec --profile work -- gh pr list`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &execRunner{
+			load:      config.Load,
+			getSecret: config.GetSecret,
+			runCmd:    runWithEnv,
+			environ:   os.Environ,
+			exit:      os.Exit,
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+
+	// Stop at the first argument, so the command's own flags aren't parsed as ours.
+	execCmd.Flags().SetInterspersed(false)
+	execCmd.Flags().StringVar(&execProfile, "profile", "", "Use this profile instead of the one expected here")
+}
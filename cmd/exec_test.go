@@ -0,0 +1,55 @@
+// cmd/exec_test.go
+
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/spf13/cobra"
+)
+
+func TestExecCommand(t *testing.T) {
+	var gotName string
+
+	var gotArgs, gotEnv []string
+
+	exitCode := -1
+
+	runner := &execRunner{
+		load: func() (*config.Config, error) {
+			return &config.Config{
+				Profiles: map[string]*config.Profile{
+					"work": {Email: "work@corp.com", Env: map[string]string{"GH_TOKEN": "vault:pat"}},
+				},
+			}, nil
+		},
+		getSecret: func(string, string) (string, error) { return "ghp_123", nil },
+		runCmd: func(name string, args []string, env []string) (int, error) {
+			gotName, gotArgs, gotEnv = name, args, env
+
+			return 3, nil
+		},
+		environ: func() []string { return []string{"PATH=/usr/bin", "GH_TOKEN=old"} },
+		exit:    func(code int) { exitCode = code },
+	}
+
+	execProfile = "work"
+
+	defer func() { execProfile = "" }()
+
+	runner.run(&cobra.Command{}, []string{"gh", "pr", "list"})
+
+	if gotName != "gh" || !slices.Equal(gotArgs, []string{"pr", "list"}) {
+		t.Errorf("Expected 'gh pr list' to run, got %s %v", gotName, gotArgs)
+	}
+
+	if gotEnv[len(gotEnv)-1] != "GH_TOKEN=ghp_123" || !slices.Contains(gotEnv, "PATH=/usr/bin") {
+		t.Errorf("Expected the profile's GH_TOKEN to override the inherited one, got %v", gotEnv)
+	}
+
+	if exitCode != 3 {
+		t.Errorf("Expected the command's exit code 3, got %d", exitCode)
+	}
+}
@@ -0,0 +1,134 @@
+// cmd/export.go
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/spf13/cobra"
+)
+
+var exportWithTokens bool
+
+// exportRunner holds the dependencies for the export command for mocking.
+type exportRunner struct {
+	load           func() (*config.Config, error)
+	getToken       func(string) (string, error)
+	getSecret      func(profileName, name string) (string, error)
+	readPassphrase func() (string, error)
+	writeBundle    func(string, *config.Bundle) error
+}
+
+// run is the core logic for the export command.
+func (r *exportRunner) run(cmd *cobra.Command, args []string) error {
+	outputPath := args[0]
+
+	cfg, err := r.load()
+	if err != nil {
+		return errLoadConfig(err)
+	}
+
+	bundle := config.NewBundle(cfg)
+	out := cmd.OutOrStdout()
+
+	if exportWithTokens {
+		tokens := r.collectTokens(cfg)
+
+		if len(tokens) == 0 {
+			_, _ = fmt.Fprintln(out, "No tokens found in the vault; exporting profiles only.")
+		} else {
+			passphrase, err := r.readPassphrase()
+			if err != nil {
+				return errors.IO(err, "could not read passphrase")
+			}
+
+			if passphrase == "" {
+				return errors.InvalidInput("a passphrase is required to export tokens")
+			}
+
+			if err := bundle.EncryptTokens(tokens, passphrase); err != nil {
+				return errors.General(err, "could not encrypt tokens")
+			}
+		}
+	}
+
+	if err := r.writeBundle(outputPath, bundle); err != nil {
+		return errors.IO(err, "could not write bundle")
+	}
+
+	fprintSuccess(out, "✓ Exported %d profile(s) and %d auto-switch rule(s) to %s\n",
+		len(bundle.Profiles), len(bundle.AutoRules), outputPath)
+
+	if bundle.Tokens != nil {
+		fprintSuccess(out, "✓ Included %d encrypted token(s) and secret(s).\n", len(bundle.Tokens.Entries))
+	}
+
+	return nil
+}
+
+// collectTokens returns the vault tokens of every profile that has one, keyed by profile
+// name, and the secrets their env refers to, keyed by config.BundleSecretKey.
+func (r *exportRunner) collectTokens(cfg *config.Config) map[string]string {
+	tokens := make(map[string]string)
+
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if token, err := r.getToken(name); err == nil && token != "" {
+			tokens[name] = token
+		}
+
+		for _, secretName := range secretNames(cfg.Profiles[name]) {
+			if secretName == config.PATSecretName {
+				continue
+			}
+
+			// Secrets a profile inherits belong to its base and are exported with it.
+			if secret, err := r.getSecret(name, secretName); err == nil && secret != "" {
+				tokens[config.BundleSecretKey(name, secretName)] = secret
+			}
+		}
+	}
+
+	return tokens
+}
+
+// exportCmd represents the export command.
+var exportCmd = &cobra.Command{
+	Use:   "export <file>",
+	Short: "Writes profiles and auto-switch rules to a portable bundle.",
+	Long: `Writes your profiles, their settings and your auto-switch rules to a
+YAML bundle that can be loaded on another machine with 'gitego import-bundle'.
+Paths inside your home directory are stored as ~/ so they resolve on the new
+machine.
+
+Tokens are left out unless --with-tokens is given, in which case they are
+encrypted with a passphrase you choose, together with the vault secrets the
+profiles' environment variables refer to.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &exportRunner{
+			load:           config.Load,
+			getToken:       config.GetToken,
+			getSecret:      config.GetSecret,
+			readPassphrase: readNewPassphrase,
+			writeBundle:    config.WriteBundle,
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().BoolVar(&exportWithTokens, "with-tokens", false,
+		"Include tokens and env secrets from the vault, encrypted with a passphrase")
+}
@@ -0,0 +1,83 @@
+// cmd/export_test.go
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/spf13/cobra"
+)
+
+func TestExportCommand(t *testing.T) {
+	mockCfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"work":     {Name: "Work User", Email: "work@corp.com"},
+			"personal": {Name: "Me", Email: "me@example.com"},
+		},
+		AutoRules: []*config.AutoRule{{Path: "/src/work/", Profile: "work"}},
+	}
+
+	var written *config.Bundle
+
+	runner := &exportRunner{
+		load: func() (*config.Config, error) { return mockCfg, nil },
+		getToken: func(name string) (string, error) {
+			if name == "work" {
+				return "ghp_work", nil
+			}
+
+			return "", errors.New("not found")
+		},
+		getSecret:      func(string, string) (string, error) { return "", config.ErrSecretNotFound },
+		readPassphrase: func() (string, error) { return "secret", nil },
+		writeBundle: func(path string, b *config.Bundle) error {
+			written = b
+
+			return nil
+		},
+	}
+
+	t.Run("without tokens", func(t *testing.T) {
+		written = nil
+		out := &bytes.Buffer{}
+		cmd := &cobra.Command{}
+		cmd.SetOut(out)
+
+		runner.run(cmd, []string{"bundle.yaml"})
+
+		if written == nil || len(written.Profiles) != 2 || len(written.AutoRules) != 1 {
+			t.Fatalf("Expected a bundle with 2 profiles and 1 rule, got %+v", written)
+		}
+
+		if written.Tokens != nil {
+			t.Error("Expected no tokens without --with-tokens.")
+		}
+
+		if !strings.Contains(out.String(), "Exported 2 profile(s)") {
+			t.Errorf("Unexpected output: %s", out.String())
+		}
+	})
+
+	t.Run("with tokens", func(t *testing.T) {
+		exportWithTokens = true
+
+		defer func() { exportWithTokens = false }()
+
+		written = nil
+
+		runner.run(&cobra.Command{}, []string{"bundle.yaml"})
+
+		if written == nil || written.Tokens == nil || len(written.Tokens.Entries) != 1 {
+			t.Fatalf("Expected one encrypted token, got %+v", written)
+		}
+
+		tokens, err := written.DecryptTokens("secret")
+		if err != nil || tokens["work"] != "ghp_work" {
+			t.Errorf("Expected the work token to decrypt, got %v (err: %v)", tokens, err)
+		}
+	})
+}
@@ -0,0 +1,412 @@
+// cmd/fix_authors.go
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/bgreenwell/gitego/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fixWriteMailmap bool
+	fixRewrite      bool
+	fixMapEmails    []string
+)
+
+// fixAuthorsRunner holds the dependencies for the fix-authors command for mocking.
+type fixAuthorsRunner struct {
+	audit     *auditRunner
+	findRoot  func() (string, error)
+	runScript func(string) error
+	stdin     io.Reader
+}
+
+// run is the core logic for the fix-authors command.
+func (r *fixAuthorsRunner) run(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	cfg, err := r.audit.load()
+	if err != nil {
+		return errLoadConfig(err)
+	}
+
+	profileName, profile, findings, err := r.audit.auditConfig(cfg, args)
+	if err != nil {
+		return err
+	}
+
+	root, err := r.findRoot()
+	if err != nil {
+		return errNotGitRepo()
+	}
+
+	if len(findings) == 0 {
+		fprintSuccess(out, "✓ All commits match profile '%s' (%s). Nothing to fix.\n", profileName, profile.Email)
+
+		return nil
+	}
+
+	own := ownEmails(cfg, profileName, fixMapEmails)
+	reader := bufio.NewReader(r.stdin)
+
+	if err := r.fixMailmap(out, reader, filepath.Join(root, ".mailmap"), profile, findings, own); err != nil {
+		return errors.IO(err, "could not update .mailmap")
+	}
+
+	return r.planRewrite(out, reader, args, profileName, profile, own)
+}
+
+// fixMailmap shows the .mailmap entries that map the user's other identities to the profile
+// and, with --write-mailmap, asks for each one before merging it into the file. Emails that
+// belong to no profile of the user, such as teammates' or bots', are never mapped.
+func (r *fixAuthorsRunner) fixMailmap(
+	out io.Writer,
+	reader *bufio.Reader,
+	path string,
+	profile *config.Profile,
+	findings []auditFinding,
+	own map[string]bool,
+) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	entries, foreign := newMailmapEntries(string(existing), profile, findings, own)
+
+	if len(foreign) > 0 {
+		_, _ = fmt.Fprintf(out, "Not mapping %d email(s) that don't belong to your profiles: %s\n",
+			len(foreign), strings.Join(foreign, ", "))
+		_, _ = fmt.Fprintln(out, "Use --map-email to map one of them anyway.")
+	}
+
+	if len(entries) == 0 {
+		fprintSuccess(out, "✓ .mailmap already maps every identity of yours.\n")
+
+		return nil
+	}
+
+	_, _ = fmt.Fprintln(out, ".mailmap entries for your other identities:")
+
+	for _, entry := range entries {
+		_, _ = fmt.Fprintf(out, "  %s\n", entry.line)
+	}
+
+	if !fixWriteMailmap {
+		_, _ = fmt.Fprintln(out, "Run with --write-mailmap to add them.")
+
+		return nil
+	}
+
+	var accepted []string
+
+	for _, entry := range entries {
+		_, _ = fmt.Fprintf(out, "Map %s to %s <%s>? [y/N]: ", entry.email, profile.Name, profile.Email)
+
+		if readYes(reader) {
+			accepted = append(accepted, entry.line)
+		}
+	}
+
+	if len(accepted) == 0 {
+		_, _ = fmt.Fprintln(out, ".mailmap left unchanged.")
+
+		return nil
+	}
+
+	content := string(existing)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	content += strings.Join(accepted, "\n") + "\n"
+
+	if err := os.WriteFile(path, []byte(content), filePermissions); err != nil {
+		return err
+	}
+
+	fprintSuccess(out, "✓ Added %d entries to %s\n", len(accepted), path)
+
+	return nil
+}
+
+// ownEmails returns the lowercased emails of the user's profiles other than profileName,
+// plus the extra emails given, that is the stray identities that may be mapped to it.
+func ownEmails(cfg *config.Config, profileName string, extra []string) map[string]bool {
+	own := make(map[string]bool)
+
+	for name, profile := range cfg.Profiles {
+		if name != profileName && profile.Email != "" {
+			own[strings.ToLower(profile.Email)] = true
+		}
+	}
+
+	for _, email := range extra {
+		own[strings.ToLower(email)] = true
+	}
+
+	if profile := cfg.Profiles[profileName]; profile != nil {
+		delete(own, strings.ToLower(profile.Email))
+	}
+
+	return own
+}
+
+// isNoreplyEmail reports whether an email is a hosting service's own address, such as the
+// noreply@github.com committer of commits made in GitHub's web interface.
+func isNoreplyEmail(email string) bool {
+	local, _, _ := strings.Cut(strings.ToLower(email), "@")
+
+	return strings.Contains(local, "noreply") || strings.Contains(local, "no-reply")
+}
+
+// planRewrite shows a script that re-authors the misattributed commits of the audited range
+// that haven't been pushed, and runs it after confirmation when --rewrite is given. Only
+// commits authored by the profile or by one of the user's own identities are rewritten, so
+// teammates' commits keep their author.
+func (r *fixAuthorsRunner) planRewrite(
+	out io.Writer,
+	reader *bufio.Reader,
+	args []string,
+	profileName string,
+	profile *config.Profile,
+	own map[string]bool,
+) error {
+	unpublished, err := r.audit.getCommits(unpublishedLogArgs(args)...)
+	if err != nil {
+		return errors.IO(err, "could not list unpublished commits")
+	}
+
+	flagged := make(map[string]bool)
+	for _, finding := range auditCommits(unpublished, profile) {
+		flagged[finding.Commit] = true
+	}
+
+	// Rebase from the parent of the oldest commit to rewrite; git log lists newest first.
+	var hashes []string
+
+	base := ""
+
+	for _, commit := range unpublished {
+		author := strings.ToLower(commit.AuthorEmail)
+		if !flagged[commit.Hash] || !own[author] && author != strings.ToLower(profile.Email) {
+			continue
+		}
+
+		hashes = append(hashes, commit.Hash)
+
+		base = "--root"
+		if len(commit.Parents) > 0 {
+			base = commit.Parents[0]
+		}
+	}
+
+	if len(hashes) == 0 {
+		_, _ = fmt.Fprintln(out, "\nNo unpublished commits of yours need rewriting; published history is left alone.")
+
+		return nil
+	}
+
+	script := rewriteScript(profileName, profile, base, auditBranch, hashes)
+
+	_, _ = fmt.Fprintf(out, "\nRewrite plan for %d unpublished commit(s):\n\n%s\n", len(hashes), script)
+
+	if !fixRewrite {
+		_, _ = fmt.Fprintln(out, "Run with --rewrite to apply it.")
+
+		return nil
+	}
+
+	_, _ = fmt.Fprint(out, "Run this plan now? [y/N]: ")
+
+	if !readYes(reader) {
+		_, _ = fmt.Fprintln(out, "Rewrite cancelled.")
+
+		return nil
+	}
+
+	if err := r.runScript(script); err != nil {
+		return errors.General(err, "rewrite failed").
+			WithHint("Resolve the rebase, or run 'git rebase --abort' to undo it.")
+	}
+
+	fprintSuccess(out, "✓ Commits re-authored.\n")
+
+	return nil
+}
+
+// mailmapEntry is a proposed .mailmap line and the stray email it maps.
+type mailmapEntry struct {
+	email string
+	line  string
+}
+
+// newMailmapEntries returns a mailmap entry for every email in findings that is one of the
+// user's own identities and that existing does not already map, and the other stray emails,
+// which are left alone. Committers such as GitHub's web-flow noreply address are ignored.
+func newMailmapEntries(
+	existing string,
+	profile *config.Profile,
+	findings []auditFinding,
+	own map[string]bool,
+) ([]mailmapEntry, []string) {
+	mapped := make(map[string]bool)
+
+	for _, line := range strings.Split(existing, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// The commit email is the last <...> on the line.
+		if start := strings.LastIndex(line, "<"); start >= 0 {
+			if end := strings.Index(line[start:], ">"); end > 0 {
+				mapped[strings.ToLower(line[start+1:start+end])] = true
+			}
+		}
+	}
+
+	var (
+		entries []mailmapEntry
+		foreign []string
+	)
+
+	for _, finding := range findings {
+		if finding.Kind == findingSignature || finding.Kind == findingCommitter && isNoreplyEmail(finding.Actual) {
+			continue
+		}
+
+		email := strings.ToLower(finding.Actual)
+		if email == "" || mapped[email] {
+			continue
+		}
+
+		mapped[email] = true
+
+		if !own[email] {
+			foreign = append(foreign, finding.Actual)
+
+			continue
+		}
+
+		entries = append(entries, mailmapEntry{
+			email: finding.Actual,
+			line:  fmt.Sprintf("%s <%s> <%s>", profile.Name, profile.Email, finding.Actual),
+		})
+	}
+
+	return entries, foreign
+}
+
+// unpublishedLogArgs returns the 'git log' arguments for the commits of the audited range,
+// HEAD by default, that no remote has.
+func unpublishedLogArgs(args []string) []string {
+	logArgs, _ := auditLogArgs(args)
+	logArgs = logArgs[:len(logArgs)-1]
+
+	if len(args) == 0 && auditBranch == "" {
+		logArgs = append(logArgs, "HEAD")
+	}
+
+	return append(logArgs, "--not", "--remotes", "--")
+}
+
+// rewriteSequenceEditor adds an exec line after the pick of every commit listed in
+// GITEGO_REWRITE, which holds full hashes separated by spaces; the todo list abbreviates
+// them, so picks are matched by prefix.
+const rewriteSequenceEditor = `f() { awk '{ print } ($1 == "pick" || $1 == "p") && index(ENVIRON["GITEGO_REWRITE"], " " $2) ` +
+	`{ print "exec " ENVIRON["GITEGO_AMEND"] }' "$1" > "$1.gitego" && mv "$1.gitego" "$1"; }; f`
+
+// rewriteScript builds a shell script that rebases branch (the current one if empty) from
+// base and amends the author of each of the given commits to the profile, leaving every
+// other commit as it is. Amended commits are re-signed if the profile signs.
+func rewriteScript(profileName string, profile *config.Profile, base, branch string, hashes []string) string {
+	identity := fmt.Sprintf("-c user.name=%s -c user.email=%s",
+		config.ShellQuote(profile.Name), config.ShellQuote(profile.Email))
+	amend := fmt.Sprintf("git %s commit --amend --no-edit --allow-empty --no-verify --author=%s",
+		identity, config.ShellQuote(fmt.Sprintf("%s <%s>", profile.Name, profile.Email)))
+
+	var b strings.Builder
+
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# Generated by gitego: re-author %d unpublished commit(s) as profile '%s'.\n", len(hashes), profileName)
+	b.WriteString("set -e\n")
+	fmt.Fprintf(&b, "GITEGO_REWRITE=%s\n", config.ShellQuote(" "+strings.Join(hashes, " ")))
+	fmt.Fprintf(&b, "GITEGO_AMEND=%s\n", config.ShellQuote(amend))
+	b.WriteString("export GITEGO_REWRITE GITEGO_AMEND\n")
+	fmt.Fprintf(&b, "GIT_SEQUENCE_EDITOR=%s git %s rebase -i --rebase-merges %s",
+		config.ShellQuote(rewriteSequenceEditor), identity, base)
+
+	if branch != "" {
+		b.WriteString(" " + config.ShellQuote(branch))
+	}
+
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// runShellScript runs a script with sh in the current directory.
+func runShellScript(script string) error {
+	command := exec.Command("sh", "-c", script)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	return command.Run()
+}
+
+// fixAuthorsCmd represents the fix-authors command.
+var fixAuthorsCmd = &cobra.Command{
+	Use:   "fix-authors [revision-range]",
+	Short: "Maps or rewrites commits made with the wrong identity.",
+	Long: `Builds on 'gitego audit' to repair misattributed commits.
+
+For every stray author or committer email that belongs to another of your
+profiles it proposes a .mailmap entry mapping it to the expected profile, so
+'git log' and 'git shortlog' show the right identity without rewriting history.
+Emails of teammates, bots and hosting services are left alone; --map-email adds
+an email of yours that no profile uses. Use --write-mailmap to be asked about
+each entry before it is merged into the repository's .mailmap.
+
+For misattributed commits of the same range that have not been pushed to any
+remote and that you authored, under the profile or another of your identities,
+it also shows a rewrite plan: a 'git rebase' script that amends the author of
+those commits only. Teammates' commits are left as they are. The plan rebases
+the current branch, or --branch. It is only printed unless --rewrite is given,
+and even then it runs only after you confirm it.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &fixAuthorsRunner{
+			audit: &auditRunner{
+				load:       config.Load,
+				getCommits: utils.GetCommits,
+			},
+			findRoot:  func() (string, error) { return findGitRoot(".") },
+			runScript: runShellScript,
+			stdin:     os.Stdin,
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(fixAuthorsCmd)
+
+	fixAuthorsCmd.Flags().StringVar(&auditSince, "since", "", "Only consider commits more recent than a date")
+	fixAuthorsCmd.Flags().StringVar(&auditBranch, "branch", "", "Consider the history of this branch instead of HEAD")
+	fixAuthorsCmd.Flags().StringVar(&auditProfile, "profile", "", "Fix towards this profile instead of the one expected here")
+	fixAuthorsCmd.Flags().BoolVar(&fixWriteMailmap, "write-mailmap", false, "Merge the proposed entries into .mailmap")
+	fixAuthorsCmd.Flags().StringSliceVar(&fixMapEmails, "map-email", nil, "Also map this email of yours to the profile")
+	fixAuthorsCmd.Flags().BoolVar(&fixRewrite, "rewrite", false, "Offer to run the rewrite plan for unpublished commits")
+}
@@ -0,0 +1,179 @@
+// cmd/fix_authors_test.go
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/utils"
+	"github.com/spf13/cobra"
+)
+
+func TestFixAuthorsCommand(t *testing.T) {
+	root := t.TempDir()
+	mailmapPath := filepath.Join(root, ".mailmap")
+
+	existing := "Work User <work@corp.com> <old@corp.com>\n"
+	if err := os.WriteFile(mailmapPath, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mockCfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"work":     {Name: "Work User", Email: "work@corp.com"},
+			"personal": {Name: "Me", Email: "me@gmail.com"},
+		},
+		ActiveProfile: "work",
+	}
+
+	history := []utils.Commit{
+		{Hash: "cccc", Parents: []string{"eeee"}, AuthorEmail: "me@gmail.com", CommitterEmail: "me@gmail.com"},
+		// A teammate's commit, and one merged in GitHub's web interface, are not ours to map.
+		{Hash: "eeee", Parents: []string{"dddd"}, AuthorEmail: "work@corp.com", CommitterEmail: "noreply@github.com"},
+		{Hash: "dddd", Parents: []string{"bbbb"}, AuthorEmail: "sam@corp.com", CommitterEmail: "sam@corp.com"},
+		{Hash: "bbbb", Parents: []string{"aaaa"}, AuthorEmail: "old@corp.com", CommitterEmail: "work@corp.com"},
+		{Hash: "aaaa", AuthorEmail: "work@corp.com", CommitterEmail: "work@corp.com"},
+	}
+
+	var ranScript string
+
+	runner := &fixAuthorsRunner{
+		audit: &auditRunner{
+			load: func() (*config.Config, error) { return mockCfg, nil },
+			getCommits: func(args ...string) ([]utils.Commit, error) {
+				if len(args) > 0 && args[0] == "HEAD" {
+					// Only the newest commit is unpublished.
+					return history[:1], nil
+				}
+
+				return history, nil
+			},
+		},
+		findRoot:  func() (string, error) { return root, nil },
+		runScript: func(script string) error { ranScript = script; return nil },
+		stdin:     strings.NewReader("y\ny\n"),
+	}
+
+	fixWriteMailmap = true
+	fixRewrite = true
+
+	defer func() {
+		fixWriteMailmap = false
+		fixRewrite = false
+	}()
+
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	runner.run(cmd, []string{})
+
+	data, err := os.ReadFile(mailmapPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := existing + "Work User <work@corp.com> <me@gmail.com>\n"
+	if string(data) != expected {
+		t.Errorf("Expected .mailmap:\n%s\ngot:\n%s", expected, string(data))
+	}
+
+	if !strings.Contains(out.String(), "Not mapping 1 email(s) that don't belong to your profiles: sam@corp.com") {
+		t.Errorf("Expected the teammate's email to be left alone, got:\n%s", out.String())
+	}
+
+	if !strings.Contains(ranScript, "GITEGO_REWRITE=' cccc'") || !strings.Contains(ranScript, "rebase -i --rebase-merges eeee\n") {
+		t.Errorf("Expected a rebase from eeee rewriting only cccc, got:\n%s", ranScript)
+	}
+
+	if !strings.Contains(ranScript, "--author='\\''Work User <work@corp.com>'\\''") {
+		t.Errorf("Expected the amend to set the profile as author, got:\n%s", ranScript)
+	}
+}
+
+// TestFixAuthorsCommand_Rewrite runs the rewrite plan in a real repository and checks that
+// only the user's own misattributed commits are re-authored.
+func TestFixAuthorsCommand_Rewrite(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	root := t.TempDir()
+	t.Chdir(root)
+
+	git := func(env []string, args ...string) {
+		t.Helper()
+
+		command := exec.Command("git", args...)
+		command.Env = append(os.Environ(), env...)
+
+		if output, err := command.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	git(nil, "init", "-q")
+
+	for i, email := range []string{"work@corp.com", "me@gmail.com", "sam@corp.com", "me@gmail.com"} {
+		git([]string{
+			"GIT_AUTHOR_NAME=Someone", "GIT_AUTHOR_EMAIL=" + email,
+			"GIT_COMMITTER_NAME=Someone", "GIT_COMMITTER_EMAIL=" + email,
+		}, "commit", "-q", "--allow-empty", "-m", fmt.Sprintf("commit %d", i))
+	}
+
+	mockCfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"work":     {Name: "Work User", Email: "work@corp.com"},
+			"personal": {Name: "Me", Email: "me@gmail.com"},
+		},
+		ActiveProfile: "work",
+	}
+
+	runner := &fixAuthorsRunner{
+		audit: &auditRunner{
+			load:       func() (*config.Config, error) { return mockCfg, nil },
+			getCommits: utils.GetCommits,
+		},
+		findRoot: func() (string, error) { return root, nil },
+		runScript: func(script string) error {
+			output, err := exec.Command("sh", "-c", script).CombinedOutput()
+			if err != nil {
+				t.Logf("rewrite output:\n%s", output)
+			}
+
+			return err
+		},
+		stdin: strings.NewReader("y\n"),
+	}
+
+	fixRewrite = true
+
+	defer func() { fixRewrite = false }()
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(&bytes.Buffer{})
+
+	if err := runner.run(cmd, []string{}); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := exec.Command("git", "log", "--format=%ae").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "work@corp.com\nsam@corp.com\nwork@corp.com\nwork@corp.com\n"
+	if string(output) != expected {
+		t.Errorf("Expected only my commits to be re-authored, got authors:\n%s", output)
+	}
+}
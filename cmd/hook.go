@@ -0,0 +1,78 @@
+// cmd/hook.go
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/utils"
+	"github.com/spf13/cobra"
+)
+
+// hookCmd groups the stable hook entrypoints that hook managers call.
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Entrypoints for Git hook managers such as pre-commit and husky.",
+	Long: `Runs gitego's checks for one Git hook. These commands are stable entrypoints
+for hook managers; for example, with the pre-commit framework:
+
+  repos:
+    - repo: https://github.com/bgreenwell/gitego
+      rev: <version>
+      hooks:
+        - id: gitego
+
+or, with husky, a line in .husky/pre-commit:
+
+  gitego hook pre-commit
+
+'gitego install-hook' sets either of these up for you when it finds
+.pre-commit-config.yaml or a .husky directory in the repository.`,
+}
+
+// hookPreCommitCmd checks the commit author, like the hook 'gitego install-hook' writes.
+var hookPreCommitCmd = &cobra.Command{
+	Use:   "pre-commit",
+	Short: "Checks the commit author against the profile expected for this repository.",
+	Long: `Checks the commit author against the profile expected for this repository,
+the team policy and the repository's .gitego.yaml, and exits non-zero to block
+the commit. On a mismatch it asks whether to abort; without a terminal to
+answer on, the commit is aborted.`,
+	Args: cobra.ArbitraryArgs,
+	Run:  func(cmd *cobra.Command, args []string) { checkCommitCmd.Run(cmd, nil) },
+}
+
+// hookCommitMsgCmd is the entrypoint for checks on the commit message.
+var hookCommitMsgCmd = &cobra.Command{
+	Use:   "commit-msg <message-file>",
+	Short: "Checks the commit message Git is about to record.",
+	Long: `Checks the commit message Git is about to record, run by Git's commit-msg
+hook with the path of the message file.
+
+With a sign-off mode set for the profile or auto-rule ('--signoff'), the
+commit author must be the profile, and the profile's own sign-off is appended
+(append) or required (require). A Signed-off-by trailer by someone else is
+rejected unless the author's own follows it, as 'git cherry-pick -s' writes.
+During a pairing session ('gitego pair'), a Co-authored-by trailer is added
+for each teammate.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runner := &commitMsgRunner{
+			loadConfig:    config.Load,
+			authorIdent:   utils.GetAuthorIdent,
+			parseTrailers: utils.ParseTrailers,
+			addTrailers:   utils.AddTrailers,
+			stderr:        os.Stderr,
+			exit:          os.Exit,
+		}
+		runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hookCmd)
+
+	hookCmd.AddCommand(hookPreCommitCmd)
+	hookCmd.AddCommand(hookCommitMsgCmd)
+}
@@ -0,0 +1,276 @@
+// cmd/hook_framework.go
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// preCommitConfigName is the pre-commit framework's config file.
+const preCommitConfigName = ".pre-commit-config.yaml"
+
+// hookFramework is a hook manager that owns a repository's hooks, so gitego's hook has to
+// be added to its config rather than to .git/hooks.
+type hookFramework struct {
+	name string
+	// path is the framework's config file or directory, relative to the repository root.
+	path string
+	// add adds a gitego hook to the framework's config, reporting false if it was already there.
+	add func(gitRoot string, hook gitHook) (bool, error)
+	// remove takes a gitego hook back out, reporting whether it was there.
+	remove func(gitRoot string, hook gitHook) (bool, error)
+	// installed reports whether a gitego hook is in the framework's config.
+	installed func(gitRoot string, hook gitHook) bool
+	// activate tells the user how to make the framework pick up the change, if needed.
+	activate func(hook gitHook) string
+}
+
+// hookFrameworks lists the hook managers gitego integrates with.
+var hookFrameworks = []hookFramework{
+	{
+		name:   "pre-commit",
+		path:   preCommitConfigName,
+		add:    addPreCommitFrameworkHook,
+		remove: removePreCommitFrameworkHook,
+		installed: func(gitRoot string, hook gitHook) bool {
+			return fileRunsHook(filepath.Join(gitRoot, preCommitConfigName), hook)
+		},
+		activate: func(hook gitHook) string {
+			return fmt.Sprintf("Run 'pre-commit install --hook-type %s' to activate it.", hook.name)
+		},
+	},
+	{
+		name: "husky",
+		path: ".husky",
+		add:  addHuskyHook,
+		remove: func(gitRoot string, hook gitHook) (bool, error) {
+			return removeHookScript(huskyHookPath(gitRoot, hook), hook)
+		},
+		installed: func(gitRoot string, hook gitHook) bool { return fileRunsHook(huskyHookPath(gitRoot, hook), hook) },
+		activate:  func(gitHook) string { return "" },
+	},
+}
+
+// detectHookFramework returns the hook manager the repository at gitRoot uses, if any.
+func detectHookFramework(gitRoot string) *hookFramework {
+	for i := range hookFrameworks {
+		if _, err := os.Stat(filepath.Join(gitRoot, hookFrameworks[i].path)); err == nil {
+			return &hookFrameworks[i]
+		}
+	}
+
+	return nil
+}
+
+// preCommitFrameworkEntry returns a 'repo: local' entry that runs a gitego hook through
+// the stable entrypoint, so it works without pinning a gitego release.
+func preCommitFrameworkEntry(hook gitHook) string {
+	return fmt.Sprintf(`repo: local
+hooks:
+  - id: gitego-%[1]s
+    name: gitego %[1]s check
+    entry: %[2]s
+    language: system
+    always_run: true
+    pass_filenames: %[3]t
+    stages: [%[1]s]
+`, hook.name, hook.entrypoint, hook.name == "commit-msg")
+}
+
+// addPreCommitFrameworkHook adds a gitego hook to .pre-commit-config.yaml, keeping the
+// rest of the file and its comments.
+func addPreCommitFrameworkHook(gitRoot string, hook gitHook) (bool, error) {
+	path := filepath.Join(gitRoot, preCommitConfigName)
+
+	doc, err := readYAMLDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	repos := preCommitRepos(doc)
+	if repos == nil {
+		return false, fmt.Errorf("%s has no 'repos' list", preCommitConfigName)
+	}
+
+	for _, repo := range repos.Content {
+		if slices.ContainsFunc(preCommitHookEntries(repo), hook.runsIn) {
+			return false, nil
+		}
+	}
+
+	var entry yaml.Node
+	if err := yaml.Unmarshal([]byte(preCommitFrameworkEntry(hook)), &entry); err != nil {
+		return false, err
+	}
+
+	repos.Content = append(repos.Content, entry.Content[0])
+
+	return true, writeYAMLDocument(path, doc)
+}
+
+// removePreCommitFrameworkHook removes every gitego hook entry for hook from
+// .pre-commit-config.yaml, and any local repo left without hooks.
+func removePreCommitFrameworkHook(gitRoot string, hook gitHook) (bool, error) {
+	path := filepath.Join(gitRoot, preCommitConfigName)
+
+	doc, err := readYAMLDocument(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	repos := preCommitRepos(doc)
+	if repos == nil {
+		return false, nil
+	}
+
+	removed := false
+
+	repos.Content = slices.DeleteFunc(repos.Content, func(repo *yaml.Node) bool {
+		hooks := mappingValue(repo, "hooks")
+		if hooks == nil || hooks.Kind != yaml.SequenceNode {
+			return false
+		}
+
+		before := len(hooks.Content)
+		hooks.Content = slices.DeleteFunc(hooks.Content, func(h *yaml.Node) bool {
+			entry := mappingValue(h, "entry")
+
+			return entry != nil && hook.runsIn(entry.Value)
+		})
+
+		if len(hooks.Content) == before {
+			return false
+		}
+
+		removed = true
+
+		return len(hooks.Content) == 0
+	})
+
+	if !removed {
+		return false, nil
+	}
+
+	return true, writeYAMLDocument(path, doc)
+}
+
+// preCommitRepos returns the 'repos' sequence of a .pre-commit-config.yaml document.
+func preCommitRepos(doc *yaml.Node) *yaml.Node {
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	repos := mappingValue(doc.Content[0], "repos")
+	if repos == nil || repos.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	return repos
+}
+
+// preCommitHookEntries returns the 'entry' commands of a pre-commit repo's hooks.
+func preCommitHookEntries(repo *yaml.Node) []string {
+	var entries []string
+
+	if hooks := mappingValue(repo, "hooks"); hooks != nil {
+		for _, h := range hooks.Content {
+			if entry := mappingValue(h, "entry"); entry != nil {
+				entries = append(entries, entry.Value)
+			}
+		}
+	}
+
+	return entries
+}
+
+// mappingValue returns the value for key in a YAML mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// readYAMLDocument parses a YAML file into a node tree, which keeps its comments.
+func readYAMLDocument(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", filepath.Base(path), err)
+	}
+
+	return &doc, nil
+}
+
+// writeYAMLDocument writes a node tree back to a YAML file.
+func writeYAMLDocument(path string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), filePermissions)
+}
+
+// huskyHookPath returns the husky script for a hook in the repository at gitRoot.
+func huskyHookPath(gitRoot string, hook gitHook) string {
+	return filepath.Join(gitRoot, ".husky", hook.name)
+}
+
+// addHuskyHook adds gitego's block to the husky script for the hook, creating it if needed.
+func addHuskyHook(gitRoot string, hook gitHook) (bool, error) {
+	hookPath := huskyHookPath(gitRoot, hook)
+	block := hook.block(hook.entrypoint)
+
+	content, err := os.ReadFile(hookPath)
+	if os.IsNotExist(err) {
+		// Husky scripts are run by sh and need no shebang.
+		return true, os.WriteFile(hookPath, []byte(hookCreatedMarker+"\n\n"+block), executableFilePermissions)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if hook.runsIn(string(content)) {
+		return false, nil
+	}
+
+	return true, appendHookBlock(hookPath, content, block)
+}
+
+// fileRunsHook reports whether the file at path runs a gitego hook.
+func fileRunsHook(path string, hook gitHook) bool {
+	content, err := os.ReadFile(path)
+
+	return err == nil && hook.runsIn(string(content))
+}
@@ -0,0 +1,112 @@
+// cmd/hook_framework_test.go
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPreCommitFrameworkHook(t *testing.T) {
+	repoRoot, _ := setupTestGitRepo(t)
+	defer func() { _ = os.RemoveAll(repoRoot) }()
+
+	configPath := filepath.Join(repoRoot, preCommitConfigName)
+	original := `# Project hooks.
+repos:
+  - repo: https://github.com/pre-commit/pre-commit-hooks
+    rev: v4.6.0
+    hooks:
+      - id: trailing-whitespace # keep this
+`
+	if err := os.WriteFile(configPath, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	framework := detectHookFramework(repoRoot)
+	if framework == nil || framework.name != "pre-commit" {
+		t.Fatalf("Expected the pre-commit framework to be detected, got %v", framework)
+	}
+
+	if added, err := framework.add(repoRoot, preCommitHook); err != nil || !added {
+		t.Fatalf("Expected the hook to be added, got %v, %v", added, err)
+	}
+
+	content, _ := os.ReadFile(configPath)
+	for _, want := range []string{"# Project hooks.", "# keep this", "repo: local", "entry: gitego hook pre-commit"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected the config to contain %q:\n%s", want, content)
+		}
+	}
+
+	if !preCommitHook.installed(repoRoot) {
+		t.Error("Expected the hook to be reported as installed.")
+	}
+
+	if added, err := framework.add(repoRoot, preCommitHook); err != nil || added {
+		t.Errorf("Expected a second add to do nothing, got %v, %v", added, err)
+	}
+
+	if removed, err := framework.remove(repoRoot, preCommitHook); err != nil || !removed {
+		t.Fatalf("Expected the hook to be removed, got %v, %v", removed, err)
+	}
+
+	content, _ = os.ReadFile(configPath)
+	if strings.Contains(string(content), "gitego") || !strings.Contains(string(content), "trailing-whitespace") {
+		t.Errorf("Expected only the gitego entry to be removed:\n%s", content)
+	}
+}
+
+func TestHuskyHook(t *testing.T) {
+	repoRoot, _ := setupTestGitRepo(t)
+	defer func() { _ = os.RemoveAll(repoRoot) }()
+
+	huskyDir := filepath.Join(repoRoot, ".husky")
+	if err := os.MkdirAll(huskyDir, 0755); err != nil {
+		t.Fatalf("Failed to create .husky: %v", err)
+	}
+
+	framework := detectHookFramework(repoRoot)
+	if framework == nil || framework.name != "husky" {
+		t.Fatalf("Expected husky to be detected, got %v", framework)
+	}
+
+	// A new script is created, and deleted again on removal.
+	if added, err := framework.add(repoRoot, preCommitHook); err != nil || !added {
+		t.Fatalf("Expected the hook to be added, got %v, %v", added, err)
+	}
+
+	if removed, err := framework.remove(repoRoot, preCommitHook); err != nil || !removed {
+		t.Fatalf("Expected the hook to be removed, got %v, %v", removed, err)
+	}
+
+	hookPath := filepath.Join(huskyDir, "pre-commit")
+	if _, err := os.Stat(hookPath); !os.IsNotExist(err) {
+		t.Error("Expected the script gitego created to be deleted.")
+	}
+
+	// An existing script keeps its own commands.
+	if err := os.WriteFile(hookPath, []byte("npx lint-staged\n"), 0755); err != nil {
+		t.Fatalf("Failed to write husky hook: %v", err)
+	}
+
+	if _, err := framework.add(repoRoot, preCommitHook); err != nil {
+		t.Fatalf("Expected the hook to be added, got %v", err)
+	}
+
+	content, _ := os.ReadFile(hookPath)
+	if !strings.HasPrefix(string(content), "npx lint-staged\n") || !strings.Contains(string(content), "gitego hook pre-commit") {
+		t.Errorf("Expected the gitego block to be appended:\n%s", content)
+	}
+
+	if _, err := framework.remove(repoRoot, preCommitHook); err != nil {
+		t.Fatalf("Expected the hook to be removed, got %v", err)
+	}
+
+	content, _ = os.ReadFile(hookPath)
+	if string(content) != "npx lint-staged\n" {
+		t.Errorf("Expected the original script back, got %q", content)
+	}
+}
@@ -0,0 +1,359 @@
+// cmd/import.go
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importDryRun     bool
+	importYes        bool
+	importOverwrite  bool
+	importWithTokens bool
+	importForce      bool
+)
+
+// importRunner holds the dependencies for the import command for mocking.
+type importRunner struct {
+	load     func() (*config.Config, error)
+	save     func(*config.Config) error
+	setToken func(string, string) error
+	discover func() ([]config.ImportCandidate, []error)
+	auto     *autoRunner
+	stdin    io.Reader
+}
+
+// run is the core logic for the import command.
+func (r *importRunner) run(cmd *cobra.Command, args []string) error {
+	cfg, err := r.load()
+	if err != nil {
+		return errLoadConfig(err)
+	}
+
+	candidates, warnings := r.discover()
+	for _, warning := range warnings {
+		fmt.Printf("Warning: %v\n", warning)
+	}
+
+	candidates = mergeImportCandidates(candidates)
+	if len(candidates) == 0 {
+		fmt.Println("No identities found to import.")
+
+		return nil
+	}
+
+	fmt.Printf("Found %d candidate profile(s):\n", len(candidates))
+
+	for _, candidate := range candidates {
+		fmt.Printf("  %s\n", describeImportCandidate(cfg, candidate))
+	}
+
+	if importDryRun {
+		return nil
+	}
+
+	reader := bufio.NewReader(r.stdin)
+	imported := 0
+
+	for _, candidate := range candidates {
+		if r.importCandidate(cfg, candidate, reader) {
+			imported++
+		}
+	}
+
+	if err := r.save(cfg); err != nil {
+		return errSaveConfig(err)
+	}
+
+	printSuccess("✓ Imported %d profile(s).\n", imported)
+
+	return nil
+}
+
+// importCandidate creates a profile (and rule) from one candidate, asking first unless --yes
+// was given. It reports whether the profile was imported.
+func (r *importRunner) importCandidate(cfg *config.Config, candidate config.ImportCandidate, reader *bufio.Reader) bool {
+	if _, exists := cfg.Profiles[candidate.Name]; exists && !importOverwrite {
+		return false
+	}
+
+	profile := candidate.Profile
+
+	if !importYes {
+		fmt.Printf("\nImport '%s' from %s? [y/N]: ", candidate.Name, candidate.Source)
+
+		if !readYes(reader) {
+			return false
+		}
+
+		if profile.Name == "" {
+			profile.Name = promptLine(reader, "  user.name: ")
+		}
+
+		if profile.Email == "" {
+			profile.Email = promptLine(reader, "  user.email: ")
+		}
+	}
+
+	if profile.Name == "" || profile.Email == "" {
+		if importYes {
+			fmt.Printf("Skipping '%s': a name and email are required; run 'gitego import' without --yes to enter them.\n",
+				candidate.Name)
+		} else {
+			fmt.Printf("Skipping '%s': a name and email are required.\n", candidate.Name)
+		}
+
+		return false
+	}
+
+	if err := checkProfile(candidate.Name, &profile, importForce); err != nil {
+		fmt.Printf("Skipping '%s': %v\n", candidate.Name, err)
+
+		return false
+	}
+
+	cfg.Profiles[candidate.Name] = &profile
+
+	if candidate.Token != "" && importWithTokens {
+		if err := r.setToken(candidate.Name, candidate.Token); err != nil {
+			fmt.Printf("Warning: Failed to store token for '%s' securely: %v\n", candidate.Name, err)
+		}
+	}
+
+	if candidate.RulePath != "" {
+		r.importRule(cfg, candidate, &profile)
+	} else if candidate.UnsupportedRule != "" {
+		fmt.Printf("Not recording a rule for includeIf '%s': only absolute and ~/ paths can become auto-switch rules.\n",
+			candidate.UnsupportedRule)
+	}
+
+	printSuccess("✓ Profile '%s' imported.\n", candidate.Name)
+
+	return true
+}
+
+// importRule records the auto-switch rule of a candidate found in an includeIf block. The
+// directory already includes the user's own file, so no second includeIf is added for it.
+func (r *importRunner) importRule(cfg *config.Config, candidate config.ImportCandidate, profile *config.Profile) {
+	cleanPath, err := r.auto.processPath(candidate.RulePath)
+	if err != nil {
+		fmt.Printf("Warning: Could not resolve rule path '%s': %v\n", candidate.RulePath, err)
+
+		return
+	}
+
+	if r.auto.ruleExists(cfg, cleanPath, candidate.Name, candidate.RulePath) {
+		return
+	}
+
+	if err := r.auto.ensureProfileGitconfig(candidate.Name, profile); err != nil {
+		fmt.Printf("Warning: Could not create the gitconfig for profile '%s': %v\n", candidate.Name, err)
+	}
+
+	cfg.AutoRules = append(cfg.AutoRules, &config.AutoRule{Path: cleanPath, Profile: candidate.Name})
+	fmt.Printf("Keeping your includeIf for '%s'; the auto-switch rule is recorded without adding another.\n",
+		candidate.RulePath)
+}
+
+// mergeImportCandidates collapses candidates that share an email, keeping the first
+// candidate's name and filling its empty fields from the others.
+func mergeImportCandidates(candidates []config.ImportCandidate) []config.ImportCandidate {
+	var merged []config.ImportCandidate
+
+	byEmail := make(map[string]int)
+
+	for _, candidate := range candidates {
+		email := strings.ToLower(candidate.Profile.Email)
+		if email == "" {
+			merged = append(merged, candidate)
+
+			continue
+		}
+
+		i, seen := byEmail[email]
+		if !seen || (merged[i].RulePath != "" && candidate.RulePath != "") {
+			byEmail[email] = len(merged)
+			merged = append(merged, candidate)
+
+			continue
+		}
+
+		target := &merged[i]
+		if target.RulePath == "" {
+			target.RulePath = candidate.RulePath
+		}
+
+		if target.UnsupportedRule == "" {
+			target.UnsupportedRule = candidate.UnsupportedRule
+		}
+
+		if target.Profile.Name == "" {
+			target.Profile.Name = candidate.Profile.Name
+		}
+
+		if target.Profile.SigningKey == "" {
+			target.Profile.SigningKey = candidate.Profile.SigningKey
+		}
+
+		target.Source += ", " + candidate.Source
+	}
+
+	return merged
+}
+
+func describeImportCandidate(cfg *config.Config, candidate config.ImportCandidate) string {
+	identity := candidate.Profile.Email
+	if candidate.Profile.Name != "" {
+		identity = fmt.Sprintf("%s <%s>", candidate.Profile.Name, candidate.Profile.Email)
+	}
+
+	var details []string
+	if identity != "" {
+		details = append(details, identity)
+	}
+
+	if candidate.Profile.Username != "" {
+		details = append(details, "username "+candidate.Profile.Username)
+	}
+
+	if candidate.Profile.SSHKey != "" {
+		details = append(details, "ssh key "+candidate.Profile.SSHKey)
+	}
+
+	if candidate.RulePath != "" {
+		details = append(details, "rule "+candidate.RulePath)
+	} else if candidate.UnsupportedRule != "" {
+		details = append(details, "includeIf "+candidate.UnsupportedRule+" (not importable as a rule)")
+	}
+
+	if candidate.Token != "" {
+		details = append(details, "token available")
+	}
+
+	status := ""
+	if _, exists := cfg.Profiles[candidate.Name]; exists {
+		status = " (profile exists)"
+	} else if candidate.Profile.Name == "" || candidate.Profile.Email == "" {
+		status = " (needs a name and email: import it without --yes)"
+	}
+
+	return fmt.Sprintf("%-16s %s — from %s%s", candidate.Name, strings.Join(details, ", "), candidate.Source, status)
+}
+
+// readYes reads a line and reports whether it was an affirmative answer.
+func readYes(reader *bufio.Reader) bool {
+	response, _ := reader.ReadString('\n')
+
+	return strings.TrimSpace(strings.ToLower(response)) == "y"
+}
+
+// promptLine prints a prompt and returns the trimmed line the user enters.
+func promptLine(reader *bufio.Reader, prompt string) string {
+	fmt.Print(prompt)
+
+	response, _ := reader.ReadString('\n')
+
+	return strings.TrimSpace(response)
+}
+
+// discoverImportCandidates collects candidates from every supported source.
+func discoverImportCandidates() ([]config.ImportCandidate, []error) {
+	var candidates []config.ImportCandidate
+
+	var warnings []error
+
+	if candidate, err := config.DiscoverGitconfigUser(config.GlobalGitconfigPath(), "global", "global .gitconfig"); err != nil {
+		warnings = append(warnings, err)
+	} else if candidate != nil {
+		candidates = append(candidates, *candidate)
+	}
+
+	if gitRoot, err := findGitRoot("."); err == nil {
+		localPath := filepath.Join(gitRoot, ".git", "config")
+		name := filepath.Base(gitRoot)
+
+		if candidate, err := config.DiscoverGitconfigUser(localPath, name, "local .git/config of "+name); err != nil {
+			warnings = append(warnings, err)
+		} else if candidate != nil {
+			candidates = append(candidates, *candidate)
+		}
+	}
+
+	sources := []func() ([]config.ImportCandidate, error){
+		func() ([]config.ImportCandidate, error) {
+			return config.DiscoverIncludeIfs(config.GlobalGitconfigPath())
+		},
+		func() ([]config.ImportCandidate, error) { return config.DiscoverSSHHosts(config.UserSSHConfigPath()) },
+		func() ([]config.ImportCandidate, error) { return config.DiscoverGHHosts(config.GHHostsPath()) },
+	}
+
+	for _, source := range sources {
+		found, err := source()
+		if err != nil {
+			warnings = append(warnings, err)
+
+			continue
+		}
+
+		candidates = append(candidates, found...)
+	}
+
+	return candidates, warnings
+}
+
+// importCmd represents the import command.
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Creates profiles from identities already configured on this machine.",
+	Long: `Discovers identities you have already configured and offers to turn them
+into gitego profiles. Sources are the user.* settings of your global and local
+gitconfig, includeIf blocks in ~/.gitconfig that point at other files (which
+also become auto-switch rules), Host entries with an IdentityFile in
+~/.ssh/config, and accounts from the GitHub CLI's hosts.yml.
+
+Existing profiles are never replaced unless --overwrite is given. Use --dry-run
+to list what would be imported without changing anything. Candidates that fail
+profile validation are skipped unless --force is given. SSH hosts and GitHub
+CLI logins carry no name or email, so --yes skips them; import them without
+--yes to enter those. A profile found in an includeIf block keeps that block
+instead of getting a second one from gitego. Only absolute and ~/ gitdir
+patterns become rules; relative and wildcard patterns are listed as not
+importable and their profiles are imported without a rule.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &importRunner{
+			load:     config.Load,
+			save:     func(c *config.Config) error { return c.Save() },
+			setToken: config.SetToken,
+			discover: discoverImportCandidates,
+			auto: &autoRunner{
+				save:                   func(c *config.Config) error { return c.Save() },
+				ensureProfileGitconfig: config.EnsureProfileGitconfig,
+				addIncludeIf:           config.AddIncludeIf,
+			},
+			stdin: os.Stdin,
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "List candidate profiles without importing them")
+	importCmd.Flags().BoolVarP(&importYes, "yes", "y", false, "Import every complete candidate without prompting")
+	importCmd.Flags().BoolVar(&importOverwrite, "overwrite", false, "Replace existing profiles with the same name")
+	importCmd.Flags().BoolVar(&importWithTokens, "with-tokens", false,
+		"Store tokens found in the GitHub CLI config in gitego's vault")
+	importCmd.Flags().BoolVar(&importForce, "force", false,
+		"Import candidates even if their email, username or keys fail validation")
+}
@@ -0,0 +1,271 @@
+// cmd/import_bundle.go
+
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	conflictSkip      = "skip"
+	conflictRename    = "rename"
+	conflictOverwrite = "overwrite"
+)
+
+var importBundleOnConflict string
+
+// importBundleRunner holds the dependencies for the import-bundle command for mocking.
+type importBundleRunner struct {
+	load           func() (*config.Config, error)
+	save           func(*config.Config) error
+	setToken       func(string, string) error
+	setSecret      func(profileName, name, value string) error
+	readBundle     func(string) (*config.Bundle, error)
+	readPassphrase func() (string, error)
+	auto           *autoRunner
+}
+
+// run is the core logic for the import-bundle command.
+func (r *importBundleRunner) run(cmd *cobra.Command, args []string) error {
+	switch importBundleOnConflict {
+	case conflictSkip, conflictRename, conflictOverwrite:
+	default:
+		return errors.InvalidInput("invalid --on-conflict value '%s'", importBundleOnConflict).
+			WithHint("Use skip, rename or overwrite.")
+	}
+
+	cfg, err := r.load()
+	if err != nil {
+		return errLoadConfig(err)
+	}
+
+	bundle, err := r.readBundle(args[0])
+	if errors.Is(err, fs.ErrNotExist) {
+		return errors.NotFound("bundle '%s' not found", args[0])
+	}
+
+	if err != nil {
+		return errors.IO(err, "could not import '%s'", args[0])
+	}
+
+	// Tokens are bound to the profile names they were exported under, so they must be
+	// decrypted before any conflicting profile is renamed.
+	var tokens map[string]string
+
+	if bundle.Tokens != nil {
+		passphrase, err := r.readPassphrase()
+		if err != nil {
+			return errors.IO(err, "could not read passphrase")
+		}
+
+		tokens, err = bundle.DecryptTokens(passphrase)
+		if err != nil {
+			return errors.InvalidInput("could not decrypt tokens: %v", err)
+		}
+	}
+
+	imported, overwritten := r.mergeProfiles(cfg, bundle, tokens)
+
+	// Imported profiles may extend each other or existing ones; the rules need their values.
+	cfg.ResolveProfiles()
+
+	r.mergeRules(cfg, bundle, imported, overwritten)
+
+	if cfg.ActiveProfile == "" && imported[bundle.ActiveProfile] {
+		cfg.ActiveProfile = bundle.ActiveProfile
+	}
+
+	if err := r.save(cfg); err != nil {
+		return errSaveConfig(err)
+	}
+
+	printSuccess("✓ Imported %d profile(s) from %s.\n", len(imported), args[0])
+
+	return nil
+}
+
+// mergeProfiles copies the bundle's profiles into cfg, resolving name conflicts according
+// to --on-conflict. Renamed profiles are renamed inside the bundle first, so its rules and
+// the profiles extending them follow. It returns the imported profile names and those that
+// replaced an existing profile.
+func (r *importBundleRunner) mergeProfiles(
+	cfg *config.Config,
+	bundle *config.Bundle,
+	tokens map[string]string,
+) (map[string]bool, map[string]bool) {
+	imported := make(map[string]bool)
+	overwritten := make(map[string]bool)
+
+	names := make([]string, 0, len(bundle.Profiles))
+	for name := range bundle.Profiles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	// Decide every name before copying, since a profile may extend one renamed after it.
+	targets := make(map[string]string, len(names))
+
+	for _, name := range names {
+		// Profile names become file paths and keychain keys, so a bundle can't bring bad ones.
+		if err := config.ValidateProfileName(name); err != nil {
+			fmt.Printf("Skipping: %v.\n", err)
+
+			continue
+		}
+
+		target := name
+
+		if _, exists := cfg.Profiles[name]; exists {
+			switch importBundleOnConflict {
+			case conflictSkip:
+				fmt.Printf("Skipping '%s': a profile with that name already exists.\n", name)
+
+				continue
+			case conflictRename:
+				target = uniqueProfileName(cfg, bundle, name)
+				bundle.RenameProfile(name, target)
+				fmt.Printf("Importing '%s' as '%s' to avoid a conflict.\n", name, target)
+			case conflictOverwrite:
+				overwritten[target] = true
+			}
+		}
+
+		targets[name] = target
+	}
+
+	for _, name := range names {
+		target, ok := targets[name]
+		if !ok {
+			continue
+		}
+
+		cfg.Profiles[target] = bundle.LocalProfile(target)
+		imported[target] = true
+
+		if token := tokens[name]; token != "" {
+			if err := r.setToken(target, token); err != nil {
+				fmt.Printf("Warning: Failed to store token for '%s' securely: %v\n", target, err)
+			}
+		}
+
+		for _, key := range sortedKeys(tokens) {
+			profileName, secretName, isSecret := config.ParseBundleSecretKey(key)
+			if !isSecret || profileName != name {
+				continue
+			}
+
+			if err := r.setSecret(target, secretName, tokens[key]); err != nil {
+				fmt.Printf("Warning: Failed to store secret '%s' for '%s' securely: %v\n", secretName, target, err)
+			}
+		}
+
+		printSuccess("✓ Profile '%s' imported.\n", target)
+	}
+
+	return imported, overwritten
+}
+
+// mergeRules adds the bundle's auto-switch rules for imported profiles and regenerates the
+// gitconfig of overwritten profiles that already had rules.
+func (r *importBundleRunner) mergeRules(
+	cfg *config.Config,
+	bundle *config.Bundle,
+	imported, overwritten map[string]bool,
+) {
+	for _, rule := range bundle.AutoRules {
+		if !imported[rule.Profile] {
+			continue
+		}
+
+		cleanPath, err := r.auto.processPath(rule.Path)
+		if err != nil {
+			fmt.Printf("Warning: Could not resolve rule path '%s': %v\n", rule.Path, err)
+
+			continue
+		}
+
+		if r.auto.ruleExists(cfg, cleanPath, rule.Profile, rule.Path) {
+			continue
+		}
+
+		if err := r.auto.setupAutoRule(cfg, rule.Profile, cfg.Profiles[rule.Profile], cleanPath, rule.Signoff); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+
+	names := make([]string, 0, len(overwritten))
+	for name := range overwritten {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := r.auto.ensureProfileGitconfig(name, cfg.Profiles[name]); err != nil {
+			fmt.Printf("Warning: Could not update gitconfig for profile '%s': %v\n", name, err)
+		}
+	}
+}
+
+// uniqueProfileName returns name with the lowest numeric suffix that is free in both the
+// config and the bundle.
+func uniqueProfileName(cfg *config.Config, bundle *config.Bundle, name string) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", name, i)
+
+		_, inConfig := cfg.Profiles[candidate]
+		_, inBundle := bundle.Profiles[candidate]
+
+		if !inConfig && !inBundle {
+			return candidate
+		}
+	}
+}
+
+// importBundleCmd represents the import-bundle command.
+var importBundleCmd = &cobra.Command{
+	Use:   "import-bundle <file>",
+	Short: "Loads profiles and auto-switch rules from an exported bundle.",
+	Long: `Merges a bundle written by 'gitego export' into your configuration.
+Profile gitconfig files and includeIf entries are regenerated for this machine,
+and ~/ paths are expanded to your home directory.
+
+When a profile in the bundle has the same name as an existing one, --on-conflict
+decides what happens: 'skip' keeps the existing profile, 'rename' imports the
+bundle's profile under a new name such as 'work-2', and 'overwrite' replaces it.
+If the bundle contains encrypted tokens and secrets you will be asked for its
+passphrase.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &importBundleRunner{
+			load:       config.Load,
+			save:       func(c *config.Config) error { return c.Save() },
+			setToken:   config.SetToken,
+			setSecret:  config.SetSecret,
+			readBundle: config.ReadBundle,
+			readPassphrase: func() (string, error) {
+				return readSecret("Bundle passphrase: ")
+			},
+			auto: &autoRunner{
+				save:                   func(c *config.Config) error { return c.Save() },
+				ensureProfileGitconfig: config.EnsureProfileGitconfig,
+				addIncludeIf:           config.AddIncludeIf,
+			},
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importBundleCmd)
+
+	importBundleCmd.Flags().StringVar(&importBundleOnConflict, "on-conflict", conflictSkip,
+		"How to handle profiles that already exist: skip, rename or overwrite")
+}
@@ -0,0 +1,173 @@
+// cmd/import_bundle_test.go
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/spf13/cobra"
+)
+
+func TestImportBundleCommand(t *testing.T) {
+	newBundle := func() *config.Bundle {
+		bundle := &config.Bundle{
+			Version: config.BundleVersion,
+			Profiles: map[string]*config.Profile{
+				"work":     {Name: "Work User", Email: "work@corp.com"},
+				"personal": {Name: "Me", Email: "me@example.com"},
+				"acme":     {Extends: "work", Email: "work@acme.com"},
+			},
+			AutoRules:     []*config.AutoRule{{Path: "/src/work/", Profile: "work"}},
+			ActiveProfile: "personal",
+		}
+
+		if err := bundle.EncryptTokens(map[string]string{"work": "ghp_work"}, "secret"); err != nil {
+			t.Fatalf("EncryptTokens failed: %v", err)
+		}
+
+		return bundle
+	}
+
+	tests := []struct {
+		name          string
+		onConflict    string
+		expectedWork  string
+		tokenProfile  string
+		ruleProfile   string
+		regenerated   []string
+		expectedCount int
+		expectedBase  string
+	}{
+		{"skip", conflictSkip, "old@corp.com", "", "", nil, 3, "work"},
+		{"rename", conflictRename, "old@corp.com", "work-2", "work-2", []string{"work-2"}, 4, "work-2"},
+		{"overwrite", conflictOverwrite, "work@corp.com", "work", "work", []string{"work", "work"}, 3, "work"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCfg := &config.Config{
+				Profiles: map[string]*config.Profile{
+					"work": {Name: "Old", Email: "old@corp.com"},
+				},
+			}
+
+			storedTokens := make(map[string]string)
+
+			var regenerated []string
+
+			runner := &importBundleRunner{
+				load:           func() (*config.Config, error) { return mockCfg, nil },
+				save:           func(c *config.Config) error { return nil },
+				setToken:       func(name, token string) error { storedTokens[name] = token; return nil },
+				readBundle:     func(string) (*config.Bundle, error) { return newBundle(), nil },
+				readPassphrase: func() (string, error) { return "secret", nil },
+				auto: &autoRunner{
+					save: func(c *config.Config) error { return nil },
+					ensureProfileGitconfig: func(name string, _ *config.Profile) error {
+						regenerated = append(regenerated, name)
+
+						return nil
+					},
+					addIncludeIf: func(string, string) error { return nil },
+				},
+			}
+
+			importBundleOnConflict = tt.onConflict
+
+			defer func() { importBundleOnConflict = conflictSkip }()
+
+			runner.run(&cobra.Command{}, []string{"bundle.yaml"})
+
+			if len(mockCfg.Profiles) != tt.expectedCount {
+				t.Errorf("Expected %d profiles, got %d", tt.expectedCount, len(mockCfg.Profiles))
+			}
+
+			if got := mockCfg.Profiles["work"].Email; got != tt.expectedWork {
+				t.Errorf("Expected 'work' email '%s', got '%s'", tt.expectedWork, got)
+			}
+
+			if acme := mockCfg.Profiles["acme"]; acme == nil || acme.Extends != tt.expectedBase {
+				t.Errorf("Expected 'acme' to extend '%s', got %+v", tt.expectedBase, acme)
+			}
+
+			if mockCfg.Profiles["personal"] == nil || mockCfg.ActiveProfile != "personal" {
+				t.Errorf("Expected 'personal' to be imported and made active, got active '%s'", mockCfg.ActiveProfile)
+			}
+
+			if tt.tokenProfile != "" && storedTokens[tt.tokenProfile] != "ghp_work" {
+				t.Errorf("Expected token stored for '%s', got %v", tt.tokenProfile, storedTokens)
+			}
+
+			if tt.ruleProfile == "" && len(mockCfg.AutoRules) != 0 {
+				t.Errorf("Expected no rules, got %v", mockCfg.AutoRules)
+			}
+
+			if tt.ruleProfile != "" && (len(mockCfg.AutoRules) != 1 || mockCfg.AutoRules[0].Profile != tt.ruleProfile) {
+				t.Errorf("Expected one rule for '%s', got %v", tt.ruleProfile, mockCfg.AutoRules)
+			}
+
+			if len(regenerated) != len(tt.regenerated) {
+				t.Errorf("Expected gitconfigs regenerated for %v, got %v", tt.regenerated, regenerated)
+			}
+		})
+	}
+}
+
+// TestBundleSecretsRoundTrip verifies that env secrets exported with --with-tokens are
+// stored again, under the imported profile's name, by import-bundle.
+func TestBundleSecretsRoundTrip(t *testing.T) {
+	exported := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"work": {Name: "Work User", Email: "work@corp.com", Env: map[string]string{
+				"NPM_TOKEN": "vault:NPM_TOKEN", "GH_TOKEN": "vault:pat", "GH_HOST": "github.corp.com",
+			}},
+		},
+	}
+	source := mockVault(map[string]string{"work/pat": "ghp_work", "work/NPM_TOKEN": "npm_work"})
+
+	var bundle *config.Bundle
+
+	exporter := &exportRunner{
+		load:           func() (*config.Config, error) { return exported, nil },
+		getToken:       source.getToken,
+		getSecret:      source.getSecret,
+		readPassphrase: func() (string, error) { return "secret", nil },
+		writeBundle:    func(_ string, b *config.Bundle) error { bundle = b; return nil },
+	}
+
+	exportWithTokens = true
+
+	defer func() { exportWithTokens = false }()
+
+	if err := exporter.run(&cobra.Command{}, []string{"bundle.yaml"}); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	imported := &config.Config{Profiles: map[string]*config.Profile{"work": {Name: "Old", Email: "old@corp.com"}}}
+	entries := make(map[string]string)
+	target := mockVault(entries)
+
+	importer := &importBundleRunner{
+		load:           func() (*config.Config, error) { return imported, nil },
+		save:           func(*config.Config) error { return nil },
+		setToken:       target.setToken,
+		setSecret:      target.setSecret,
+		readBundle:     func(string) (*config.Bundle, error) { return bundle, nil },
+		readPassphrase: func() (string, error) { return "secret", nil },
+		auto:           &autoRunner{},
+	}
+
+	importBundleOnConflict = conflictRename
+
+	defer func() { importBundleOnConflict = conflictSkip }()
+
+	if err := importer.run(&cobra.Command{}, []string{"bundle.yaml"}); err != nil {
+		t.Fatalf("import-bundle failed: %v", err)
+	}
+
+	env, err := imported.Profiles["work-2"].ResolveEnv("work-2", target.getSecret)
+	if err != nil || env["NPM_TOKEN"] != "npm_work" || env["GH_TOKEN"] != "ghp_work" {
+		t.Errorf("Expected 'work-2' to read its secrets after the import, got %v, %v (vault: %v)", env, err, entries)
+	}
+}
@@ -0,0 +1,120 @@
+// cmd/import_test.go
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/spf13/cobra"
+)
+
+func TestImportCommand(t *testing.T) {
+	mockCfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"global": {Name: "Existing", Email: "existing@example.com"},
+		},
+	}
+
+	var includeIfCalls []string
+
+	storedTokens := make(map[string]string)
+
+	runner := &importRunner{
+		load:     func() (*config.Config, error) { return mockCfg, nil },
+		save:     func(c *config.Config) error { return nil },
+		setToken: func(name, token string) error { storedTokens[name] = token; return nil },
+		discover: func() ([]config.ImportCandidate, []error) {
+			return []config.ImportCandidate{
+				{Name: "global", Source: "global .gitconfig",
+					Profile: config.Profile{Name: "Me", Email: "me@example.com"}},
+				{Name: "work", Source: "includeIf", RulePath: "/src/work/",
+					Profile: config.Profile{Name: "Work User", Email: "work@corp.com"}},
+				{Name: "octocat", Source: "GitHub CLI", Token: "gho_abc",
+					Profile: config.Profile{Username: "octocat"}},
+				{Name: "client", Source: "includeIf", UnsupportedRule: "**/client/",
+					Profile: config.Profile{Name: "Client User", Email: "me@client.com"}},
+			}, nil
+		},
+		auto: &autoRunner{
+			save:                   func(c *config.Config) error { return nil },
+			ensureProfileGitconfig: func(string, *config.Profile) error { return nil },
+			addIncludeIf: func(profileName, path string) error {
+				includeIfCalls = append(includeIfCalls, profileName+"="+path)
+
+				return nil
+			},
+		},
+		// Accept "work", then accept "octocat" and fill in its missing identity, then accept "client".
+		stdin: strings.NewReader("y\ny\nOcto Cat\nocto@example.com\ny\n"),
+	}
+
+	importWithTokens = true
+
+	defer func() { importWithTokens = false }()
+
+	output := captureOutput(t, "", func() { _ = runner.run(&cobra.Command{}, []string{}) })
+
+	if !strings.Contains(output, "includeIf **/client/ (not importable as a rule)") {
+		t.Errorf("Expected the listing to mark the wildcard pattern as not importable, got:\n%s", output)
+	}
+
+	if mockCfg.Profiles["global"].Email != "existing@example.com" {
+		t.Error("Expected the existing 'global' profile not to be overwritten.")
+	}
+
+	if work, ok := mockCfg.Profiles["work"]; !ok || work.Email != "work@corp.com" {
+		t.Errorf("Expected 'work' profile to be imported, got %+v", work)
+	}
+
+	if len(mockCfg.AutoRules) != 1 || mockCfg.AutoRules[0].Profile != "work" || len(includeIfCalls) != 0 {
+		t.Errorf("Expected an auto-rule for 'work' without a second includeIf, got rules %v and includeIf calls %v",
+			mockCfg.AutoRules, includeIfCalls)
+	}
+
+	octocat, ok := mockCfg.Profiles["octocat"]
+	if !ok || octocat.Name != "Octo Cat" || octocat.Email != "octo@example.com" || octocat.Username != "octocat" {
+		t.Errorf("Expected 'octocat' profile with the prompted identity, got %+v", octocat)
+	}
+
+	if storedTokens["octocat"] != "gho_abc" {
+		t.Errorf("Expected the gh token to be stored for 'octocat', got %v", storedTokens)
+	}
+
+	if _, ok := mockCfg.Profiles["client"]; !ok {
+		t.Error("Expected 'client' to be imported without a rule.")
+	}
+}
+
+func TestImportCommand_Yes(t *testing.T) {
+	mockCfg := &config.Config{Profiles: make(map[string]*config.Profile)}
+
+	runner := &importRunner{
+		load: func() (*config.Config, error) { return mockCfg, nil },
+		save: func(c *config.Config) error { return nil },
+		discover: func() ([]config.ImportCandidate, []error) {
+			return []config.ImportCandidate{
+				{Name: "github-work", Source: "Host github-work in ssh config",
+					Profile: config.Profile{SSHKey: "~/.ssh/id_work"}},
+			}, nil
+		},
+		stdin: strings.NewReader(""),
+	}
+
+	importDryRun = true
+
+	output := captureOutput(t, "", func() { _ = runner.run(&cobra.Command{}, []string{}) })
+	if !strings.Contains(output, "needs a name and email: import it without --yes") {
+		t.Errorf("Expected --dry-run to point out the candidate needs interactive import, got:\n%s", output)
+	}
+
+	importDryRun, importYes = false, true
+
+	defer func() { importYes = false }()
+
+	output = captureOutput(t, "", func() { _ = runner.run(&cobra.Command{}, []string{}) })
+	if len(mockCfg.Profiles) != 0 || !strings.Contains(output, "run 'gitego import' without --yes") {
+		t.Errorf("Expected --yes to skip the candidate with a hint, got %v and output:\n%s", mockCfg.Profiles, output)
+	}
+}
@@ -0,0 +1,778 @@
+// cmd/init.go
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/bgreenwell/gitego/utils"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// The ways init can set gitego up as a Git credential helper.
+const (
+	helperChain   = "chain"
+	helperReplace = "replace"
+	helperSkip    = "skip"
+)
+
+// gitegoCredentialHelper is the credential.helper value that runs gitego.
+const gitegoCredentialHelper = "!gitego credential"
+
+var (
+	initNonInteractive bool
+	initAnswersFile    string
+	initHelperMode     string
+	initProfiles       []string
+	initAutoRules      []string
+	initActiveProfile  string
+	initInstallHooks   bool
+	initForce          bool
+)
+
+// initAnswers holds every choice the setup wizard makes. In non-interactive mode they
+// come from an answers file and flags instead of prompts.
+type initAnswers struct {
+	CredentialHelper string                     `yaml:"credential_helper"`
+	Profiles         map[string]*config.Profile `yaml:"profiles"`
+	// TokenEnv maps a profile to the environment variable holding its Personal Access
+	// Token, so tokens never have to be written to the answers file.
+	TokenEnv      map[string]string  `yaml:"token_env"`
+	ActiveProfile string             `yaml:"active_profile"`
+	AutoRules     []*config.AutoRule `yaml:"auto_rules"`
+	InstallHooks  bool               `yaml:"install_hooks"`
+
+	// tokens holds the tokens typed in interactively, by profile.
+	tokens map[string]string
+}
+
+// initRunner holds the dependencies for the init command for mocking.
+type initRunner struct {
+	load                func() (*config.Config, error)
+	save                func(*config.Config) error
+	setToken            func(string, string) error
+	getGlobalGitAll     func(string) ([]string, error)
+	replaceGlobalGitAll func(string, []string) error
+	readAnswers         func(string) (*initAnswers, error)
+	readSecret          func(string) (string, error)
+	getenv              func(string) string
+	findRepos           func(string) ([]string, error)
+	installHook         func(string, *bufio.Reader) error
+	loadPolicy          func(*config.Config) (*config.Policy, error)
+	auto                *autoRunner
+	use                 *useRunner
+	doctor              *doctorRunner
+	stdin               io.Reader
+
+	// failure is the first setup step that failed. init carries on with the other steps
+	// and reports it once they are done.
+	failure error
+}
+
+// run is the core logic for the init command.
+func (r *initRunner) run(cmd *cobra.Command, args []string) error {
+	reader := bufio.NewReader(r.stdin)
+
+	var (
+		answers *initAnswers
+		err     error
+	)
+
+	if initNonInteractive {
+		answers, err = r.flagAnswers()
+	} else {
+		answers, err = r.ask(reader)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := r.apply(cmd, answers, reader); err != nil {
+		return err
+	}
+
+	fmt.Println("\nChecking your setup...")
+
+	doctorErr := r.doctor.run(cmd, nil)
+
+	if r.failure != nil {
+		return &errors.Error{Kind: errors.KindOf(r.failure), Message: "setup did not complete; see the errors above"}
+	}
+
+	return doctorErr
+}
+
+// fail reports a setup step that failed, so init can carry on with the others.
+func (r *initRunner) fail(err error) {
+	printError(os.Stderr, err)
+
+	if r.failure == nil {
+		r.failure = err
+	}
+}
+
+// flagAnswers builds the answers for --non-interactive mode from the answers file, if
+// any, with the command-line flags added on top.
+func (r *initRunner) flagAnswers() (*initAnswers, error) {
+	answers := &initAnswers{}
+
+	if initAnswersFile != "" {
+		var err error
+		if answers, err = r.readAnswers(initAnswersFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if initHelperMode != "" {
+		answers.CredentialHelper = initHelperMode
+	}
+
+	if answers.CredentialHelper == "" {
+		answers.CredentialHelper = helperChain
+	}
+
+	if !slices.Contains([]string{helperChain, helperReplace, helperSkip}, answers.CredentialHelper) {
+		return nil, errors.InvalidInput("invalid credential helper mode '%s'", answers.CredentialHelper).
+			WithHint("Use chain, replace or skip.")
+	}
+
+	for _, spec := range initProfiles {
+		name, profile, err := parseProfileSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		if answers.Profiles == nil {
+			answers.Profiles = make(map[string]*config.Profile)
+		}
+
+		answers.Profiles[name] = profile
+	}
+
+	for _, spec := range initAutoRules {
+		path, profileName, found := strings.Cut(spec, "=")
+		if !found || path == "" || profileName == "" {
+			return nil, errors.InvalidInput("invalid --auto '%s'; expected PATH=PROFILE", spec)
+		}
+
+		answers.AutoRules = append(answers.AutoRules, &config.AutoRule{Path: path, Profile: profileName})
+	}
+
+	if initActiveProfile != "" {
+		answers.ActiveProfile = initActiveProfile
+	}
+
+	answers.InstallHooks = answers.InstallHooks || initInstallHooks
+
+	return answers, nil
+}
+
+// ask walks the user through the setup and returns their answers.
+func (r *initRunner) ask(reader *bufio.Reader) (*initAnswers, error) {
+	cfg, err := r.load()
+	if err != nil {
+		return nil, errLoadConfig(err)
+	}
+
+	answers := &initAnswers{
+		Profiles: make(map[string]*config.Profile),
+		tokens:   make(map[string]string),
+	}
+
+	fmt.Println("Welcome to gitego! This will set up your Git identities step by step.")
+	fmt.Println("\n--- Credential helper ---")
+
+	answers.CredentialHelper = r.askCredentialHelper(reader)
+
+	fmt.Println("\n--- Profiles ---")
+
+	if len(cfg.Profiles) > 0 {
+		fmt.Printf("You already have these profiles: %s\n", strings.Join(sortedProfileNames(cfg), ", "))
+	}
+
+	var created []string
+
+	for {
+		question := "Create a profile? [Y/n]: "
+		if len(cfg.Profiles)+len(created) > 0 {
+			question = "Create another profile? [y/N]: "
+		}
+
+		fmt.Print(question)
+
+		if !readConfirm(reader, len(cfg.Profiles)+len(created) == 0) {
+			break
+		}
+
+		name := r.askProfile(cfg, answers, reader)
+		if name != "" {
+			created = append(created, name)
+		}
+	}
+
+	if cfg.ActiveProfile == "" && len(created) > 0 {
+		fmt.Printf("\nUse '%s' as your global default profile? [Y/n]: ", created[0])
+
+		if readConfirm(reader, true) {
+			answers.ActiveProfile = created[0]
+		}
+	}
+
+	if len(answers.AutoRules) > 0 {
+		fmt.Print("\nInstall the gitego hooks in the repositories under these directories? [Y/n]: ")
+		answers.InstallHooks = readConfirm(reader, true)
+	}
+
+	return answers, nil
+}
+
+// askCredentialHelper shows the configured credential helpers and asks how gitego should
+// join them.
+func (r *initRunner) askCredentialHelper(reader *bufio.Reader) string {
+	helpers, err := r.getGlobalGitAll("credential.helper")
+	if err != nil {
+		fmt.Printf("Warning: Could not read credential.helper: %v\n", err)
+
+		return helperSkip
+	}
+
+	if hasGitegoHelper(helpers) {
+		printSuccess("✓ gitego is already a Git credential helper.\n")
+
+		return helperSkip
+	}
+
+	var existing []string
+
+	for _, helper := range helpers {
+		if helper != "" {
+			existing = append(existing, helper)
+		}
+	}
+
+	if len(existing) == 0 {
+		fmt.Print("gitego needs to be your Git credential helper to supply tokens. Set it up? [Y/n]: ")
+
+		if readConfirm(reader, true) {
+			return helperChain
+		}
+
+		return helperSkip
+	}
+
+	fmt.Println("Git already uses these credential helpers:")
+
+	for _, helper := range existing {
+		fmt.Printf("  %s\n", helper)
+	}
+
+	fmt.Println("gitego can run first and fall back to them (chain), or be the only helper (replace).")
+
+	for {
+		choice := strings.ToLower(promptLine(reader, "Chain, replace or skip? [chain]: "))
+
+		switch choice {
+		case "", helperChain:
+			return helperChain
+		case helperReplace, helperSkip:
+			return choice
+		}
+
+		fmt.Println("Please answer chain, replace or skip.")
+	}
+}
+
+// askProfile asks for a single profile, and optionally a directory for it, and adds them
+// to answers. It returns the profile's name, or "" if it was abandoned.
+func (r *initRunner) askProfile(cfg *config.Config, answers *initAnswers, reader *bufio.Reader) string {
+	name := promptLine(reader, "  Profile name (e.g. work): ")
+	if name == "" {
+		return ""
+	}
+
+	if err := config.ValidateProfileName(name); err != nil {
+		fmt.Printf("  Skipping: %v.\n", err)
+
+		return ""
+	}
+
+	if _, exists := cfg.Profiles[name]; exists {
+		fmt.Printf("  Profile '%s' already exists; use 'gitego edit %s' to change it.\n", name, name)
+
+		return ""
+	}
+
+	if _, exists := answers.Profiles[name]; exists {
+		fmt.Printf("  Profile '%s' was already entered.\n", name)
+
+		return ""
+	}
+
+	profile := &config.Profile{
+		Name:  promptLine(reader, "  user.name: "),
+		Email: promptLine(reader, "  user.email: "),
+	}
+
+	if profile.Name == "" || profile.Email == "" {
+		fmt.Printf("  Skipping '%s': a name and email are required.\n", name)
+
+		return ""
+	}
+
+	profile.Username = promptLine(reader, "  Login username for HTTPS remotes (optional): ")
+
+	token, err := r.readSecret("  Personal Access Token (optional, stored in your OS keychain): ")
+	if err != nil {
+		fmt.Printf("  Warning: Could not read token: %v\n", err)
+	} else if token = strings.TrimSpace(token); token != "" {
+		answers.tokens[name] = token
+	}
+
+	answers.Profiles[name] = profile
+
+	path := promptLine(reader, "  Use it automatically inside which directory? (optional, e.g. ~/work/): ")
+	if path != "" {
+		answers.AutoRules = append(answers.AutoRules, &config.AutoRule{Path: path, Profile: name})
+	}
+
+	return name
+}
+
+// apply carries out the answers. Steps that fail are reported with fail; it returns an
+// error only if setup could not go far enough for the doctor checks to be worth running.
+func (r *initRunner) apply(cmd *cobra.Command, answers *initAnswers, reader *bufio.Reader) error {
+	fmt.Println()
+
+	cfg, err := r.load()
+	if err != nil {
+		return errLoadConfig(err)
+	}
+
+	r.setupCredentialHelper(cfg, answers.CredentialHelper)
+
+	if err := r.addProfiles(cfg, answers); err != nil {
+		return err
+	}
+
+	for _, rule := range answers.AutoRules {
+		r.addRule(cfg, rule)
+	}
+
+	if answers.ActiveProfile != "" {
+		if err := r.use.run(cmd, []string{answers.ActiveProfile}); err != nil {
+			r.fail(err)
+		}
+	}
+
+	if answers.InstallHooks {
+		for _, rule := range answers.AutoRules {
+			r.installHooks(rule.Path, reader)
+		}
+	}
+
+	return nil
+}
+
+// setupCredentialHelper makes gitego a Git credential helper, chained in front of the
+// existing helpers or replacing them. The helpers it found are recorded in cfg so that
+// 'gitego uninstall' can put them back.
+func (r *initRunner) setupCredentialHelper(cfg *config.Config, mode string) {
+	if mode == "" || mode == helperSkip {
+		return
+	}
+
+	helpers, err := r.getGlobalGitAll("credential.helper")
+	if err != nil {
+		r.fail(errors.IO(err, "could not read credential.helper"))
+
+		return
+	}
+
+	if mode == helperChain && hasGitegoHelper(helpers) {
+		printSuccess("✓ gitego is already a Git credential helper.\n")
+
+		return
+	}
+
+	values := []string{"", gitegoCredentialHelper}
+	if mode == helperChain {
+		values = chainCredentialHelpers(helpers)
+	}
+
+	if len(cfg.PreviousCredentialHelpers) == 0 {
+		cfg.PreviousCredentialHelpers = slices.DeleteFunc(slices.Clone(helpers), func(helper string) bool {
+			return strings.Contains(helper, "gitego credential")
+		})
+
+		if err := r.save(cfg); err != nil {
+			fmt.Printf("Warning: Could not record the previous credential helpers: %v\n", err)
+		}
+	}
+
+	if err := r.replaceGlobalGitAll("credential.helper", values); err != nil {
+		r.fail(errors.IO(err, "could not set credential.helper"))
+
+		return
+	}
+
+	if mode == helperChain && len(values) > 2 {
+		printSuccess("✓ gitego added as your Git credential helper, ahead of your existing helpers.\n")
+
+		return
+	}
+
+	printSuccess("✓ gitego set as your Git credential helper.\n")
+}
+
+// addProfiles adds the answered profiles to cfg, saves it and stores their tokens. It
+// returns an error only if the config could not be saved.
+func (r *initRunner) addProfiles(cfg *config.Config, answers *initAnswers) error {
+	if len(answers.Profiles) == 0 {
+		return nil
+	}
+
+	var added []string
+
+	for _, name := range sortedKeys(answers.Profiles) {
+		profile := answers.Profiles[name]
+
+		if _, exists := cfg.Profiles[name]; exists {
+			fmt.Printf("Profile '%s' already exists; leaving it unchanged.\n", name)
+
+			continue
+		}
+
+		if profile == nil || profile.Name == "" || profile.Email == "" {
+			r.fail(errors.InvalidInput("skipping profile '%s': a name and email are required", name))
+
+			continue
+		}
+
+		if err := checkProfile(name, profile, initForce); err != nil {
+			r.fail(err)
+
+			continue
+		}
+
+		token := r.profileToken(answers, name)
+
+		if err := enforcePolicy(r.loadPolicy, cfg, func(p *config.Policy) []config.PolicyViolation {
+			return p.CheckProfile(cfg, name, profile, token != "")
+		}); err != nil {
+			r.fail(err)
+
+			continue
+		}
+
+		if cfg.Profiles == nil {
+			cfg.Profiles = make(map[string]*config.Profile)
+		}
+
+		cfg.Profiles[name] = profile
+		added = append(added, name)
+	}
+
+	if err := r.save(cfg); err != nil {
+		return errSaveConfig(err)
+	}
+
+	for _, name := range added {
+		if token := r.profileToken(answers, name); token != "" {
+			if err := r.setToken(name, token); err != nil {
+				r.fail(errKeyring(err, "could not store the PAT for '%s' securely", name))
+			}
+		}
+
+		printSuccess("✓ Profile '%s' added.\n", name)
+	}
+
+	return nil
+}
+
+// profileToken returns the token for a profile, typed in or read from its token_env variable.
+func (r *initRunner) profileToken(answers *initAnswers, name string) string {
+	if token := answers.tokens[name]; token != "" {
+		return token
+	}
+
+	if variable := answers.TokenEnv[name]; variable != "" {
+		return r.getenv(variable)
+	}
+
+	return ""
+}
+
+// addRule maps a directory to a profile, as 'gitego auto' does.
+func (r *initRunner) addRule(cfg *config.Config, rule *config.AutoRule) {
+	profile, exists := cfg.Profiles[rule.Profile]
+	if !exists {
+		r.fail(errors.NotFound("skipping rule for '%s': profile '%s' not found", rule.Path, rule.Profile))
+
+		return
+	}
+
+	cleanPath, err := r.auto.processPath(rule.Path)
+	if err != nil {
+		r.fail(errors.InvalidInput("could not resolve path '%s': %v", rule.Path, err))
+
+		return
+	}
+
+	if r.auto.ruleExists(cfg, cleanPath, rule.Profile, rule.Path) {
+		return
+	}
+
+	if err := enforcePolicy(r.loadPolicy, cfg, func(p *config.Policy) []config.PolicyViolation {
+		return p.CheckRule(cleanPath, profile, r.profileHasToken(rule.Profile))
+	}); err != nil {
+		r.fail(err)
+
+		return
+	}
+
+	if err := r.auto.setupAutoRule(cfg, rule.Profile, profile, cleanPath, rule.Signoff); err != nil {
+		r.fail(err)
+
+		return
+	}
+
+	printSuccess("✓ Rule setup complete.\n")
+}
+
+// profileHasToken reports whether a profile has a token in the vault.
+func (r *initRunner) profileHasToken(profileName string) bool {
+	if r.auto.getToken == nil {
+		return false
+	}
+
+	token, err := r.auto.getToken(profileName)
+
+	return err == nil && token != ""
+}
+
+// installHooks installs the gitego hooks in every repository under path.
+func (r *initRunner) installHooks(path string, reader *bufio.Reader) {
+	repos, err := r.findRepos(config.ExpandHome(path))
+	if err != nil {
+		fmt.Printf("Warning: Could not search '%s' for repositories: %v\n", path, err)
+
+		return
+	}
+
+	if len(repos) == 0 {
+		fmt.Printf("No repositories found under '%s' yet; run 'gitego install-hook' in new clones.\n", path)
+
+		return
+	}
+
+	for _, repo := range repos {
+		if err := r.installHook(repo, reader); err != nil {
+			r.fail(err)
+		}
+	}
+}
+
+// chainCredentialHelpers returns helpers with gitego inserted first in the chain Git will
+// actually use, that is after the last empty value that resets the list.
+func chainCredentialHelpers(helpers []string) []string {
+	start := 0
+
+	for i, helper := range helpers {
+		if helper == "" {
+			start = i + 1
+		}
+	}
+
+	chained := slices.Clone(helpers[:start])
+	chained = append(chained, gitegoCredentialHelper)
+
+	for _, helper := range helpers[start:] {
+		if !strings.Contains(helper, "gitego credential") {
+			chained = append(chained, helper)
+		}
+	}
+
+	return chained
+}
+
+// hasGitegoHelper reports whether gitego is among the credential helpers.
+func hasGitegoHelper(helpers []string) bool {
+	return slices.ContainsFunc(helpers, func(helper string) bool {
+		return strings.Contains(helper, "gitego credential")
+	})
+}
+
+// parseProfileSpec parses a --profile value of the form NAME=Full Name <email>.
+func parseProfileSpec(spec string) (string, *config.Profile, error) {
+	name, identity, found := strings.Cut(spec, "=")
+
+	open := strings.LastIndex(identity, "<")
+	if !found || name == "" || open < 0 || !strings.HasSuffix(identity, ">") {
+		return "", nil, errors.InvalidInput("invalid --profile '%s'; expected NAME='Full Name <email>'", spec)
+	}
+
+	if err := config.ValidateProfileName(name); err != nil {
+		return "", nil, errors.InvalidInput("invalid --profile '%s': %v", spec, err)
+	}
+
+	profile := &config.Profile{
+		Name:  strings.TrimSpace(identity[:open]),
+		Email: strings.TrimSpace(identity[open+1 : len(identity)-1]),
+	}
+
+	if profile.Name == "" || profile.Email == "" {
+		return "", nil, errors.InvalidInput("invalid --profile '%s'; a name and email are required", spec)
+	}
+
+	return name, profile, nil
+}
+
+// readConfirm reads a yes/no answer, returning defaultYes for an empty line.
+func readConfirm(reader *bufio.Reader, defaultYes bool) bool {
+	response, _ := reader.ReadString('\n')
+
+	switch strings.TrimSpace(strings.ToLower(response)) {
+	case "":
+		return defaultYes
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// readInitAnswers reads an answers file for 'gitego init --non-interactive'.
+func readInitAnswers(path string) (*initAnswers, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.IO(err, "could not read answers file")
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	answers := &initAnswers{}
+	if err := decoder.Decode(answers); err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.InvalidInput("could not parse answers file %s: %v", path, err)
+	}
+
+	return answers, nil
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// initCmd represents the init command.
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Walks you through setting up gitego.",
+	Long: `Sets up gitego step by step. It makes gitego your Git credential helper,
+either chained in front of the helpers you already use or replacing them, then
+helps you create profiles, map directories to them and install the gitego
+hooks in the repositories there. It finishes by running 'gitego doctor'.
+
+For provisioning scripts, --non-interactive applies an answers file and flags
+without prompting:
+
+  credential_helper: chain        # chain, replace or skip
+  profiles:
+    work:
+      name: Work User
+      email: work@corp.com
+      username: work-user
+  token_env:
+    work: WORK_TOKEN              # read the PAT from $WORK_TOKEN
+  active_profile: work
+  auto_rules:
+    - path: ~/work/
+      profile: work
+  install_hooks: true
+
+Flags add to the answers file, for example:
+
+  $ gitego init --non-interactive --profile 'work=Work User <work@corp.com>' \
+      --auto ~/work/=work --use work --install-hooks
+
+New profiles are validated like 'gitego add' does; --force adds them even if
+their email, username or keys fail validation.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &initRunner{
+			load:                config.Load,
+			save:                func(c *config.Config) error { return c.Save() },
+			setToken:            config.SetToken,
+			getGlobalGitAll:     utils.GetGlobalGitConfigAll,
+			replaceGlobalGitAll: utils.ReplaceGlobalGitConfigAll,
+			readAnswers:         readInitAnswers,
+			readSecret:          readSecret,
+			getenv:              os.Getenv,
+			findRepos:           findGitRepos,
+			installHook:         installGitegoHooks,
+			loadPolicy:          config.LoadPolicy,
+			auto: &autoRunner{
+				save:                   func(c *config.Config) error { return c.Save() },
+				ensureProfileGitconfig: config.EnsureProfileGitconfig,
+				addIncludeIf:           config.AddIncludeIf,
+				getToken:               config.GetToken,
+			},
+			use: &useRunner{
+				load:             config.Load,
+				save:             func(c *config.Config) error { return c.Save() },
+				setGlobalGit:     utils.SetGlobalGitConfig,
+				unsetGlobalGit:   utils.UnsetGlobalGitConfig,
+				setGitCredential: config.SetGitCredential,
+				getOS:            func() string { return runtime.GOOS },
+				getToken:         config.GetToken,
+				loadPolicy:       config.LoadPolicy,
+			},
+			doctor: &doctorRunner{
+				load:            config.Load,
+				getGlobalGitAll: utils.GetGlobalGitConfigAll,
+				listGPGKeys:     utils.ListGPGSecretKeys,
+				stat:            os.Stat,
+				getToken:        config.GetToken,
+				loadPolicy:      config.LoadPolicy,
+			},
+			stdin: os.Stdin,
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().BoolVar(&initNonInteractive, "non-interactive", false,
+		"Apply the answers file and flags without prompting")
+	initCmd.Flags().StringVar(&initAnswersFile, "answers", "", "YAML answers file for --non-interactive")
+	initCmd.Flags().StringVar(&initHelperMode, "credential-helper", "",
+		"How to add gitego as a credential helper: chain (default), replace or skip")
+	initCmd.Flags().StringArrayVar(&initProfiles, "profile", nil,
+		"Create a profile, as NAME='Full Name <email>' (repeatable)")
+	initCmd.Flags().StringArrayVar(&initAutoRules, "auto", nil, "Map a directory to a profile, as PATH=PROFILE (repeatable)")
+	initCmd.Flags().StringVar(&initActiveProfile, "use", "", "Profile to set as the global default")
+	initCmd.Flags().BoolVar(&initInstallHooks, "install-hooks", false,
+		"Install the gitego hooks in repositories under the mapped directories")
+	initCmd.Flags().BoolVar(&initForce, "force", false,
+		"Add profiles even if their email, username or keys fail validation")
+}
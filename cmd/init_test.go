@@ -0,0 +1,274 @@
+// cmd/init_test.go
+
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/spf13/cobra"
+)
+
+// newTestInitRunner returns an initRunner backed by mockCfg that records the credential
+// helpers it sets and the hooks it installs.
+func newTestInitRunner(mockCfg *config.Config, helpers []string, stdin string) (*initRunner, *[]string, *[]string) {
+	setHelpers := slices.Clone(helpers)
+
+	var hooks []string
+
+	load := func() (*config.Config, error) { return mockCfg, nil }
+	save := func(c *config.Config) error { return nil }
+	getToken := func(string) (string, error) { return "", nil }
+
+	runner := &initRunner{
+		load:            load,
+		save:            save,
+		setToken:        func(string, string) error { return nil },
+		getGlobalGitAll: func(string) ([]string, error) { return setHelpers, nil },
+		replaceGlobalGitAll: func(key string, values []string) error {
+			setHelpers = values
+
+			return nil
+		},
+		readSecret:  func(string) (string, error) { return "", nil },
+		getenv:      func(string) string { return "" },
+		findRepos:   func(root string) ([]string, error) { return []string{filepath.Join(root, "repo")}, nil },
+		installHook: func(repo string, _ *bufio.Reader) error { hooks = append(hooks, repo); return nil },
+		auto: &autoRunner{
+			save:                   save,
+			ensureProfileGitconfig: func(string, *config.Profile) error { return nil },
+			addIncludeIf:           func(string, string) error { return nil },
+			getToken:               getToken,
+		},
+		use: &useRunner{
+			load:             load,
+			save:             save,
+			setGlobalGit:     func(string, string) error { return nil },
+			setGitCredential: func(string, string) error { return nil },
+			getOS:            func() string { return "linux" },
+			getToken:         getToken,
+		},
+		doctor: &doctorRunner{
+			load:            load,
+			getGlobalGitAll: func(string) ([]string, error) { return setHelpers, nil },
+			stat:            func(string) (os.FileInfo, error) { return nil, nil },
+			getToken:        getToken,
+		},
+		stdin: strings.NewReader(stdin),
+	}
+
+	return runner, &setHelpers, &hooks
+}
+
+func TestInitCommand_Interactive(t *testing.T) {
+	mockCfg := &config.Config{Profiles: map[string]*config.Profile{}}
+
+	// Chain with osxkeychain, create "work" mapped to /src/work/, decline another profile,
+	// make it the default and install hooks.
+	input := "\ny\nwork\nWork User\nwork@corp.com\nwork-user\n/src/work/\nn\n\n\n"
+	runner, helpers, hooks := newTestInitRunner(mockCfg, []string{"osxkeychain"}, input)
+
+	err := runner.run(&cobra.Command{}, []string{})
+
+	expectedHelpers := []string{gitegoCredentialHelper, "osxkeychain"}
+	if !slices.Equal(*helpers, expectedHelpers) {
+		t.Errorf("Expected helpers %q, got %q", expectedHelpers, *helpers)
+	}
+
+	if !slices.Equal(mockCfg.PreviousCredentialHelpers, []string{"osxkeychain"}) {
+		t.Errorf("Expected the previous helpers to be recorded, got %q", mockCfg.PreviousCredentialHelpers)
+	}
+
+	profile := mockCfg.Profiles["work"]
+	if profile == nil || profile.Email != "work@corp.com" || profile.Username != "work-user" {
+		t.Fatalf("Expected profile 'work' to be created, got %+v", profile)
+	}
+
+	if len(mockCfg.AutoRules) != 1 || mockCfg.AutoRules[0].Path != "/src/work/" {
+		t.Errorf("Expected a rule for /src/work/, got %+v", mockCfg.AutoRules)
+	}
+
+	if mockCfg.ActiveProfile != "work" {
+		t.Errorf("Expected active profile 'work', got %q", mockCfg.ActiveProfile)
+	}
+
+	if !slices.Equal(*hooks, []string{"/src/work/repo"}) {
+		t.Errorf("Expected a hook in /src/work/repo, got %v", *hooks)
+	}
+
+	if err != nil {
+		t.Errorf("Expected the doctor checks to pass, got %v", err)
+	}
+}
+
+func TestInitCommand_NonInteractive(t *testing.T) {
+	mockCfg := &config.Config{Profiles: map[string]*config.Profile{}}
+	runner, helpers, hooks := newTestInitRunner(mockCfg, nil, "")
+
+	storedTokens := make(map[string]string)
+	runner.setToken = func(name, token string) error { storedTokens[name] = token; return nil }
+	runner.getenv = func(name string) string {
+		if name == "WORK_TOKEN" {
+			return "ghp_work"
+		}
+
+		return ""
+	}
+	runner.readAnswers = func(string) (*initAnswers, error) {
+		return &initAnswers{
+			CredentialHelper: helperReplace,
+			Profiles: map[string]*config.Profile{
+				"work": {Name: "Work User", Email: "work@corp.com"},
+			},
+			TokenEnv: map[string]string{"work": "WORK_TOKEN"},
+		}, nil
+	}
+
+	initNonInteractive = true
+	initAnswersFile = "answers.yaml"
+	initProfiles = []string{"oss=Open Source <me@example.com>"}
+	initAutoRules = []string{"/src/oss/=oss"}
+	initActiveProfile = "work"
+
+	defer func() {
+		initNonInteractive = false
+		initAnswersFile = ""
+		initProfiles = nil
+		initAutoRules = nil
+		initActiveProfile = ""
+	}()
+
+	runner.run(&cobra.Command{}, []string{})
+
+	if !slices.Equal(*helpers, []string{"", gitegoCredentialHelper}) {
+		t.Errorf("Expected gitego to replace the helpers, got %q", *helpers)
+	}
+
+	if mockCfg.Profiles["work"] == nil || mockCfg.Profiles["oss"] == nil {
+		t.Fatalf("Expected profiles 'work' and 'oss', got %v", sortedProfileNames(mockCfg))
+	}
+
+	if storedTokens["work"] != "ghp_work" {
+		t.Errorf("Expected the token from WORK_TOKEN to be stored, got %v", storedTokens)
+	}
+
+	if len(mockCfg.AutoRules) != 1 || mockCfg.AutoRules[0].Profile != "oss" {
+		t.Errorf("Expected a rule for 'oss', got %+v", mockCfg.AutoRules)
+	}
+
+	if mockCfg.ActiveProfile != "work" {
+		t.Errorf("Expected active profile 'work', got %q", mockCfg.ActiveProfile)
+	}
+
+	if len(*hooks) != 0 {
+		t.Errorf("Expected no hooks without --install-hooks, got %v", *hooks)
+	}
+}
+
+func TestInitCommand_InvalidFlags(t *testing.T) {
+	mockCfg := &config.Config{Profiles: map[string]*config.Profile{}}
+	runner, helpers, _ := newTestInitRunner(mockCfg, nil, "")
+
+	initNonInteractive = true
+	initProfiles = []string{"work=no email"}
+
+	defer func() {
+		initNonInteractive = false
+		initProfiles = nil
+	}()
+
+	err := runner.run(&cobra.Command{}, []string{})
+	if errors.KindOf(err) != errors.KindInvalidInput {
+		t.Errorf("Expected an invalid input error, got %v", err)
+	}
+
+	if len(*helpers) != 0 || len(mockCfg.Profiles) != 0 {
+		t.Error("Expected nothing to change with an invalid --profile.")
+	}
+}
+
+func TestInitCommand_InvalidProfiles(t *testing.T) {
+	mockCfg := &config.Config{Profiles: map[string]*config.Profile{}}
+	runner, _, _ := newTestInitRunner(mockCfg, nil, "")
+
+	runner.readAnswers = func(string) (*initAnswers, error) {
+		return &initAnswers{
+			Profiles: map[string]*config.Profile{
+				"../../x": {Name: "A", Email: "a@b.c"},
+				"work":    {Name: "Work User", Email: "work at corp"},
+				"oss":     {Name: "Open Source", Email: "me@example.com"},
+			},
+			AutoRules: []*config.AutoRule{{Path: "/src/x/", Profile: "../../x"}},
+		}, nil
+	}
+
+	initNonInteractive = true
+	initAnswersFile = "answers.yaml"
+
+	defer func() {
+		initNonInteractive = false
+		initAnswersFile = ""
+	}()
+
+	if err := runner.run(&cobra.Command{}, []string{}); err == nil {
+		t.Error("Expected the invalid profiles to fail the setup.")
+	}
+
+	if len(mockCfg.Profiles) != 1 || mockCfg.Profiles["oss"] == nil || len(mockCfg.AutoRules) != 0 {
+		t.Errorf("Expected only 'oss' to be added, without rules, got %v and %v",
+			sortedProfileNames(mockCfg), mockCfg.AutoRules)
+	}
+
+	// Interactively, the name is rejected before any field is asked for.
+	mockCfg = &config.Config{Profiles: map[string]*config.Profile{}}
+	runner, _, _ = newTestInitRunner(mockCfg, nil, "")
+
+	output := captureOutput(t, "", func() {
+		name := runner.askProfile(mockCfg, &initAnswers{Profiles: map[string]*config.Profile{}},
+			bufio.NewReader(strings.NewReader("../x\nA\na@b.c\n")))
+		if name != "" {
+			t.Errorf("Expected no profile, got %q", name)
+		}
+	})
+
+	if !strings.Contains(output, "not a valid profile name") || strings.Contains(output, "user.name") {
+		t.Errorf("Expected the name to be rejected before prompting, got:\n%s", output)
+	}
+}
+
+func TestChainCredentialHelpers(t *testing.T) {
+	tests := []struct {
+		helpers  []string
+		expected []string
+	}{
+		{nil, []string{gitegoCredentialHelper}},
+		{[]string{"osxkeychain"}, []string{gitegoCredentialHelper, "osxkeychain"}},
+		{[]string{"store", "", "manager"}, []string{"store", "", gitegoCredentialHelper, "manager"}},
+		{[]string{"", "!gitego credential", "cache"}, []string{"", gitegoCredentialHelper, "cache"}},
+	}
+
+	for _, tt := range tests {
+		if got := chainCredentialHelpers(tt.helpers); !slices.Equal(got, tt.expected) {
+			t.Errorf("chainCredentialHelpers(%q) = %q, want %q", tt.helpers, got, tt.expected)
+		}
+	}
+}
+
+func TestParseProfileSpec(t *testing.T) {
+	name, profile, err := parseProfileSpec("work=Work User <work@corp.com>")
+	if err != nil || name != "work" || profile.Name != "Work User" || profile.Email != "work@corp.com" {
+		t.Errorf("Unexpected result: %q %+v %v", name, profile, err)
+	}
+
+	for _, spec := range []string{"work", "work=Work User", "=Work <w@c.com>", "work=<w@c.com>", "../../x=A <a@b.c>"} {
+		if _, _, err := parseProfileSpec(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
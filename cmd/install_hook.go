@@ -6,13 +6,24 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/bgreenwell/gitego/errors"
 	"github.com/spf13/cobra"
 )
 
-// The content of the shell script we'll write or append.
-const hookScriptContent = `
+// Every block gitego writes into a hook is wrapped in these markers so that it can be
+// removed again without touching the rest of the script.
+const (
+	hookBeginMarker = "# >>> gitego >>>"
+	hookEndMarker   = "# <<< gitego <<<"
+	// hookCreatedMarker is written below the shebang of hooks gitego creates itself.
+	hookCreatedMarker = "# This hook was created by gitego."
+)
+
+// legacyHookScriptContent is the unmarked block older versions appended to pre-commit hooks.
+const legacyHookScriptContent = `
 # gitego pre-commit hook
 # This command checks your commit author against the expected profile.
 # If there's a mismatch, it will prompt you before committing.
@@ -21,96 +32,231 @@ gitego internal check-commit
 const (
 	// executableFilePermissions are the permissions for an executable file.
 	executableFilePermissions = 0755
+	// filePermissions are the permissions for config files gitego edits in a repository.
+	filePermissions = 0644
 )
 
+// gitHook describes a Git hook gitego can install.
+type gitHook struct {
+	name        string
+	description string
+	// command is what gitego's own block in .git/hooks runs.
+	command string
+	// entrypoint is the stable command hook managers such as pre-commit and husky run.
+	entrypoint string
+	// args are passed on from the hook script to the command, for hooks Git gives arguments.
+	args string
+}
+
+// preCommitHook checks the commit author before every commit.
+var preCommitHook = gitHook{
+	name: "pre-commit",
+	description: `# This command checks your commit author against the expected profile.
+# If there's a mismatch, it will prompt you before committing.`,
+	command:    "gitego internal check-commit",
+	entrypoint: "gitego hook pre-commit",
+}
+
+// commitMsgHook checks and adds the sign-off and pairing trailers of the commit message.
+var commitMsgHook = gitHook{
+	name: "commit-msg",
+	description: `# This command checks the Signed-off-by trailers against the commit author
+# and adds the sign-off and Co-authored-by trailers your profile calls for.`,
+	command:    "gitego hook commit-msg",
+	entrypoint: "gitego hook commit-msg",
+	args:       `"$1"`,
+}
+
+// gitegoHooks lists every hook type gitego installs.
+var gitegoHooks = []gitHook{preCommitHook, commitMsgHook}
+
+// block returns the marked block, running command, that gitego writes into a hook script.
+func (h gitHook) block(command string) string {
+	return fmt.Sprintf("%s\n%s\n%s\n%s\n", hookBeginMarker, h.description, h.commandLine(command), hookEndMarker)
+}
+
+// commandLine returns the line a hook script runs command with, passing on the hook's arguments.
+func (h gitHook) commandLine(command string) string {
+	if h.args == "" {
+		return command
+	}
+
+	return command + " " + h.args
+}
+
+// runsIn reports whether a hook script runs this gitego hook.
+func (h gitHook) runsIn(script string) bool {
+	return strings.Contains(script, h.command) || strings.Contains(script, h.entrypoint)
+}
+
+// path returns the location of the hook script in the repository at gitRoot.
+func (h gitHook) path(gitRoot string) string {
+	return filepath.Join(gitRoot, ".git", "hooks", h.name)
+}
+
+// installed reports whether the repository at gitRoot runs the hook, either from
+// .git/hooks or through a hook manager.
+func (h gitHook) installed(gitRoot string) bool {
+	if fileRunsHook(h.path(gitRoot), h) {
+		return true
+	}
+
+	return slices.ContainsFunc(hookFrameworks, func(f hookFramework) bool { return f.installed(gitRoot, h) })
+}
+
 var installHookCmd = &cobra.Command{
 	Use:   "install-hook",
-	Short: "Installs the pre-commit hook to safeguard against misattributed commits.",
-	Long: `Installs a pre-commit hook in the current Git repository.
+	Short: "Installs the gitego hooks to safeguard against misattributed commits.",
+	Long: `Installs gitego's pre-commit and commit-msg hooks in the current Git repository.
 
-This hook automatically runs before every commit to verify that your
+The pre-commit hook automatically runs before every commit to verify that your
 commit author details match the gitego profile expected for this directory.
 This provides a powerful safety net against accidental misattributed commits.
-If a pre-commit hook already exists, gitego will ask to append its command.`,
-	Run: func(cmd *cobra.Command, args []string) {
+The commit-msg hook checks and adds the Signed-off-by and Co-authored-by
+trailers set up with '--signoff' and 'gitego pair'; without them it does nothing.
+If a hook already exists, gitego will ask to append its command.
+
+If the repository manages its hooks with the pre-commit framework
+(.pre-commit-config.yaml) or husky (.husky/), gitego offers to add its hooks
+there instead, running 'gitego hook pre-commit' and 'gitego hook commit-msg',
+so that the hook manager doesn't overwrite them.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		gitRoot, err := findGitRoot(".")
 		if err != nil {
-			fmt.Println("Error: Not a git repository (or any of the parent directories).")
-
-			return
+			return errNotGitRepo()
 		}
 
-		hooksDir := filepath.Join(gitRoot, ".git", "hooks")
-		// It's possible the hooks directory doesn't exist in a fresh git init.
-		if err := os.MkdirAll(hooksDir, executableFilePermissions); err != nil {
-			fmt.Printf("Error: Could not create hooks directory: %v\n", err)
+		reader := bufio.NewReader(os.Stdin)
 
-			return
+		if framework := detectHookFramework(gitRoot); framework != nil {
+			fmt.Printf("This repository manages its hooks with %s (%s).\n", framework.name, framework.path)
+			fmt.Printf("Add the gitego hooks to %s instead of .git/hooks? [Y/n]: ", framework.path)
+
+			if readConfirm(reader, true) {
+				for _, hook := range gitegoHooks {
+					if err := installFrameworkHook(gitRoot, framework, hook); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			}
 		}
 
-		hookPath := filepath.Join(hooksDir, "pre-commit")
+		return installGitegoHooks(gitRoot, reader)
+	},
+}
 
-		// --- New, smarter hook installation logic ---
-		if _, err := os.Stat(hookPath); err == nil {
-			// File exists, so we need to check its content.
-			content, err := os.ReadFile(hookPath)
-			if err != nil {
-				fmt.Printf("Error: Could not read existing pre-commit hook: %v\n", err)
+// installFrameworkHook adds a gitego hook to the config of the repository's hook manager.
+func installFrameworkHook(gitRoot string, framework *hookFramework, hook gitHook) error {
+	added, err := framework.add(gitRoot, hook)
+	if err != nil {
+		return errors.IO(err, "could not add the gitego hook to %s", framework.path)
+	}
 
-				return
-			}
+	if !added {
+		printSuccess("✓ gitego %s hook is already in %s.\n", hook.name, framework.path)
 
-			if strings.Contains(string(content), "gitego internal check-commit") {
-				fmt.Println("✓ gitego pre-commit hook is already installed.")
+		return nil
+	}
 
-				return
-			}
+	printSuccess("✓ gitego %s hook added to %s.\n", hook.name, framework.path)
 
-			// Hook exists but is missing our command. Ask to append.
-			fmt.Print("A pre-commit hook already exists. Append gitego check? [Y/n]: ")
-			reader := bufio.NewReader(os.Stdin)
-			response, _ := reader.ReadString('\n')
+	if next := framework.activate(hook); next != "" {
+		fmt.Println(next)
+	}
 
-			if strings.TrimSpace(strings.ToLower(response)) == "n" {
-				fmt.Println("\nInstall cancelled. Please manually add the following line to your pre-commit hook:")
-				fmt.Println("  gitego internal check-commit")
+	return nil
+}
 
-				return
-			}
+// installGitegoHooks installs every gitego hook in the repository at gitRoot, asking on
+// reader before appending to an existing hook. It stops at the first hook that fails.
+func installGitegoHooks(gitRoot string, reader *bufio.Reader) error {
+	for _, hook := range gitegoHooks {
+		if err := installGitHook(gitRoot, hook, reader); err != nil {
+			return err
+		}
+	}
 
-			// User confirmed. Append to the existing file.
-			f, err := os.OpenFile(hookPath, os.O_APPEND|os.O_WRONLY, executableFilePermissions)
-			if err != nil {
-				fmt.Printf("Error: Failed to open existing hook for appending: %v\n", err)
+	return nil
+}
 
-				return
-			}
-			defer func() {
-				if err := f.Close(); err != nil {
-					fmt.Printf("Warning: Failed to close hook file: %v\n", err)
-				}
-			}()
+// installGitHook writes the hook's gitego block into the repository at gitRoot, asking on
+// reader before appending to an existing script.
+func installGitHook(gitRoot string, hook gitHook, reader *bufio.Reader) error {
+	hooksDir := filepath.Join(gitRoot, ".git", "hooks")
+	// It's possible the hooks directory doesn't exist in a fresh git init.
+	if err := os.MkdirAll(hooksDir, executableFilePermissions); err != nil {
+		return errors.IO(err, "could not create hooks directory")
+	}
 
-			if _, err := f.WriteString(hookScriptContent); err != nil {
-				fmt.Printf("Error: Failed to append to existing hook: %v\n", err)
+	hookPath := hook.path(gitRoot)
 
-				return
-			}
-			fmt.Printf("✓ gitego check appended successfully to %s\n", hookPath)
+	// --- New, smarter hook installation logic ---
+	if _, err := os.Stat(hookPath); err == nil {
+		// File exists, so we need to check its content.
+		content, err := os.ReadFile(hookPath)
+		if err != nil {
+			return errors.IO(err, "could not read existing %s hook", hook.name)
+		}
 
-		} else {
-			// File does not exist, create a new one.
-			// Prepend the shebang for a new script.
-			newHookContent := "#!/bin/sh" + hookScriptContent
-			err = os.WriteFile(hookPath, []byte(newHookContent), executableFilePermissions)
-			if err != nil {
-				fmt.Printf("Error installing hook: %v\n", err)
+		if hook.runsIn(string(content)) {
+			printSuccess("✓ gitego %s hook is already installed.\n", hook.name)
 
-				return
-			}
-			fmt.Printf("✓ gitego pre-commit hook installed successfully in %s\n", hookPath)
+			return nil
 		}
-	},
+
+		// Hook exists but is missing our command. Ask to append.
+		fmt.Printf("A %s hook already exists. Append gitego check? [Y/n]: ", hook.name)
+		response, _ := reader.ReadString('\n')
+
+		if strings.TrimSpace(strings.ToLower(response)) == "n" {
+			fmt.Printf("\nInstall cancelled. Please manually add the following line to your %s hook:\n", hook.name)
+			fmt.Printf("  %s\n", hook.commandLine(hook.command))
+
+			return nil
+		}
+
+		// User confirmed. Append to the existing file.
+		if err := appendHookBlock(hookPath, content, hook.block(hook.command)); err != nil {
+			return errors.IO(err, "could not append to existing %s hook", hook.name)
+		}
+		printSuccess("✓ gitego check appended successfully to %s\n", hookPath)
+
+	} else {
+		// File does not exist, create a new one.
+		// Prepend the shebang for a new script.
+		newHookContent := "#!/bin/sh\n" + hookCreatedMarker + "\n\n" + hook.block(hook.command)
+		err = os.WriteFile(hookPath, []byte(newHookContent), executableFilePermissions)
+		if err != nil {
+			return errors.IO(err, "could not install %s hook", hook.name)
+		}
+		printSuccess("✓ gitego %s hook installed successfully in %s\n", hook.name, hookPath)
+	}
+
+	return nil
+}
+
+// appendHookBlock appends a gitego block to the hook script at hookPath, whose current
+// content is given, separated from it by a blank line.
+func appendHookBlock(hookPath string, content []byte, block string) error {
+	f, err := os.OpenFile(hookPath, os.O_APPEND|os.O_WRONLY, executableFilePermissions)
+	if err != nil {
+		return err
+	}
+
+	separator := "\n"
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		separator = "\n\n"
+	}
+
+	if _, err := f.WriteString(separator + block); err != nil {
+		_ = f.Close()
+
+		return err
+	}
+
+	return f.Close()
 }
 
 // findGitRoot searches for the root of the git repository.
@@ -139,3 +285,86 @@ func findGitRoot(startDir string) (string, error) {
 func init() {
 	rootCmd.AddCommand(installHookCmd)
 }
+
+// removeGitHook removes gitego's block from the hook script in the repository at gitRoot,
+// leaving the rest of the script alone. A script gitego created is deleted once nothing
+// else is left in it. It reports whether anything was removed.
+func removeGitHook(gitRoot string, hook gitHook) (bool, error) {
+	return removeHookScript(hook.path(gitRoot), hook)
+}
+
+// removeHookScript removes gitego's block from the hook script at hookPath, deleting a
+// script gitego created once nothing else is left in it.
+func removeHookScript(hookPath string, hook gitHook) (bool, error) {
+	content, err := os.ReadFile(hookPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	original := string(content)
+	if !hook.runsIn(original) {
+		return false, nil
+	}
+
+	// Scripts written before markers were used get their old block removed instead.
+	created := strings.Contains(original, hookCreatedMarker) ||
+		original == "#!/bin/sh"+legacyHookScriptContent
+	remaining := removeHookBlocks(strings.ReplaceAll(original, legacyHookScriptContent, ""), hook)
+
+	if created && isEmptyHookScript(remaining) {
+		return true, os.Remove(hookPath)
+	}
+
+	return true, os.WriteFile(hookPath, []byte(remaining), executableFilePermissions)
+}
+
+// removeHookBlocks drops every marked gitego block, and any stray line running the hook,
+// from a hook script.
+func removeHookBlocks(script string, hook gitHook) string {
+	var kept []string
+
+	inBlock := false
+
+	for _, line := range strings.SplitAfter(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == hookBeginMarker:
+			inBlock = true
+		case trimmed == hookEndMarker:
+			inBlock = false
+		case !inBlock && !slices.Contains(hook.commandLines(), trimmed):
+			kept = append(kept, line)
+		}
+	}
+
+	// Drop the blank line that separated the block from the rest of the script.
+	remaining := strings.Join(kept, "")
+	for strings.HasSuffix(remaining, "\n\n") {
+		remaining = strings.TrimSuffix(remaining, "\n")
+	}
+
+	return remaining
+}
+
+// commandLines returns the lines by which a hook script can run this gitego hook.
+func (h gitHook) commandLines() []string {
+	return []string{h.command, h.entrypoint, h.commandLine(h.command), h.commandLine(h.entrypoint)}
+}
+
+// isEmptyHookScript reports whether a hook script has nothing left but its shebang and
+// gitego's own comments.
+func isEmptyHookScript(script string) bool {
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && trimmed != hookCreatedMarker && !strings.HasPrefix(trimmed, "#!") {
+			return false
+		}
+	}
+
+	return true
+}
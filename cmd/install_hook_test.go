@@ -155,7 +155,9 @@ func TestInstallHook(t *testing.T) {
 		defer cleanup()
 
 		output := captureOutput(t, "", func() {
-			installHookCmd.Run(installHookCmd, []string{})
+			if err := installHookCmd.RunE(installHookCmd, []string{}); err != nil {
+				t.Error(err)
+			}
 		})
 
 		validateHookCreation(t, hooksDir, output)
@@ -169,12 +171,37 @@ func TestInstallHook(t *testing.T) {
 		createExistingHook(hooksDir, initialContent)
 
 		output := captureOutput(t, "y\n", func() {
-			installHookCmd.Run(installHookCmd, []string{})
+			if err := installHookCmd.RunE(installHookCmd, []string{}); err != nil {
+				t.Error(err)
+			}
 		})
 
 		validateHookAppend(t, hooksDir, initialContent, output)
 	})
 
+	t.Run("when the repository uses husky", func(t *testing.T) {
+		repoRoot, hooksDir, cleanup := setupTestRepoAndChangeDir(t, originalWd)
+		defer cleanup()
+
+		if err := os.MkdirAll(filepath.Join(repoRoot, ".husky"), 0755); err != nil {
+			t.Fatalf("Failed to create .husky: %v", err)
+		}
+
+		output := captureOutput(t, "\n", func() {
+			if err := installHookCmd.RunE(installHookCmd, []string{}); err != nil {
+				t.Error(err)
+			}
+		})
+
+		if !strings.Contains(output, "hook added to .husky") {
+			t.Errorf("Expected the hook to be added to husky, but got: %s", output)
+		}
+
+		if _, err := os.Stat(filepath.Join(hooksDir, "pre-commit")); !os.IsNotExist(err) {
+			t.Error("Expected .git/hooks to be left alone.")
+		}
+	})
+
 	t.Run("when hook is already installed", func(t *testing.T) {
 		_, hooksDir, cleanup := setupTestRepoAndChangeDir(t, originalWd)
 		defer cleanup()
@@ -182,7 +209,9 @@ func TestInstallHook(t *testing.T) {
 		createExistingHook(hooksDir, "#!/bin/sh\ngitego internal check-commit\n")
 
 		output := captureOutput(t, "", func() {
-			installHookCmd.Run(installHookCmd, []string{})
+			if err := installHookCmd.RunE(installHookCmd, []string{}); err != nil {
+				t.Error(err)
+			}
 		})
 
 		if !strings.Contains(output, "already installed") {
@@ -4,8 +4,8 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"log"
-	"os"
 	"sort"
 	"strings"
 	"text/tabwriter"
@@ -23,82 +23,176 @@ const (
 	flags    = 0
 )
 
-// listCmd represents the list command.
-var listCmd = &cobra.Command{
-	Use:   "list",
-	Short: "Lists all saved user profiles and their attributes.",
-	Long: `Reads the gitego configuration file and displays a table of all saved profiles, 
-including their associated user name, email, and configured credentials (SSH, PAT).
-The globally active profile is marked with an asterisk (*).`,
-	Aliases: []string{"ls"}, // Users can run 'gitego ls' as a shortcut for 'gitego list'
-	Run: func(cmd *cobra.Command, args []string) {
-		cfg, err := config.Load()
-		if err != nil {
-			fmt.Printf("Error loading configuration: %v\n", err)
+// inheritedMarker follows a value that a profile takes from the profile it extends.
+const inheritedMarker = "^"
+
+var listLong bool
+
+// listRunner holds the dependencies for the list command for mocking.
+type listRunner struct {
+	load     func() (*config.Config, error)
+	getToken func(string) (string, error)
+}
+
+// run is the core logic for the list command.
+func (lr *listRunner) run(cmd *cobra.Command, args []string) error {
+	cfg, err := lr.load()
+	if err != nil {
+		return errLoadConfig(err)
+	}
+
+	if len(cfg.Profiles) == 0 {
+		fmt.Println("No profiles found. Use 'gitego add <profile_name>' to create one.")
+
+		return nil
+	}
 
-			return
+	profileNames := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		profileNames = append(profileNames, name)
+	}
+	sort.Strings(profileNames)
+
+	if listLong {
+		lr.printLong(cmd.OutOrStdout(), cfg, profileNames)
+
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), minwidth, tabwidth, padding, padchar, flags)
+
+	// New, more informative header
+	if _, err := fmt.Fprintln(w, "ACTIVE\tPROFILE\tNAME\tEMAIL\tATTRIBUTES"); err != nil {
+		log.Printf("Warning: Failed to write header: %v", err)
+	}
+	if _, err := fmt.Fprintln(w, "------\t-------\t----\t-----\t----------"); err != nil {
+		log.Printf("Warning: Failed to write separator: %v", err)
+	}
+
+	anyInherited := false
+
+	for _, name := range profileNames {
+		profile := cfg.Profiles[name]
+
+		// 1. Check if this is the active profile
+		activeMarker := " "
+		if name == cfg.ActiveProfile {
+			activeMarker = "*"
 		}
 
-		if len(cfg.Profiles) == 0 {
-			fmt.Println("No profiles found. Use 'gitego add <profile_name>' to create one.")
+		// 2. Check for associated credentials
+		var attributes []string
+		if profile.SSHKey != "" {
+			attributes = append(attributes, "[SSH"+inheritedSuffix(profile, "ssh_key")+"]")
+		}
+		// Check if a PAT exists in the keychain for this profile
+		if token, err := lr.getToken(name); err == nil && token != "" {
+			attributes = append(attributes, "[PAT]")
+		}
 
-			return
+		if profile.Extends != "" {
+			attributes = append(attributes, fmt.Sprintf("[extends %s]", profile.Extends))
 		}
 
-		profileNames := make([]string, 0, len(cfg.Profiles))
-		for name := range cfg.Profiles {
-			profileNames = append(profileNames, name)
+		anyInherited = anyInherited || len(profile.InheritedFields()) > 0
+
+		// 3. Print the enhanced row
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			activeMarker,
+			name,
+			profile.Name+inheritedSuffix(profile, "name"),
+			profile.Email+inheritedSuffix(profile, "email"),
+			strings.Join(attributes, " "),
+		); err != nil {
+			log.Printf("Warning: Failed to write profile row: %v", err)
 		}
-		sort.Strings(profileNames)
+	}
 
-		w := tabwriter.NewWriter(os.Stdout, minwidth, tabwidth, padding, padchar, flags)
-		defer func() {
-			if err := w.Flush(); err != nil {
-				log.Printf("Warning: Failed to flush output: %v", err)
-			}
-		}()
+	if err := w.Flush(); err != nil {
+		log.Printf("Warning: Failed to flush output: %v", err)
+	}
+
+	if anyInherited {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\n%s inherited from a base profile. Use 'gitego list --long' to see every field.\n",
+			inheritedMarker)
+	}
+
+	return nil
+}
 
-		// New, more informative header
-		if _, err := fmt.Fprintln(w, "ACTIVE\tPROFILE\tNAME\tEMAIL\tATTRIBUTES"); err != nil {
-			log.Printf("Warning: Failed to write header: %v", err)
+// printLong prints every field of each profile with its effective value.
+func (lr *listRunner) printLong(out io.Writer, cfg *config.Config, profileNames []string) {
+	for i, name := range profileNames {
+		profile := cfg.Profiles[name]
+
+		if i > 0 {
+			_, _ = fmt.Fprintln(out)
 		}
-		if _, err := fmt.Fprintln(w, "------\t-------\t----\t-----\t----------"); err != nil {
-			log.Printf("Warning: Failed to write separator: %v", err)
+
+		header := name
+		if name == cfg.ActiveProfile {
+			header += " (active)"
 		}
 
-		for _, name := range profileNames {
-			profile := cfg.Profiles[name]
+		if profile.Extends != "" {
+			header += fmt.Sprintf(" extends %s", profile.Extends)
+		}
 
-			// 1. Check if this is the active profile
-			activeMarker := " "
-			if name == cfg.ActiveProfile {
-				activeMarker = "*"
-			}
+		_, _ = fmt.Fprintf(out, "%s:\n", header)
 
-			// 2. Check for associated credentials
-			var attributes []string
-			if profile.SSHKey != "" {
-				attributes = append(attributes, "[SSH]")
-			}
-			// Check if a PAT exists in the keychain for this profile
-			if token, err := config.GetToken(name); err == nil && token != "" {
-				attributes = append(attributes, "[PAT]")
-			}
+		w := tabwriter.NewWriter(out, minwidth, tabwidth, padding, padchar, flags)
 
-			// 3. Print the enhanced row
-			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-				activeMarker,
-				name,
-				profile.Name,
-				profile.Email,
-				strings.Join(attributes, " "),
-			); err != nil {
-				log.Printf("Warning: Failed to write profile row: %v", err)
+		for _, field := range profile.FieldValues() {
+			marker := ""
+			if field.Inherited {
+				marker = " " + inheritedMarker
 			}
+
+			_, _ = fmt.Fprintf(w, "  %s\t%s%s\n", field.Name, field.Value, marker)
+		}
+
+		if token, err := lr.getToken(name); err == nil && token != "" {
+			_, _ = fmt.Fprintf(w, "  pat\t(stored in keychain)\n")
+		}
+
+		if err := w.Flush(); err != nil {
+			log.Printf("Warning: Failed to flush output: %v", err)
+		}
+	}
+}
+
+// inheritedSuffix returns the inherited marker if the profile takes the field from its base.
+func inheritedSuffix(profile *config.Profile, field string) string {
+	if profile.Inherits(field) {
+		return inheritedMarker
+	}
+
+	return ""
+}
+
+// listCmd represents the list command.
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists all saved user profiles and their attributes.",
+	Long: `Reads the gitego configuration file and displays a table of all saved profiles, 
+including their associated user name, email, and configured credentials (SSH, PAT).
+The globally active profile is marked with an asterisk (*).
+
+Profiles that extend a base profile show their effective values; values
+inherited from a base are marked with a caret (^). --long lists every field
+of each profile.`,
+	Aliases: []string{"ls"}, // Users can run 'gitego ls' as a shortcut for 'gitego list'
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &listRunner{
+			load:     config.Load,
+			getToken: config.GetToken,
 		}
+		return runner.run(cmd, args)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().BoolVarP(&listLong, "long", "l", false, "Show every field of each profile")
 }
@@ -5,88 +5,13 @@ package cmd
 import (
 	"bytes"
 	"fmt"
-	"sort"
 	"strings"
 	"testing"
-	"text/tabwriter"
 
 	"github.com/bgreenwell/gitego/config"
 	"github.com/spf13/cobra"
 )
 
-// listRunner holds the dependencies for the list command for mocking.
-type listRunner struct {
-	load     func() (*config.Config, error)
-	getToken func(string) (string, error)
-}
-
-// run executes the core logic of the list command.
-func (lr *listRunner) run(cmd *cobra.Command, args []string) {
-	cfg, err := lr.load()
-	if err != nil {
-		fmt.Printf("Error loading configuration: %v\n", err)
-
-		return
-	}
-
-	if len(cfg.Profiles) == 0 {
-		fmt.Println("No profiles found. Use 'gitego add <profile_name>' to create one.")
-
-		return
-	}
-
-	profileNames := make([]string, 0, len(cfg.Profiles))
-	for name := range cfg.Profiles {
-		profileNames = append(profileNames, name)
-	}
-
-	sort.Strings(profileNames)
-
-	// In the test, we write to the command's output stream.
-	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
-	defer func() {
-		if err := w.Flush(); err != nil {
-			fmt.Printf("Warning: Failed to flush output: %v\n", err)
-		}
-	}()
-
-	if _, err := fmt.Fprintln(w, "ACTIVE\tPROFILE\tNAME\tEMAIL\tATTRIBUTES"); err != nil {
-		fmt.Printf("Warning: Failed to write header: %v\n", err)
-	}
-	if _, err := fmt.Fprintln(w, "------\t-------\t----\t-----\t----------"); err != nil {
-		fmt.Printf("Warning: Failed to write separator: %v\n", err)
-	}
-
-	for _, name := range profileNames {
-		profile := cfg.Profiles[name]
-
-		activeMarker := " "
-		if name == cfg.ActiveProfile {
-			activeMarker = "*"
-		}
-
-		var attributes []string
-		if profile.SSHKey != "" {
-			attributes = append(attributes, "[SSH]")
-		}
-
-		// Use the mocked getToken function to check for a PAT.
-		if token, err := lr.getToken(name); err == nil && token != "" {
-			attributes = append(attributes, "[PAT]")
-		}
-
-		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-			activeMarker,
-			name,
-			profile.Name,
-			profile.Email,
-			strings.Join(attributes, " "),
-		); err != nil {
-			fmt.Printf("Warning: Failed to write profile row: %v\n", err)
-		}
-	}
-}
-
 // validateListHeaders checks if all expected headers are present in the output.
 func validateListHeaders(t *testing.T, output string) {
 	expectedHeaders := []string{"ACTIVE", "PROFILE", "NAME", "EMAIL", "ATTRIBUTES"}
@@ -196,3 +121,45 @@ func TestListCommand(t *testing.T) {
 	validatePersonalProfileLine(t, personalLine)
 	validateWorkProfileLine(t, workLine)
 }
+
+// TestListInheritedFields verifies that values taken from a base profile are marked.
+func TestListInheritedFields(t *testing.T) {
+	cfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"consultant": {Name: "Jane Doe", Email: "jane@example.com", SSHKey: "~/.ssh/id_consulting"},
+			"acme":       {Extends: "consultant", Email: "jane@acme.com"},
+		},
+	}
+	cfg.ResolveProfiles()
+
+	lr := &listRunner{
+		load:     func() (*config.Config, error) { return cfg, nil },
+		getToken: func(string) (string, error) { return "", fmt.Errorf("no token found") },
+	}
+
+	defer func() { listLong = false }()
+
+	for _, long := range []bool{false, true} {
+		listLong = long
+
+		var buf bytes.Buffer
+
+		cmd := &cobra.Command{}
+		cmd.SetOut(&buf)
+
+		lr.run(cmd, []string{})
+
+		output := buf.String()
+
+		expected := []string{"Jane Doe^", "jane@acme.com   ", "[SSH^]", "[extends consultant]"}
+		if long {
+			expected = []string{"acme extends consultant:", "Jane Doe ^", "jane@acme.com\n"}
+		}
+
+		for _, want := range expected {
+			if !strings.Contains(output, want) {
+				t.Errorf("Expected output (long=%v) to contain %q, got:\n%s", long, want, output)
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// cmd/pair.go
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/spf13/cobra"
+)
+
+var pairEnd bool
+
+// pairRunner holds the dependencies for the pair command for mocking.
+type pairRunner struct {
+	load func() (*config.Config, error)
+	save func(*config.Config) error
+}
+
+// run is the core logic for the pair command.
+func (p *pairRunner) run(cmd *cobra.Command, args []string) error {
+	if pairEnd && len(args) > 0 {
+		return errors.InvalidInput("--end takes no profile names")
+	}
+
+	cfg, err := p.load()
+	if err != nil {
+		return errLoadConfig(err)
+	}
+
+	switch {
+	case pairEnd:
+		return p.end(cfg)
+	case len(args) == 0:
+		p.show(cfg)
+
+		return nil
+	default:
+		return p.start(cfg, args)
+	}
+}
+
+// start records the profiles to credit as co-authors of every commit.
+func (p *pairRunner) start(cfg *config.Config, profileNames []string) error {
+	var pair []string
+
+	for _, name := range profileNames {
+		profile, exists := cfg.Profiles[name]
+		if !exists {
+			return errProfileNotFound(name)
+		}
+
+		if profile.Email == "" {
+			return errors.InvalidInput("profile '%s' has no email to credit", name).
+				WithHint("Add one with 'gitego edit %s --email <email>'.", name)
+		}
+
+		if !slices.Contains(pair, name) {
+			pair = append(pair, name)
+		}
+	}
+
+	cfg.Pair = pair
+
+	if err := p.save(cfg); err != nil {
+		return errSaveConfig(err)
+	}
+
+	printSuccess("✓ Pairing session started. Commits will be co-authored by:\n")
+
+	for _, name := range pair {
+		printSuccess("  %s: %s\n", name, cfg.Profiles[name].Identity())
+	}
+
+	printSuccess("Run 'gitego pair --end' when you are done.\n")
+
+	return nil
+}
+
+// end clears the pairing session.
+func (p *pairRunner) end(cfg *config.Config) error {
+	if len(cfg.Pair) == 0 {
+		fmt.Println("No pairing session is active.")
+
+		return nil
+	}
+
+	cfg.Pair = nil
+
+	if err := p.save(cfg); err != nil {
+		return errSaveConfig(err)
+	}
+
+	printSuccess("✓ Pairing session ended.\n")
+
+	return nil
+}
+
+// show prints the current pairing session.
+func (p *pairRunner) show(cfg *config.Config) {
+	if len(cfg.Pair) == 0 {
+		fmt.Println("No pairing session is active. Start one with 'gitego pair <profile...>'.")
+
+		return
+	}
+
+	fmt.Println("Commits are co-authored by:")
+
+	for _, name := range cfg.Pair {
+		if profile, exists := cfg.Profiles[name]; exists {
+			fmt.Printf("  %s: %s\n", name, profile.Identity())
+		} else {
+			fmt.Printf("  %s: (profile not found)\n", name)
+		}
+	}
+}
+
+// pairCmd represents the pair command.
+var pairCmd = &cobra.Command{
+	Use:   "pair [profile...]",
+	Short: "Credits teammates as co-authors of your commits while pairing.",
+	Long: `Starts a pairing session: until 'gitego pair --end', the commit-msg hook adds a
+Co-authored-by trailer to every commit for each named profile, using the
+profile's name and email. Teammates need a gitego profile, which only needs a
+name and an email. The commit's own author is never credited twice.
+
+Without arguments, shows the current session. The hook must be installed in
+the repository with 'gitego install-hook'.`,
+	Example: `  gitego add alex --name "Alex Kim" --email alex@corp.com
+  gitego pair alex
+  gitego pair --end`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &pairRunner{
+			load: config.Load,
+			save: func(c *config.Config) error { return c.Save() },
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pairCmd)
+
+	pairCmd.Flags().BoolVar(&pairEnd, "end", false, "End the pairing session")
+}
@@ -0,0 +1,65 @@
+// cmd/pair_test.go
+
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+)
+
+func TestPairCommand(t *testing.T) {
+	cfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"alex": {Name: "Alex Kim", Email: "alex@corp.com"},
+			"sam":  {Name: "Sam Roe", Email: "sam@corp.com"},
+		},
+	}
+
+	saves := 0
+
+	runner := &pairRunner{
+		load: func() (*config.Config, error) { return cfg, nil },
+		save: func(*config.Config) error {
+			saves++
+
+			return nil
+		},
+	}
+
+	defer func() { pairEnd = false }()
+
+	err := runner.run(pairCmd, []string{"alex", "ghost"})
+	if errors.KindOf(err) != errors.KindNotFound || len(cfg.Pair) != 0 || saves != 0 {
+		t.Errorf("Expected an unknown profile to be rejected, got pair %v and %v", cfg.Pair, err)
+	}
+
+	output := captureOutput(t, "", func() { runner.run(pairCmd, []string{"alex", "sam", "alex"}) })
+	if !slices.Equal(cfg.Pair, []string{"alex", "sam"}) || saves != 1 {
+		t.Errorf("Expected the pair to be saved once as [alex sam], got %v", cfg.Pair)
+	}
+
+	if !strings.Contains(output, "Alex Kim <alex@corp.com>") {
+		t.Errorf("Expected the co-authors to be listed, got: %s", output)
+	}
+
+	output = captureOutput(t, "", func() { runner.run(pairCmd, nil) })
+	if !strings.Contains(output, "sam: Sam Roe <sam@corp.com>") {
+		t.Errorf("Expected the session to be shown, got: %s", output)
+	}
+
+	pairEnd = true
+
+	output = captureOutput(t, "", func() { runner.run(pairCmd, nil) })
+	if cfg.Pair != nil || saves != 2 || !strings.Contains(output, "Pairing session ended") {
+		t.Errorf("Expected the session to end, got pair %v and output: %s", cfg.Pair, output)
+	}
+
+	output = captureOutput(t, "", func() { runner.run(pairCmd, nil) })
+	if !strings.Contains(output, "No pairing session is active") || saves != 2 {
+		t.Errorf("Expected no session to end, got: %s", output)
+	}
+}
@@ -0,0 +1,55 @@
+// cmd/policy.go
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+)
+
+// enforcePolicy loads the team policy and runs check against it. It returns an error
+// listing the violations if the change may not go ahead. A policy that cannot be loaded
+// blocks the change, so a broken policy file can't be used to bypass it. A nil loadPolicy
+// disables enforcement.
+func enforcePolicy(
+	loadPolicy func(*config.Config) (*config.Policy, error),
+	cfg *config.Config,
+	check func(*config.Policy) []config.PolicyViolation,
+) error {
+	if loadPolicy == nil {
+		return nil
+	}
+
+	policy, err := loadPolicy(cfg)
+	if err != nil {
+		return errors.IO(err, "could not load policy")
+	}
+
+	violations := check(policy)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	var message strings.Builder
+
+	message.WriteString("this change violates your team policy:")
+
+	for _, violation := range violations {
+		fmt.Fprintf(&message, "\n  - %s", violation)
+	}
+
+	return errors.InvalidInput("%s", message.String())
+}
+
+// printPolicyViolations writes one line per violation.
+func printPolicyViolations(out io.Writer, violations []config.PolicyViolation) {
+	_, _ = fmt.Fprintln(out, "Error: This change violates your team policy:")
+
+	for _, violation := range violations {
+		_, _ = fmt.Fprintf(out, "  - %s\n", violation)
+	}
+}
@@ -0,0 +1,101 @@
+// cmd/prompt.go
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/spf13/cobra"
+)
+
+// defaultPromptFormat is used when neither --format nor GITEGO_PROMPT_FORMAT is set.
+const defaultPromptFormat = "{profile}{mismatch}"
+
+var (
+	promptFormat string
+	promptGlyph  string
+)
+
+// promptRunner holds the dependencies for the prompt command for mocking.
+type promptRunner struct {
+	loadCache  func() (*config.PromptCache, error)
+	getwd      func() (string, error)
+	localEmail func(string) string
+	getenv     func(string) string
+}
+
+// run is the core logic for the prompt command. It prints nothing when no profile applies
+// or anything goes wrong, so a broken setup never breaks the shell prompt.
+func (r *promptRunner) run(cmd *cobra.Command, args []string) {
+	cache, err := r.loadCache()
+	if err != nil {
+		return
+	}
+
+	dir, err := r.getwd()
+	if err != nil {
+		return
+	}
+
+	rule := cache.Resolve(dir)
+	if rule.Profile == "" {
+		return
+	}
+
+	format := promptFormat
+	if format == "" {
+		format = r.getenv("GITEGO_PROMPT_FORMAT")
+	}
+
+	if format == "" {
+		format = defaultPromptFormat
+	}
+
+	mismatch := ""
+
+	// Only a repo-local override can beat the rule's includeIf, so that's all we check.
+	if local := r.localEmail(dir); local != "" && !strings.EqualFold(local, rule.Email) {
+		mismatch = promptGlyph
+	}
+
+	replacer := strings.NewReplacer("{profile}", rule.Profile, "{email}", rule.Email, "{mismatch}", mismatch)
+
+	_, _ = fmt.Fprint(cmd.OutOrStdout(), replacer.Replace(format))
+}
+
+// promptCmd represents the prompt command.
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Prints the profile for the current directory, for shell prompts.",
+	Long: `Prints the gitego profile that applies to the current directory in a short
+format suitable for a shell prompt. It is built for speed: instead of loading
+config.yaml and calling git, it reads a precompiled rule cache that is rebuilt
+automatically whenever the configuration changes.
+
+The format, set with --format or GITEGO_PROMPT_FORMAT, may use {profile},
+{email} and {mismatch}. {mismatch} expands to the --glyph when the repository's
+own .git/config overrides user.email with something else. Nothing is printed
+when no profile applies.
+
+See 'gitego shell-init' for ready-made prompt snippets.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runner := &promptRunner{
+			loadCache:  config.LoadPromptCache,
+			getwd:      os.Getwd,
+			localEmail: config.LocalUserEmail,
+			getenv:     os.Getenv,
+		}
+		runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(promptCmd)
+
+	promptCmd.Flags().StringVar(&promptFormat, "format", "", "Output format using {profile}, {email} and {mismatch}")
+	promptCmd.Flags().StringVar(&promptGlyph, "glyph", "!", "Text {mismatch} expands to on an identity mismatch")
+}
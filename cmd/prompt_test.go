@@ -0,0 +1,76 @@
+// cmd/prompt_test.go
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/spf13/cobra"
+)
+
+func TestPromptCommand(t *testing.T) {
+	cache := &config.PromptCache{
+		Default: config.PromptRule{Profile: "me", Email: "me@gmail.com"},
+	}
+
+	testCases := []struct {
+		name       string
+		format     string
+		env        string
+		localEmail string
+		expected   string
+	}{
+		{"default format", "", "", "", "me"},
+		{"mismatch glyph", "", "", "other@corp.com", "me!"},
+		{"matching override", "", "", "ME@gmail.com", "me"},
+		{"format from environment", "", "{email}", "", "me@gmail.com"},
+		{"format flag wins", "[{profile} {email}]{mismatch}", "{email}", "x@y.com", "[me me@gmail.com]!"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			runner := &promptRunner{
+				loadCache:  func() (*config.PromptCache, error) { return cache, nil },
+				getwd:      func() (string, error) { return t.TempDir(), nil },
+				localEmail: func(string) string { return tc.localEmail },
+				getenv:     func(string) string { return tc.env },
+			}
+
+			promptFormat = tc.format
+			promptGlyph = "!"
+
+			defer func() { promptFormat = "" }()
+
+			out := &bytes.Buffer{}
+			cmd := &cobra.Command{}
+			cmd.SetOut(out)
+
+			runner.run(cmd, []string{})
+
+			if out.String() != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestPromptCommand_NoProfile(t *testing.T) {
+	runner := &promptRunner{
+		loadCache:  func() (*config.PromptCache, error) { return &config.PromptCache{}, nil },
+		getwd:      func() (string, error) { return t.TempDir(), nil },
+		localEmail: func(string) string { return "" },
+		getenv:     func(string) string { return "" },
+	}
+
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	runner.run(cmd, []string{})
+
+	if out.Len() != 0 {
+		t.Errorf("Expected no output without a profile, got %q", out.String())
+	}
+}
@@ -0,0 +1,259 @@
+// cmd/rename.go
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/bgreenwell/gitego/utils"
+	"github.com/spf13/cobra"
+)
+
+var renameSkipKeychain bool
+
+// vaultAccess holds the vault functions for mocking.
+type vaultAccess struct {
+	getToken     func(string) (string, error)
+	setToken     func(string, string) error
+	deleteToken  func(string) error
+	getSecret    func(profileName, name string) (string, error)
+	setSecret    func(profileName, name, value string) error
+	deleteSecret func(profileName, name string) error
+}
+
+// defaultVault is gitego's vault in the OS keychain.
+var defaultVault = vaultAccess{
+	getToken:     config.GetToken,
+	setToken:     config.SetToken,
+	deleteToken:  config.DeleteToken,
+	getSecret:    config.GetSecret,
+	setSecret:    config.SetSecret,
+	deleteSecret: config.DeleteSecret,
+}
+
+// secretNames returns the vault entries a profile can own: its token, under the name
+// "pat", and the secrets its environment variables refer to.
+func secretNames(profile *config.Profile) []string {
+	names := []string{config.PATSecretName}
+
+	for _, key := range sortedKeys(profile.Env) {
+		if name, isRef := config.ParseSecretRef(profile.Env[key]); isRef && name != config.PATSecretName {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// copySecrets copies the vault entries of profile src to profile dst and returns the names
+// of those it copied. Entries src doesn't have are skipped. On failure, the entries
+// already copied are returned along with the error so they can be removed again.
+func (v vaultAccess) copySecrets(src, dst string, profile *config.Profile) ([]string, error) {
+	var copied []string
+
+	for _, name := range secretNames(profile) {
+		found, err := v.copySecret(src, dst, name)
+		if err != nil {
+			return copied, fmt.Errorf("secret '%s': %w", name, err)
+		}
+
+		if found {
+			copied = append(copied, name)
+		}
+	}
+
+	return copied, nil
+}
+
+// copySecret copies the named vault entry of profile src to profile dst. It reports false
+// if src has no such entry.
+func (v vaultAccess) copySecret(src, dst, name string) (bool, error) {
+	value, err := v.getSecret(src, name)
+	if errors.Is(err, config.ErrSecretNotFound) {
+		return false, nil
+	}
+
+	if err == nil {
+		if name == config.PATSecretName {
+			err = v.setToken(dst, value)
+		} else {
+			err = v.setSecret(dst, name, value)
+		}
+	}
+
+	return err == nil, err
+}
+
+// deleteSecrets removes the named vault entries of a profile, ignoring failures.
+func (v vaultAccess) deleteSecrets(profileName string, names []string) {
+	for _, name := range names {
+		if name == config.PATSecretName {
+			_ = v.deleteToken(profileName)
+		} else {
+			_ = v.deleteSecret(profileName, name)
+		}
+	}
+}
+
+// renameRunner holds the dependencies for the rename command for mocking.
+type renameRunner struct {
+	load                   func() (*config.Config, error)
+	save                   func(*config.Config) error
+	renameGitconfig        func(oldName, newName string) (bool, error)
+	ensureProfileGitconfig func(string, *config.Profile) error
+	renameIncludeIfs       func(oldName, newName string) (int, error)
+	getGlobalGitAll        func(string) ([]string, error)
+	setGlobalGit           func(string, string) error
+	vault                  vaultAccess
+}
+
+// run is the core logic for the rename command. Every change is undone if a later one
+// fails, so the profile is either renamed everywhere or not at all.
+func (r *renameRunner) run(cmd *cobra.Command, args []string) error {
+	oldName, newName := args[0], args[1]
+
+	cfg, err := r.load()
+	if err != nil {
+		return errLoadConfig(err)
+	}
+
+	profile, exists := cfg.Profiles[oldName]
+	if !exists {
+		return errProfileNotFound(oldName)
+	}
+
+	if err := cfg.ValidateNewProfileName(newName); err != nil {
+		return errNewProfileName(err)
+	}
+
+	var undo []func()
+
+	rollback := func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+	}
+
+	// 1. Copy the token and secrets to the new name; the old entries go once all is saved.
+	var moved []string
+
+	if !renameSkipKeychain {
+		moved, err = r.vault.copySecrets(oldName, newName, profile)
+		undo = append(undo, func() { r.vault.deleteSecrets(newName, moved) })
+
+		if err != nil {
+			rollback()
+
+			return errors.KeyringUnavailable(err, "could not move the keychain entries").
+				WithHint("If the keychain is unavailable and the profile has no token, rerun with --skip-keychain.")
+		}
+	}
+
+	// 2. Rename the profile's gitconfig file.
+	hasGitconfig, err := r.renameGitconfig(oldName, newName)
+	if err != nil {
+		rollback()
+
+		return errors.IO(err, "could not rename the profile gitconfig file")
+	}
+
+	if hasGitconfig {
+		undo = append(undo, func() { _, _ = r.renameGitconfig(newName, oldName) })
+	}
+
+	// 3. Point the includeIf blocks in ~/.gitconfig at the renamed file.
+	includeIfs, err := r.renameIncludeIfs(oldName, newName)
+	if err != nil {
+		rollback()
+
+		return errors.IO(err, "could not update ~/.gitconfig")
+	}
+
+	if includeIfs > 0 {
+		undo = append(undo, func() { _, _ = r.renameIncludeIfs(newName, oldName) })
+	}
+
+	// 4. Update the global core.sshCommand if it was set for this profile by 'gitego use'.
+	if values, _ := r.getGlobalGitAll("core.sshCommand"); len(values) == 1 && values[0] == config.SSHCommand(oldName) {
+		if err := r.setGlobalGit("core.sshCommand", config.SSHCommand(newName)); err != nil {
+			rollback()
+
+			return errors.IO(err, "could not update git core.sshCommand")
+		}
+
+		undo = append(undo, func() { _ = r.setGlobalGit("core.sshCommand", config.SSHCommand(oldName)) })
+	}
+
+	// 5. Rename the profile and every reference to it in the gitego config.
+	rules := 0
+
+	for _, rule := range cfg.AutoRules {
+		if rule.Profile == oldName {
+			rules++
+		}
+	}
+
+	if err := cfg.RenameProfile(oldName, newName); err != nil {
+		rollback()
+
+		return errors.InvalidInput("%v", err)
+	}
+
+	if err := r.save(cfg); err != nil {
+		rollback()
+
+		return errSaveConfig(err)
+	}
+
+	// The generated gitconfig names the profile in its core.sshCommand.
+	if hasGitconfig {
+		if err := r.ensureProfileGitconfig(newName, profile); err != nil {
+			fmt.Printf("Warning: Could not regenerate the profile gitconfig file: %v\n", err)
+		}
+	}
+
+	r.vault.deleteSecrets(oldName, moved)
+
+	printSuccess("✓ Profile '%s' renamed to '%s'.\n", oldName, newName)
+	printSuccess("  %d auto-switch rule(s), %d includeIf block(s) and %d keychain entry(ies) carried over.\n",
+		rules, includeIfs, len(moved))
+
+	return nil
+}
+
+// renameCmd represents the rename command.
+var renameCmd = &cobra.Command{
+	Use:   "rename <old_name> <new_name>",
+	Short: "Renames a profile, keeping its rules, files and tokens.",
+	Long: `Renames a profile and everything that refers to it: its auto-switch rules,
+its generated gitconfig file and the includeIf blocks in ~/.gitconfig that
+include it, its token and secrets in the OS keychain, and the active profile,
+pairing session and profiles that extend it.
+
+If any step fails, the ones already done are undone. Without a keychain to
+reach, --skip-keychain renames everything else and leaves the vault alone.`,
+	Aliases: []string{"mv"},
+	Args:    cobra.ExactArgs(exactArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &renameRunner{
+			load:                   config.Load,
+			save:                   func(c *config.Config) error { return c.Save() },
+			renameGitconfig:        config.RenameProfileGitconfig,
+			ensureProfileGitconfig: config.EnsureProfileGitconfig,
+			renameIncludeIfs:       config.RenameIncludeIfs,
+			getGlobalGitAll:        utils.GetGlobalGitConfigAll,
+			setGlobalGit:           utils.SetGlobalGitConfig,
+			vault:                  defaultVault,
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+
+	renameCmd.Flags().BoolVar(&renameSkipKeychain, "skip-keychain", false,
+		"Rename without moving the profile's token and secrets in the OS keychain")
+}
@@ -0,0 +1,135 @@
+// cmd/rename_test.go
+
+package cmd
+
+import (
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+)
+
+// mockVault returns vault functions backed by a map of "profile/name" entries.
+func mockVault(entries map[string]string) vaultAccess {
+	get := func(profileName, name string) (string, error) {
+		if value, ok := entries[profileName+"/"+name]; ok {
+			return value, nil
+		}
+
+		return "", config.ErrSecretNotFound
+	}
+
+	set := func(profileName, name, value string) error {
+		entries[profileName+"/"+name] = value
+
+		return nil
+	}
+
+	remove := func(profileName, name string) error {
+		delete(entries, profileName+"/"+name)
+
+		return nil
+	}
+
+	return vaultAccess{
+		getToken:     func(p string) (string, error) { return get(p, config.PATSecretName) },
+		setToken:     func(p, v string) error { return set(p, config.PATSecretName, v) },
+		deleteToken:  func(p string) error { return remove(p, config.PATSecretName) },
+		getSecret:    get,
+		setSecret:    set,
+		deleteSecret: remove,
+	}
+}
+
+func TestRenameCommand(t *testing.T) {
+	newConfig := func() *config.Config {
+		return &config.Config{
+			Profiles: map[string]*config.Profile{
+				"work": {Name: "Work", Email: "work@corp.com", Env: map[string]string{"NPM_TOKEN": "vault:NPM_TOKEN"}},
+			},
+			AutoRules:     []*config.AutoRule{{Path: "/src/work/", Profile: "work"}},
+			ActiveProfile: "work",
+		}
+	}
+
+	for _, failSave := range []bool{false, true} {
+		cfg := newConfig()
+		entries := map[string]string{"work/pat": "ghp_work", "work/NPM_TOKEN": "npm_work"}
+		files := map[string]bool{"work": true}
+		includeIfs := map[string]int{"work": 1}
+		sshCommand := config.SSHCommand("work")
+
+		var saved *config.Config
+
+		runner := &renameRunner{
+			load: func() (*config.Config, error) { return cfg, nil },
+			save: func(c *config.Config) error {
+				if failSave {
+					return errors.New("disk full")
+				}
+
+				saved = c
+
+				return nil
+			},
+			renameGitconfig: func(oldName, newName string) (bool, error) {
+				moved := files[oldName]
+				delete(files, oldName)
+				files[newName] = moved
+
+				return moved, nil
+			},
+			ensureProfileGitconfig: func(string, *config.Profile) error { return nil },
+			renameIncludeIfs: func(oldName, newName string) (int, error) {
+				count := includeIfs[oldName]
+				delete(includeIfs, oldName)
+				includeIfs[newName] = count
+
+				return count, nil
+			},
+			getGlobalGitAll: func(string) ([]string, error) { return []string{sshCommand}, nil },
+			setGlobalGit: func(_, value string) error {
+				sshCommand = value
+
+				return nil
+			},
+			vault: mockVault(entries),
+		}
+
+		var err error
+
+		output := captureOutput(t, "", func() { err = runner.run(renameCmd, []string{"work", "corp"}) })
+
+		if failSave {
+			if err == nil || !strings.Contains(err.Error(), "disk full") {
+				t.Errorf("Expected the save error to be reported, got: %v", err)
+			}
+
+			if !maps.Equal(entries, map[string]string{"work/pat": "ghp_work", "work/NPM_TOKEN": "npm_work"}) ||
+				!files["work"] || includeIfs["work"] != 1 || sshCommand != config.SSHCommand("work") {
+				t.Errorf("Expected every change to be undone, got vault %v, files %v, includeIfs %v, sshCommand %q",
+					entries, files, includeIfs, sshCommand)
+			}
+
+			continue
+		}
+
+		if saved == nil || saved.Profiles["corp"] == nil || saved.AutoRules[0].Profile != "corp" || saved.ActiveProfile != "corp" {
+			t.Fatalf("Expected the renamed profile to be saved, got %v and output: %s", err, output)
+		}
+
+		if !maps.Equal(entries, map[string]string{"corp/pat": "ghp_work", "corp/NPM_TOKEN": "npm_work"}) {
+			t.Errorf("Expected the keychain entries to move, got %v", entries)
+		}
+
+		if !files["corp"] || includeIfs["corp"] != 1 || sshCommand != config.SSHCommand("corp") {
+			t.Errorf("Expected the gitconfig file, includeIf and sshCommand to follow, got %v %v %q", files, includeIfs, sshCommand)
+		}
+
+		if !strings.Contains(output, "1 auto-switch rule(s), 1 includeIf block(s) and 2 keychain entry(ies)") {
+			t.Errorf("Expected a summary of what was carried over, got: %s", output)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/bgreenwell/gitego/config"
@@ -24,23 +25,22 @@ type rmRunner struct {
 	removeIncludeIf  func(string) error
 	removeProfileCfg func(string) error
 	deleteToken      func(string) error
+	deleteSecret     func(profileName, name string) error
+	// vault hands secrets over to the profiles that extend the removed one.
+	vault vaultAccess
 }
 
 // run is the core logic for the rm command.
-func (r *rmRunner) run(cmd *cobra.Command, args []string) {
+func (r *rmRunner) run(cmd *cobra.Command, args []string) error {
 	profileName := args[0]
 
 	cfg, err := r.load()
 	if err != nil {
-		fmt.Printf("Error loading configuration: %v\n", err)
-
-		return
+		return errLoadConfig(err)
 	}
 
 	if _, exists := cfg.Profiles[profileName]; !exists {
-		fmt.Printf("Error: Profile '%s' not found.\n", profileName)
-
-		return
+		return errProfileNotFound(profileName)
 	}
 
 	if !forceFlag {
@@ -53,7 +53,7 @@ func (r *rmRunner) run(cmd *cobra.Command, args []string) {
 		if strings.TrimSpace(strings.ToLower(response)) != "y" {
 			fmt.Println("Removal cancelled.")
 
-			return
+			return nil
 		}
 	}
 
@@ -78,19 +78,83 @@ func (r *rmRunner) run(cmd *cobra.Command, args []string) {
 
 	cfg.AutoRules = keptRules
 
+	// The profile can no longer be credited as a co-author.
+	cfg.Pair = slices.DeleteFunc(cfg.Pair, func(name string) bool { return name == profileName })
+
+	// Profiles that extend this one keep the values they inherited from it, along with the
+	// secrets their env reads from it. A secret that can't be copied is not deleted.
+	kept := make(map[string]bool)
+
+	for _, name := range sortedKeys(cfg.Profiles) {
+		child := cfg.Profiles[name]
+		if child.Extends != profileName {
+			continue
+		}
+
+		for _, secret := range r.handOverSecrets(profileName, name, child) {
+			kept[secret] = true
+		}
+
+		child.Extends = ""
+
+		fmt.Printf("Profile '%s' no longer extends '%s' and keeps the values it inherited.\n", name, profileName)
+	}
+
 	// 4. Delete the profile itself.
+	profile := cfg.Profiles[profileName]
 	delete(cfg.Profiles, profileName)
 
 	if err := r.save(cfg); err != nil {
-		fmt.Printf("Error saving configuration: %v\n", err)
-
-		return
+		return errSaveConfig(err)
 	}
 
 	// 5. Remove the PAT from the OS keychain.
-	_ = r.deleteToken(profileName)
+	if !kept[config.PATSecretName] {
+		_ = r.deleteToken(profileName)
+	}
+
+	// 6. Remove the secrets its environment variables refer to.
+	if r.deleteSecret != nil {
+		for _, value := range profile.Env {
+			if name, isRef := config.ParseSecretRef(value); isRef && name != config.PATSecretName && !kept[name] {
+				_ = r.deleteSecret(profileName, name)
+			}
+		}
+	}
+
+	printSuccess("✓ Profile '%s' and all associated rules removed successfully.\n", profileName)
+
+	return nil
+}
+
+// handOverSecrets copies the vault entries that the env of profile childName reads from
+// profile baseName into the child's own vault, unless it has its own already. It returns
+// the names of those that could not be copied.
+func (r *rmRunner) handOverSecrets(baseName, childName string, child *config.Profile) []string {
+	var failed []string
+
+	if r.vault.getSecret == nil {
+		return nil
+	}
+
+	for _, key := range sortedKeys(child.Env) {
+		name, isRef := config.ParseSecretRef(child.Env[key])
+		if !isRef || child.EnvSource(childName, key) != baseName || slices.Contains(failed, name) {
+			continue
+		}
+
+		if _, err := r.vault.getSecret(childName, name); err == nil {
+			continue
+		}
+
+		if _, err := r.vault.copySecret(baseName, childName, name); err != nil {
+			fmt.Printf("Warning: Could not copy secret '%s' to profile '%s'; keeping it: %v\n", name, childName, err)
+
+			failed = append(failed, name)
+		}
+	}
 
-	fmt.Printf("✓ Profile '%s' and all associated rules removed successfully.\n", profileName)
+	return failed
 }
 
 // rmCmd represents the rm command.
@@ -102,12 +166,14 @@ var rmCmd = &cobra.Command{
 	rules from your global .gitconfig file.`,
 	Aliases: []string{"remove"},
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		runner := &rmRunner{
 			load:            config.Load,
 			save:            func(c *config.Config) error { return c.Save() },
 			removeIncludeIf: config.RemoveIncludeIf,
 			deleteToken:     config.DeleteToken,
+			deleteSecret:    config.DeleteSecret,
+			vault:           defaultVault,
 			removeProfileCfg: func(profileName string) error {
 				home, err := os.UserHomeDir()
 				if err != nil {
@@ -118,7 +184,7 @@ var rmCmd = &cobra.Command{
 				return os.Remove(path)
 			},
 		}
-		runner.run(cmd, args)
+		return runner.run(cmd, args)
 	},
 }
 
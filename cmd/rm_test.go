@@ -110,3 +110,49 @@ func validateRmCommandEffects(t *testing.T, saved bool, removedIncludeIf, remove
 		t.Error("Expected DeleteToken to be called for 'work' profile.")
 	}
 }
+
+func TestRmCommand_ExtendingProfiles(t *testing.T) {
+	mockCfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"consultant": {Name: "Jane Doe", Email: "jane@consulting.com",
+				Env: map[string]string{"NPM_TOKEN": "vault:NPM_TOKEN", "GH_TOKEN": "vault:pat"}},
+			"acme": {Extends: "consultant", Email: "jane@acme.com"},
+		},
+	}
+	mockCfg.ResolveProfiles()
+
+	entries := map[string]string{"consultant/NPM_TOKEN": "npm_123", "consultant/pat": "ghp_123"}
+	vault := mockVault(entries)
+
+	runner := &rmRunner{
+		load:             func() (*config.Config, error) { return mockCfg, nil },
+		save:             func(*config.Config) error { return nil },
+		removeIncludeIf:  func(string) error { return nil },
+		removeProfileCfg: func(string) error { return nil },
+		deleteToken:      vault.deleteToken,
+		deleteSecret:     vault.deleteSecret,
+		vault:            vault,
+	}
+
+	forceFlag = true
+
+	defer func() { forceFlag = false }()
+
+	if err := runner.run(rmCmd, []string{"consultant"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	acme := mockCfg.Profiles["acme"]
+	if acme.Extends != "" || acme.Name != "Jane Doe" || acme.Email != "jane@acme.com" {
+		t.Errorf("Expected 'acme' to stop extending 'consultant' and keep its values, got %+v", acme)
+	}
+
+	env, err := acme.ResolveEnv("acme", vault.getSecret)
+	if err != nil || env["NPM_TOKEN"] != "npm_123" || env["GH_TOKEN"] != "ghp_123" {
+		t.Errorf("Expected 'acme' to still read its secrets, got %v, %v", env, err)
+	}
+
+	if _, exists := entries["consultant/NPM_TOKEN"]; exists {
+		t.Errorf("Expected the secrets of 'consultant' to be removed, got %v", entries)
+	}
+}
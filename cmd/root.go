@@ -5,8 +5,11 @@ package cmd
 
 import (
 	"fmt"
-	"log"
+	"io"
+	"os"
 
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +19,8 @@ var version = "0.1.1" // Updated for release
 var (
 	// versionFlag is a flag to print the version and exit.
 	versionFlag bool
+	// quietFlag suppresses the success messages printed through printSuccess.
+	quietFlag bool
 )
 
 // rootCmd represents the base command when called without any subcommands.
@@ -27,28 +32,111 @@ var rootCmd = &cobra.Command{
 
 It allows you to define, switch between, and automatically apply different
 user profiles (user.name, user.email), SSH keys, and Personal Access Tokens
-depending on your current working directory or other contexts.`,
-	Run: func(cmd *cobra.Command, _ []string) {
+depending on your current working directory or other contexts.
+
+Errors are printed to stderr, and the exit code tells what kind of error
+occurred: 1 general failure, 2 invalid input, 3 not found, 4 already exists,
+5 file or git failure, 6 keychain unavailable.`,
+	// Execute prints errors itself, with their hint and without the usage text.
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
 		// If the version flag is passed, print the version and exit.
 		if versionFlag {
 			fmt.Printf("gitego version %s\n", version)
 
-			return
+			return nil
 		}
 		// Otherwise, show the help information.
-		if err := cmd.Help(); err != nil {
-			log.Fatalf("Failed to show help: %v", err)
-		}
+		return cmd.Help()
 	},
 }
 
 func init() {
 	// Add the --version flag to the root command.
 	rootCmd.Flags().BoolVarP(&versionFlag, "version", "v", false, "Print gitego's version number")
+	rootCmd.PersistentFlags().BoolVarP(&quietFlag, "quiet", "q", false, "Only print errors and the output asked for")
+}
+
+// printSuccess prints a confirmation that a command did what it was asked to, unless
+// --quiet was given.
+func printSuccess(format string, args ...any) {
+	fprintSuccess(os.Stdout, format, args...)
+}
+
+// fprintSuccess is printSuccess for commands that write to cmd.OutOrStdout().
+func fprintSuccess(out io.Writer, format string, args ...any) {
+	if !quietFlag {
+		_, _ = fmt.Fprintf(out, format, args...)
+	}
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Errors are printed to stderr before being returned; errors.ExitCode gives the exit
+// code for them.
 func Execute() error {
-	return rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil {
+		return nil
+	}
+
+	// Errors that aren't gitego's own come from cobra's flag and argument checks.
+	var gitegoErr *errors.Error
+	if !errors.As(err, &gitegoErr) {
+		err = errors.InvalidInput("%v", err).WithHint("Run '%s --help' for usage.", cmd.CommandPath())
+	}
+
+	printError(os.Stderr, err)
+
+	return err
+}
+
+// printError writes an error and its hint.
+func printError(out io.Writer, err error) {
+	_, _ = fmt.Fprintf(out, "Error: %v\n", err)
+
+	if hint := errors.HintOf(err); hint != "" {
+		_, _ = fmt.Fprintln(out, hint)
+	}
+}
+
+// errLoadConfig and errSaveConfig wrap failures to read and write the gitego config.
+func errLoadConfig(err error) error {
+	return errors.IO(err, "could not load configuration")
+}
+
+func errSaveConfig(err error) error {
+	return errors.IO(err, "could not save configuration")
+}
+
+// errProfileNotFound is the error for a profile name that isn't in the config.
+func errProfileNotFound(profileName string) *errors.Error {
+	return errors.NotFound("profile '%s' not found", profileName).
+		WithHint("Run 'gitego list' to see your profiles.")
+}
+
+// errNewProfileName types an error of config.ValidateNewProfileName: an invalid name is
+// invalid input, anything else means the name is taken.
+func errNewProfileName(err error) error {
+	var invalid *config.ValidationError
+	if errors.As(err, &invalid) {
+		return errors.InvalidInput("%v", err)
+	}
+
+	return errors.AlreadyExists("%v", err)
+}
+
+// errNotGitRepo is the error for commands that must run inside a repository.
+func errNotGitRepo() *errors.Error {
+	return errors.NotFound("not a git repository (or any of the parent directories)")
+}
+
+// errKeyring wraps a failure to use the OS keychain.
+func errKeyring(err error, format string, args ...any) error {
+	if errors.Is(err, config.ErrSecretNotFound) {
+		return &errors.Error{Kind: errors.KindNotFound, Message: fmt.Sprintf(format, args...), Err: err}
+	}
+
+	return errors.KeyringUnavailable(err, format, args...)
 }
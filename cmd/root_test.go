@@ -0,0 +1,59 @@
+// cmd/root_test.go
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+)
+
+func TestExecute_InvalidFlag(t *testing.T) {
+	rootCmd.SetArgs([]string{"list", "--no-such-flag"})
+
+	defer rootCmd.SetArgs(nil)
+
+	err := Execute()
+	if errors.ExitCode(err) != 2 || errors.HintOf(err) != "Run 'gitego list --help' for usage." {
+		t.Errorf("Expected an invalid input error with a usage hint, got %v (hint %q)", err, errors.HintOf(err))
+	}
+}
+
+func TestPrintError(t *testing.T) {
+	var out bytes.Buffer
+
+	printError(&out, errProfileNotFound("missing"))
+
+	expected := "Error: profile 'missing' not found\nRun 'gitego list' to see your profiles.\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestPrintSuccess_Quiet(t *testing.T) {
+	var out bytes.Buffer
+
+	fprintSuccess(&out, "✓ %s\n", "done")
+
+	quietFlag = true
+
+	defer func() { quietFlag = false }()
+
+	fprintSuccess(&out, "✓ %s\n", "hidden")
+
+	if out.String() != "✓ done\n" {
+		t.Errorf("Expected --quiet to hide success messages, got %q", out.String())
+	}
+}
+
+func TestErrKeyring(t *testing.T) {
+	if kind := errors.KindOf(errKeyring(config.ErrSecretNotFound, "no token")); kind != errors.KindNotFound {
+		t.Errorf("Expected a missing secret to be not found, got kind %v", kind)
+	}
+
+	if kind := errors.KindOf(errKeyring(errors.New("dbus unavailable"), "no token")); kind != errors.KindKeyringUnavailable {
+		t.Errorf("Expected a keychain failure, got kind %v", kind)
+	}
+}
@@ -0,0 +1,454 @@
+// cmd/scan.go
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/bgreenwell/gitego/utils"
+	"github.com/spf13/cobra"
+)
+
+// Output formats for the scan command.
+const (
+	scanOutputTable = "table"
+	scanOutputJSON  = "json"
+)
+
+var (
+	scanOutput string
+	scanJobs   int
+	scanFix    bool
+)
+
+// scanResult is the identity report for one repository.
+type scanResult struct {
+	Path  string `json:"path"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// Rule and Profile are the auto rule that applies to the repository and its profile.
+	Rule    string   `json:"rule,omitempty"`
+	Profile string   `json:"profile,omitempty"`
+	Hosts   []string `json:"hosts"`
+	Hook    bool     `json:"hook"`
+	// LocalName and LocalEmail are user overrides set in the repository's own .git/config.
+	LocalName  string   `json:"local_name,omitempty"`
+	LocalEmail string   `json:"local_email,omitempty"`
+	Conflicts  []string `json:"conflicts,omitempty"`
+	Error      string   `json:"error,omitempty"`
+
+	// overrides are the local keys behind Conflicts, which --fix removes.
+	overrides []string
+}
+
+// scanRunner holds the dependencies for the scan command for mocking.
+type scanRunner struct {
+	load           func() (*config.Config, error)
+	findRepos      func(string) ([]string, error)
+	getConfig      func(dir, key string) (string, error)
+	getLocalConfig func(dir, key string) (string, error)
+	getRemotes     func(string) ([]string, error)
+	hookInstalled  func(string) bool
+	unsetLocal     func(dir, key string) error
+	backup         func(string) (string, error)
+	stdin          io.Reader
+}
+
+// run is the core logic for the scan command.
+func (r *scanRunner) run(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	if scanOutput != scanOutputTable && scanOutput != scanOutputJSON {
+		return errors.InvalidInput("unknown output format '%s'", scanOutput).
+			WithHint("Use '%s' or '%s'.", scanOutputTable, scanOutputJSON)
+	}
+
+	if scanFix && scanOutput == scanOutputJSON {
+		return errors.InvalidInput("--fix can't be combined with --output json")
+	}
+
+	results, err := r.scan(args)
+	if err != nil {
+		return err
+	}
+
+	conflicts := 0
+
+	for _, result := range results {
+		if len(result.Conflicts) > 0 {
+			conflicts++
+		}
+	}
+
+	if scanOutput == scanOutputJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(results)
+	} else {
+		printScanTable(out, results)
+		_, _ = fmt.Fprintf(out, "\nScanned %d repositories; %d with local overrides that contradict their rule.\n",
+			len(results), conflicts)
+	}
+
+	if scanFix && conflicts > 0 {
+		conflicts -= r.fix(out, results)
+	}
+
+	if conflicts > 0 {
+		return errors.General(nil, "%d repositories with contradicting overrides", conflicts)
+	}
+
+	return nil
+}
+
+// scan finds the repositories under the directory in args and inspects them concurrently.
+func (r *scanRunner) scan(args []string) ([]scanResult, error) {
+	cfg, err := r.load()
+	if err != nil {
+		return nil, errLoadConfig(err)
+	}
+
+	root := "."
+	if len(args) > 0 {
+		root = args[0]
+	}
+
+	root, err = filepath.Abs(config.ExpandHome(root))
+	if err != nil {
+		return nil, errors.IO(err, "could not resolve %s", root)
+	}
+
+	repos, err := r.findRepos(root)
+	if err != nil {
+		return nil, errors.IO(err, "could not scan %s", root)
+	}
+
+	results := make([]scanResult, len(repos))
+	indexes := make(chan int)
+
+	workers := min(max(scanJobs, 1), len(repos))
+
+	var wg sync.WaitGroup
+
+	for range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				results[i] = r.scanRepo(cfg, repos[i])
+			}
+		}()
+	}
+
+	for i := range repos {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	return results, nil
+}
+
+// scanRepo reports the identity a repository resolves to and checks it against its rule.
+func (r *scanRunner) scanRepo(cfg *config.Config, repo string) scanResult {
+	result := scanResult{Path: repo, Hosts: []string{}, Hook: r.hookInstalled(repo)}
+
+	var errs []string
+
+	record := func(value *string, get func(string, string) (string, error), key string) {
+		v, err := get(repo, key)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+
+		*value = v
+	}
+
+	record(&result.Name, r.getConfig, "user.name")
+	record(&result.Email, r.getConfig, "user.email")
+	record(&result.LocalName, r.getLocalConfig, "user.name")
+	record(&result.LocalEmail, r.getLocalConfig, "user.email")
+
+	remotes, err := r.getRemotes(repo)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	seen := make(map[string]bool)
+
+	for _, remote := range remotes {
+		if host := config.RemoteHost(remote); host != "" && !seen[host] {
+			seen[host] = true
+			result.Hosts = append(result.Hosts, host)
+		}
+	}
+
+	sort.Strings(result.Hosts)
+
+	result.Error = strings.Join(errs, "; ")
+
+	rule := cfg.MatchingRule(repo)
+	if rule == nil {
+		return result
+	}
+
+	result.Rule = rule.Path
+	result.Profile = rule.Profile
+
+	if profile, exists := cfg.Profiles[rule.Profile]; exists {
+		result.Conflicts, result.overrides = localOverrideConflicts(result, profile)
+	}
+
+	return result
+}
+
+// localOverrideConflicts describes the repo-local user settings that differ from the
+// profile the repository's rule expects, and returns the keys involved.
+func localOverrideConflicts(result scanResult, profile *config.Profile) ([]string, []string) {
+	var conflicts, keys []string
+
+	if result.LocalEmail != "" && !strings.EqualFold(result.LocalEmail, profile.Email) {
+		conflicts = append(conflicts, fmt.Sprintf("local user.email '%s' overrides '%s' from profile '%s'",
+			result.LocalEmail, profile.Email, result.Profile))
+		keys = append(keys, "user.email")
+	}
+
+	if result.LocalName != "" && profile.Name != "" && result.LocalName != profile.Name {
+		conflicts = append(conflicts, fmt.Sprintf("local user.name '%s' overrides '%s' from profile '%s'",
+			result.LocalName, profile.Name, result.Profile))
+		keys = append(keys, "user.name")
+	}
+
+	return conflicts, keys
+}
+
+// fix shows the local overrides it would remove, then, once confirmed, backs up each
+// repository's .git/config and removes them so the rule's profile applies. It returns the
+// number of repositories fixed.
+func (r *scanRunner) fix(out io.Writer, results []scanResult) int {
+	var targets []scanResult
+
+	_, _ = fmt.Fprintln(out, "\nProposed changes:")
+
+	for _, result := range results {
+		if len(result.overrides) == 0 {
+			continue
+		}
+
+		targets = append(targets, result)
+
+		_, _ = fmt.Fprintf(out, "\n  %s\n", filepath.Join(result.Path, ".git", "config"))
+
+		for _, key := range result.overrides {
+			value := result.LocalEmail
+			if key == "user.name" {
+				value = result.LocalName
+			}
+
+			_, _ = fmt.Fprintf(out, "  - %s = %s\n", key, value)
+		}
+	}
+
+	_, _ = fmt.Fprintf(out, "\nRemove these overrides from %d repositories? Each .git/config is backed up first. [y/N]: ",
+		len(targets))
+
+	if !readYes(bufio.NewReader(r.stdin)) {
+		_, _ = fmt.Fprintln(out, "No changes made.")
+
+		return 0
+	}
+
+	fixed := 0
+
+	for _, target := range targets {
+		backupPath, err := r.backup(target.Path)
+		if err != nil {
+			printError(os.Stderr, errors.IO(err, "could not back up %s, skipping it", target.Path))
+
+			continue
+		}
+
+		var failed error
+
+		for _, key := range target.overrides {
+			if err := r.unsetLocal(target.Path, key); err != nil {
+				failed = err
+
+				break
+			}
+		}
+
+		if failed != nil {
+			printError(os.Stderr, errors.IO(failed, "could not fix %s (backup at %s)", target.Path, backupPath))
+
+			continue
+		}
+
+		fixed++
+
+		fprintSuccess(out, "✓ %s (backup at %s)\n", target.Path, backupPath)
+	}
+
+	_, _ = fmt.Fprintf(out, "\nFixed %d of %d repositories.\n", fixed, len(targets))
+
+	return fixed
+}
+
+// backupGitConfig copies a repository's .git/config next to it, as config.gitego.bak or,
+// if that exists, the first free config.gitego.bak.N, and returns the backup's path.
+func backupGitConfig(repo string) (string, error) {
+	configPath := filepath.Join(repo, ".git", "config")
+
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return "", err
+	}
+
+	backupPath := configPath + ".gitego.bak"
+
+	for n := 1; ; n++ {
+		if _, err := os.Lstat(backupPath); os.IsNotExist(err) {
+			break
+		}
+
+		backupPath = fmt.Sprintf("%s.gitego.bak.%d", configPath, n)
+	}
+
+	if err := os.WriteFile(backupPath, content, info.Mode().Perm()); err != nil {
+		return "", err
+	}
+
+	return backupPath, nil
+}
+
+// printScanTable writes scan results as an aligned table.
+func printScanTable(out io.Writer, results []scanResult) {
+	w := tabwriter.NewWriter(out, minwidth, tabwidth, padding, padchar, flags)
+	_, _ = fmt.Fprintln(w, "REPOSITORY\tNAME\tEMAIL\tPROFILE\tREMOTES\tHOOK\tSTATUS")
+
+	for _, result := range results {
+		profile := result.Profile
+		if profile == "" {
+			profile = "-"
+		}
+
+		hook := "no"
+		if result.Hook {
+			hook = "yes"
+		}
+
+		status := "ok"
+
+		switch {
+		case len(result.Conflicts) > 0:
+			status = "✗ " + strings.Join(result.Conflicts, "; ")
+		case result.Error != "":
+			status = "error: " + result.Error
+		}
+
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", result.Path, result.Name, result.Email,
+			profile, strings.Join(result.Hosts, ","), hook, status)
+	}
+
+	_ = w.Flush()
+}
+
+// findGitRepos returns every git repository under root, without descending into them.
+// Directories that can't be read are skipped.
+func findGitRepos(root string) ([]string, error) {
+	var repos []string
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+
+			return fs.SkipDir
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if _, err := os.Lstat(filepath.Join(path, ".git")); err == nil {
+			repos = append(repos, path)
+
+			return fs.SkipDir
+		}
+
+		return nil
+	})
+
+	return repos, err
+}
+
+// gitegoHookInstalled reports whether the repository's pre-commit hook runs gitego.
+func gitegoHookInstalled(repo string) bool {
+	return preCommitHook.installed(repo)
+}
+
+// scanCmd represents the scan command.
+var scanCmd = &cobra.Command{
+	Use:   "scan [dir]",
+	Short: "Reports the identity of every git repository under a directory.",
+	Long: `Finds git repositories under a directory (the current one by default) and
+reports, for each, the effective user name and email, the gitego auto rule and
+profile that apply to it, its remote hosts, and whether the gitego pre-commit
+hook is installed.
+
+Repositories whose own .git/config sets user.name or user.email to something
+other than their rule's profile are flagged: such local overrides silently win
+over gitego's includeIf rules. The command exits with status 1 if any are found.
+
+Repositories are inspected concurrently; use --jobs to bound the number of
+workers and --output json for machine-readable output.
+
+With --fix, the contradicting overrides are removed so each repository resolves
+to its rule's profile. The changes are shown first and only made after you
+confirm them, and each .git/config is backed up as config.gitego.bak before it
+is changed.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &scanRunner{
+			load:           config.Load,
+			findRepos:      findGitRepos,
+			getConfig:      utils.GetRepoGitConfig,
+			getLocalConfig: utils.GetLocalGitConfig,
+			getRemotes:     utils.GetRepoRemoteURLs,
+			hookInstalled:  gitegoHookInstalled,
+			unsetLocal:     utils.UnsetLocalGitConfig,
+			backup:         backupGitConfig,
+			stdin:          os.Stdin,
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+
+	scanCmd.Flags().StringVarP(&scanOutput, "output", "o", scanOutputTable, "Output format: table or json")
+	scanCmd.Flags().IntVarP(&scanJobs, "jobs", "j", 8, "Number of repositories to inspect at once")
+	scanCmd.Flags().BoolVar(&scanFix, "fix", false, "Remove local overrides that contradict a repository's rule")
+}
@@ -0,0 +1,190 @@
+// cmd/scan_test.go
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/spf13/cobra"
+)
+
+func TestFindGitRepos(t *testing.T) {
+	root := t.TempDir()
+
+	for _, dir := range []string{"app/.git", "app/vendor/lib/.git", "corp/api/.git", "notes"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repos, err := findGitRepos(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{filepath.Join(root, "app"), filepath.Join(root, "corp", "api")}
+	if strings.Join(repos, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, repos)
+	}
+}
+
+func TestScanCommand(t *testing.T) {
+	root := t.TempDir()
+	workDir := filepath.Join(root, "work")
+	workRepo := filepath.Join(workDir, "api")
+	personalRepo := filepath.Join(root, "dotfiles")
+
+	mockCfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"work": {Name: "Work User", Email: "work@corp.com"},
+		},
+		AutoRules: []*config.AutoRule{{Path: workDir, Profile: "work"}},
+	}
+
+	local := map[string]string{workRepo + " user.email": "me@gmail.com"}
+	effective := map[string]string{
+		workRepo + " user.name":      "Work User",
+		workRepo + " user.email":     "me@gmail.com",
+		personalRepo + " user.email": "me@gmail.com",
+	}
+
+	runner := &scanRunner{
+		load:      func() (*config.Config, error) { return mockCfg, nil },
+		findRepos: func(string) ([]string, error) { return []string{workRepo, personalRepo}, nil },
+		getConfig: func(dir, key string) (string, error) { return effective[dir+" "+key], nil },
+		getLocalConfig: func(dir, key string) (string, error) {
+			return local[dir+" "+key], nil
+		},
+		getRemotes: func(dir string) ([]string, error) {
+			return []string{"git@github.com:corp/api.git", "https://github.com/me/api"}, nil
+		},
+		hookInstalled: func(dir string) bool { return dir == workRepo },
+	}
+
+	scanOutput = scanOutputJSON
+	scanJobs = 2
+
+	defer func() {
+		scanOutput = scanOutputTable
+		scanJobs = 8
+	}()
+
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	err := runner.run(cmd, []string{root})
+	if errors.ExitCode(err) != 1 {
+		t.Errorf("Expected exit code 1 with a conflicting override, got %v", err)
+	}
+
+	var results []scanResult
+	if err := json.Unmarshal(out.Bytes(), &results); err != nil {
+		t.Fatalf("Expected JSON output, got %v:\n%s", err, out.String())
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %+v", results)
+	}
+
+	work := results[0]
+	if work.Path != workRepo || work.Profile != "work" || !work.Hook || len(work.Conflicts) != 1 {
+		t.Errorf("Expected a conflicting work repository, got %+v", work)
+	}
+
+	if len(work.Hosts) != 1 || work.Hosts[0] != "github.com" {
+		t.Errorf("Expected remote hosts [github.com], got %v", work.Hosts)
+	}
+
+	personal := results[1]
+	if personal.Profile != "" || personal.Hook || len(personal.Conflicts) != 0 || personal.Email != "me@gmail.com" {
+		t.Errorf("Expected an unmatched personal repository, got %+v", personal)
+	}
+}
+
+func TestScanCommand_Fix(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "api")
+
+	mockCfg := &config.Config{
+		Profiles:  map[string]*config.Profile{"work": {Name: "Work User", Email: "work@corp.com"}},
+		AutoRules: []*config.AutoRule{{Path: root, Profile: "work"}},
+	}
+
+	local := map[string]string{"user.email": "me@gmail.com", "user.name": "Me"}
+
+	var unset []string
+
+	runner := &scanRunner{
+		load:           func() (*config.Config, error) { return mockCfg, nil },
+		findRepos:      func(string) ([]string, error) { return []string{repo}, nil },
+		getConfig:      func(dir, key string) (string, error) { return local[key], nil },
+		getLocalConfig: func(dir, key string) (string, error) { return local[key], nil },
+		getRemotes:     func(string) ([]string, error) { return nil, nil },
+		hookInstalled:  func(string) bool { return false },
+		unsetLocal: func(dir, key string) error {
+			unset = append(unset, key)
+
+			return nil
+		},
+		backup: func(dir string) (string, error) { return filepath.Join(dir, ".git", "config.gitego.bak"), nil },
+		stdin:  strings.NewReader("y\n"),
+	}
+
+	scanFix = true
+
+	defer func() { scanFix = false }()
+
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	err := runner.run(cmd, []string{root})
+
+	if strings.Join(unset, ",") != "user.email,user.name" {
+		t.Errorf("Expected both overrides to be removed, got %v", unset)
+	}
+
+	if !strings.Contains(out.String(), "- user.email = me@gmail.com") || !strings.Contains(out.String(), "Fixed 1 of 1") {
+		t.Errorf("Expected a dry-run diff and summary, got:\n%s", out.String())
+	}
+
+	if err != nil {
+		t.Errorf("Expected no error once every conflict is fixed, got %v", err)
+	}
+}
+
+func TestBackupGitConfig(t *testing.T) {
+	repo := t.TempDir()
+	configPath := filepath.Join(repo, ".git", "config")
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(configPath, []byte("[user]\n\temail = me@gmail.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := backupGitConfig(repo)
+	if err != nil || first != configPath+".gitego.bak" {
+		t.Fatalf("Expected %s.gitego.bak, got %s (%v)", configPath, first, err)
+	}
+
+	second, err := backupGitConfig(repo)
+	if err != nil || second != configPath+".gitego.bak.1" {
+		t.Fatalf("Expected an earlier backup to be kept, got %s (%v)", second, err)
+	}
+
+	content, _ := os.ReadFile(second)
+	if !strings.Contains(string(content), "me@gmail.com") {
+		t.Errorf("Expected the backup to copy .git/config, got %q", content)
+	}
+}
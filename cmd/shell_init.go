@@ -0,0 +1,146 @@
+// cmd/shell_init.go
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const bashPromptSnippet = `# gitego shell integration for bash.
+# Load it from ~/.bashrc with:  eval "$(gitego shell-init bash)"
+# then use $(__gitego_ps1) in PS1, e.g.  PS1='\u@\h \w$(__gitego_ps1)\$ '
+__gitego_ps1() {
+  local profile
+  profile="$(gitego prompt 2>/dev/null)"
+  [ -n "$profile" ] && printf ' (%s)' "$profile"
+}
+
+# Check the identity whenever the directory changes.
+__gitego_chpwd() {
+  [ "$PWD" = "$__gitego_last_pwd" ] && return
+  __gitego_last_pwd="$PWD"
+  eval "$(gitego internal chpwd --shell bash` + chpwdFlagsMarker + `)"
+}
+PROMPT_COMMAND="__gitego_chpwd${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+`
+
+const zshPromptSnippet = `# gitego shell integration for zsh.
+# Load it from ~/.zshrc with:  eval "$(gitego shell-init zsh)"
+# It shows the profile in RPROMPT unless you already use it; otherwise add
+# $(__gitego_ps1) to PROMPT or RPROMPT yourself.
+setopt PROMPT_SUBST
+__gitego_ps1() {
+  local profile
+  profile="$(gitego prompt 2>/dev/null)"
+  [[ -n "$profile" ]] && print -n " ($profile)"
+}
+[[ -z "$RPROMPT" ]] && RPROMPT='$(__gitego_ps1)'
+
+# Check the identity whenever the directory changes.
+__gitego_chpwd() {
+  eval "$(gitego internal chpwd --shell zsh` + chpwdFlagsMarker + `)"
+}
+autoload -Uz add-zsh-hook
+add-zsh-hook chpwd __gitego_chpwd
+__gitego_chpwd
+`
+
+const fishPromptSnippet = `# gitego shell integration for fish.
+# Load it from ~/.config/fish/config.fish with:  gitego shell-init fish | source
+# It shows the profile in fish_right_prompt unless you already define one;
+# otherwise call __gitego_ps1 from your own prompt function.
+function __gitego_ps1
+    set -l profile (gitego prompt 2>/dev/null)
+    test -n "$profile"; and printf ' (%s)' $profile
+end
+if not functions -q fish_right_prompt
+    function fish_right_prompt
+        __gitego_ps1
+    end
+end
+
+# Check the identity whenever the directory changes.
+function __gitego_chpwd --on-variable PWD
+    gitego internal chpwd --shell fish` + chpwdFlagsMarker + ` | source
+end
+__gitego_chpwd
+`
+
+const starshipPromptSnippet = `# gitego module for starship.
+# Add this to ~/.config/starship.toml and include ${custom.gitego} in your format
+# if you set one explicitly. The module is hidden when no profile applies.
+[custom.gitego]
+command = "gitego prompt"
+when = true
+shell = ["sh"]
+symbol = "👤 "
+style = "bold blue"
+format = "[$symbol$output]($style) "
+`
+
+// chpwdFlagsMarker is replaced with the extra flags the directory hook passes to
+// 'gitego internal chpwd'.
+const chpwdFlagsMarker = "@CHPWD_FLAGS@"
+
+var shellInitExportEnv bool
+
+// shellInitSnippets maps each supported shell to its integration snippet.
+var shellInitSnippets = map[string]string{
+	"bash":     bashPromptSnippet,
+	"zsh":      zshPromptSnippet,
+	"fish":     fishPromptSnippet,
+	"starship": starshipPromptSnippet,
+}
+
+// shellInitCmd represents the shell-init command.
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init [bash|zsh|fish|starship]",
+	Short: "Prints a snippet that integrates gitego with your shell prompt.",
+	Long: `Prints shell code that shows the gitego profile for the current directory in
+your prompt, using the fast 'gitego prompt' command. Each snippet starts with a
+comment explaining how to load it.
+
+For bash, zsh and fish the snippet also installs a hook that runs whenever the
+directory changes. It prints a one-line notice if GIT_AUTHOR_EMAIL,
+GIT_COMMITTER_EMAIL or Git's effective user.email disagree with the profile
+expected there. With --export-env it also exports the profile's environment
+variables (see 'gitego env') and GH_TOKEN from its stored token while inside a
+directory with an auto rule, and unsets them on leaving it.
+
+Bash:
+  $ eval "$(gitego shell-init bash)"       # in ~/.bashrc
+
+Zsh:
+  $ eval "$(gitego shell-init zsh)"        # in ~/.zshrc
+
+Fish:
+  $ gitego shell-init fish | source        # in ~/.config/fish/config.fish
+
+Starship:
+  $ gitego shell-init starship >> ~/.config/starship.toml`,
+	ValidArgs: []string{"bash", "zsh", "fish", "starship"},
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	Run: func(cmd *cobra.Command, args []string) {
+		_, _ = fmt.Fprint(cmd.OutOrStdout(), shellInitSnippet(args[0], shellInitExportEnv))
+	},
+}
+
+// shellInitSnippet returns the snippet for a shell with the hook's flags filled in.
+func shellInitSnippet(shell string, exportEnv bool) string {
+	flags := ""
+	if exportEnv {
+		flags = " --export-env"
+	}
+
+	return strings.ReplaceAll(shellInitSnippets[shell], chpwdFlagsMarker, flags)
+}
+
+func init() {
+	rootCmd.AddCommand(shellInitCmd)
+
+	shellInitCmd.Flags().BoolVar(&shellInitExportEnv, "export-env", false,
+		"Export profile-scoped variables such as GH_TOKEN inside matching directories")
+}
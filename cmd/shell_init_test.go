@@ -0,0 +1,31 @@
+// cmd/shell_init_test.go
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShellInitSnippets(t *testing.T) {
+	for _, shell := range shellInitCmd.ValidArgs {
+		snippet, exists := shellInitSnippets[shell]
+		if !exists {
+			t.Errorf("No snippet for %s", shell)
+
+			continue
+		}
+
+		if !strings.Contains(snippet, "gitego prompt") {
+			t.Errorf("Expected the %s snippet to use 'gitego prompt'", shell)
+		}
+
+		if strings.Contains(shellInitSnippet(shell, true), chpwdFlagsMarker) {
+			t.Errorf("Expected the %s snippet to have its hook flags filled in", shell)
+		}
+	}
+
+	if !strings.Contains(shellInitSnippet("zsh", true), "gitego internal chpwd --shell zsh --export-env") {
+		t.Errorf("Expected the zsh hook to pass --export-env")
+	}
+}
@@ -0,0 +1,62 @@
+// cmd/signing.go
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/utils"
+)
+
+// signingKeyAuto is the --signing-key value that asks gitego to pick a GPG key
+// whose user ID matches the profile's email.
+const signingKeyAuto = "auto"
+
+// resolveGPGSigningKey validates a profile's GPG signing key against the user's
+// keyring, or selects one when the key is "auto". Profiles that don't sign with
+// GPG are left alone. If gpg itself can't be run, an explicit key is accepted
+// with a warning since it may still be valid on the machine that signs.
+func resolveGPGSigningKey(listKeys func() ([]utils.GPGKey, error), profile *config.Profile) error {
+	auto := profile.SigningKey == signingKeyAuto
+	if !auto && (profile.SigningMode() != config.SigningGPG || profile.SigningKey == "") {
+		return nil
+	}
+
+	keys, err := listKeys()
+	if err != nil {
+		if auto {
+			return fmt.Errorf("could not list GPG keys: %w", err)
+		}
+
+		fmt.Printf("Warning: Could not verify GPG signing key: %v\n", err)
+
+		return nil
+	}
+
+	if auto {
+		key, err := utils.FindGPGKeyForEmail(keys, profile.Email, time.Now())
+		if err != nil {
+			return err
+		}
+
+		profile.SigningKey = key.KeyID
+		if key.Fingerprint != "" {
+			profile.SigningKey = key.Fingerprint
+		}
+
+		profile.SigningFormat = config.SigningGPG
+
+		printSuccess("✓ Selected GPG key %s for '%s'.\n", key.KeyID, profile.Email)
+
+		return nil
+	}
+
+	key, err := utils.FindGPGKey(keys, profile.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	return key.Validate(profile.Email, time.Now())
+}
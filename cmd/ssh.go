@@ -0,0 +1,119 @@
+// cmd/ssh.go
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/bgreenwell/gitego/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultSSHHost is the host used by 'ssh test' when none is given.
+	defaultSSHHost = "github.com"
+	// defaultSSHUser is the login user Git hosts expect for SSH remotes.
+	defaultSSHUser = "git"
+)
+
+// sshTestRunner holds the dependencies for the ssh test command for mocking.
+type sshTestRunner struct {
+	load     func() (*config.Config, error)
+	resolve  func([]string, string, string) (map[string]string, error)
+	testAuth func([]string, string, string) (string, error)
+}
+
+// run is the core logic for the ssh test command.
+func (r *sshTestRunner) run(cmd *cobra.Command, args []string) error {
+	profileName := args[0]
+
+	host := defaultSSHHost
+	if len(args) > 1 {
+		host = args[1]
+	}
+
+	cfg, err := r.load()
+	if err != nil {
+		return errLoadConfig(err)
+	}
+
+	profile, exists := cfg.Profiles[profileName]
+	if !exists {
+		return errProfileNotFound(profileName)
+	}
+
+	if profile.SSHKey == "" {
+		return errors.NotFound("profile '%s' has no SSH key", profileName).
+			WithHint("Use 'gitego edit %s --ssh-key <path>' to add one.", profileName)
+	}
+
+	env := []string{fmt.Sprintf("%s=%s", config.SSHProfileEnv, profileName)}
+
+	options, err := r.resolve(env, config.SSHConfigPath(), host)
+	if err != nil {
+		return errors.IO(err, "could not resolve ssh configuration for '%s'", host)
+	}
+
+	user := profile.SSHUser
+	if user == "" {
+		user = defaultSSHUser
+	}
+
+	out := cmd.OutOrStdout()
+
+	_, _ = fmt.Fprintf(out, "--- SSH identity for profile '%s' on %s ---\n", profileName, host)
+	_, _ = fmt.Fprintf(out, "  IdentityFile:   %s\n", strings.ReplaceAll(options["identityfile"], "\n", ", "))
+	_, _ = fmt.Fprintf(out, "  IdentitiesOnly: %s\n", options["identitiesonly"])
+
+	if agent := options["identityagent"]; agent != "" {
+		_, _ = fmt.Fprintf(out, "  IdentityAgent:  %s\n", agent)
+	}
+
+	_, _ = fmt.Fprintf(out, "  User:           %s\n", user)
+	_, _ = fmt.Fprintf(out, "  Port:           %s\n", options["port"])
+
+	greeting, err := r.testAuth(env, config.SSHConfigPath(), fmt.Sprintf("%s@%s", user, host))
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "  Server says:    (connection failed: %v)\n", err)
+	} else {
+		_, _ = fmt.Fprintf(out, "  Server says:    %s\n", greeting)
+	}
+
+	return nil
+}
+
+// sshCmd groups the SSH-related subcommands.
+var sshCmd = &cobra.Command{
+	Use:   "ssh",
+	Short: "Manages the SSH identities used by your profiles.",
+	Long: `gitego generates a managed ssh_config at ~/.gitego/ssh_config with one
+block per profile (IdentityFile, IdentitiesOnly, IdentityAgent, Port, User).
+Each profile's core.sshCommand points ssh at that file with -F, so ssh-agent
+can no longer offer a different account's key first.`,
+}
+
+// sshTestCmd represents the ssh test command.
+var sshTestCmd = &cobra.Command{
+	Use:   "test <profile_name> [host]",
+	Short: "Shows which SSH identity a profile would authenticate with.",
+	Long: `Resolves the ssh configuration gitego generates for a profile against a
+host (github.com by default), prints the identity ssh would offer, and asks
+the server which account that identity authenticates as.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &sshTestRunner{
+			load:     config.Load,
+			resolve:  utils.ResolveSSHConfig,
+			testAuth: utils.TestSSHAuth,
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	sshCmd.AddCommand(sshTestCmd)
+	rootCmd.AddCommand(sshCmd)
+}
@@ -0,0 +1,154 @@
+// cmd/ssh_keygen.go
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	keygenPassphrase bool
+	keygenSign       bool
+	keygenForce      bool
+)
+
+// sshKeygenRunner holds the dependencies for the ssh-keygen command for mocking.
+type sshKeygenRunner struct {
+	load           func() (*config.Config, error)
+	save           func(*config.Config) error
+	generateKey    func(string, string, string, bool) (string, string, error)
+	readPassphrase func() (string, error)
+}
+
+// run is the core logic for the ssh-keygen command.
+func (r *sshKeygenRunner) run(cmd *cobra.Command, args []string) error {
+	profileName := args[0]
+
+	cfg, err := r.load()
+	if err != nil {
+		return errLoadConfig(err)
+	}
+
+	profile, exists := cfg.Profiles[profileName]
+	if !exists {
+		return errProfileNotFound(profileName)
+	}
+
+	passphrase := ""
+	if keygenPassphrase {
+		passphrase, err = r.readPassphrase()
+		if err != nil {
+			return errors.IO(err, "could not read passphrase")
+		}
+	}
+
+	privPath, pubKey, err := r.generateKey(profileName, profile.Email, passphrase, keygenForce)
+	if err != nil {
+		if errors.Is(err, config.ErrKeyExists) {
+			return errors.AlreadyExists("profile '%s' already has a generated key", profileName).
+				WithHint("Use --force to replace it.")
+		}
+
+		return errors.IO(err, "could not generate key")
+	}
+
+	profile.SSHKey = privPath
+	if keygenSign {
+		profile.SigningKey = privPath + ".pub"
+		profile.SigningFormat = config.SigningSSH
+	}
+
+	if err := r.save(cfg); err != nil {
+		return errSaveConfig(err)
+	}
+
+	out := cmd.OutOrStdout()
+
+	fprintSuccess(out, "✓ Generated ed25519 key for profile '%s' at %s\n", profileName, privPath)
+
+	if keygenSign {
+		fprintSuccess(out, "✓ Key registered as the profile's SSH authentication and signing key.\n")
+	} else {
+		fprintSuccess(out, "✓ Key registered as the profile's SSH authentication key.\n")
+	}
+
+	_, _ = fmt.Fprintln(out, "\nAdd this public key to your Git hosting account:")
+	_, _ = fmt.Fprintln(out, pubKey)
+
+	return nil
+}
+
+// readNewPassphrase prompts twice for a passphrase, hiding input when stdin is a terminal.
+func readNewPassphrase() (string, error) {
+	first, err := readSecret("Enter passphrase (empty for no passphrase): ")
+	if err != nil {
+		return "", err
+	}
+
+	second, err := readSecret("Enter same passphrase again: ")
+	if err != nil {
+		return "", err
+	}
+
+	if first != second {
+		return "", errors.New("passphrases do not match")
+	}
+
+	return first, nil
+}
+
+// readSecret prints a prompt and reads one line from stdin without echoing it.
+func readSecret(prompt string) (string, error) {
+	fmt.Print(prompt)
+
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		secret, err := term.ReadPassword(fd)
+		fmt.Println()
+
+		return string(secret), err
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// sshKeygenCmd represents the ssh-keygen command.
+var sshKeygenCmd = &cobra.Command{
+	Use:   "ssh-keygen <profile_name>",
+	Short: "Generates an ed25519 SSH key for a profile.",
+	Long: `Generates an ed25519 keypair under ~/.gitego/keys/<profile_name>/ and
+registers it as the profile's SSH key. With --sign the key is also used to
+sign commits. The public key is printed in the authorized_keys format that
+GitHub, GitLab and other hosting providers accept.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &sshKeygenRunner{
+			load:           config.Load,
+			save:           func(c *config.Config) error { return c.Save() },
+			generateKey:    config.GenerateSSHKey,
+			readPassphrase: readNewPassphrase,
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sshKeygenCmd)
+
+	sshKeygenCmd.Flags().BoolVar(&keygenPassphrase, "passphrase", false, "Prompt for a passphrase to encrypt the private key")
+	sshKeygenCmd.Flags().BoolVar(&keygenSign, "sign", false, "Also use the key as the profile's commit signing key")
+	sshKeygenCmd.Flags().BoolVarP(&keygenForce, "force", "f", false, "Replace an existing generated key")
+}
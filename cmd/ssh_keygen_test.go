@@ -0,0 +1,76 @@
+// cmd/ssh_keygen_test.go
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/spf13/cobra"
+)
+
+func TestSSHKeygenCommand(t *testing.T) {
+	mockCfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"work": {Name: "Work User", Email: "work@example.com"},
+		},
+	}
+
+	var saved bool
+
+	var capturedComment, capturedPassphrase string
+
+	runner := &sshKeygenRunner{
+		load: func() (*config.Config, error) { return mockCfg, nil },
+		save: func(c *config.Config) error {
+			saved = true
+
+			return nil
+		},
+		generateKey: func(profileName, comment, passphrase string, _ bool) (string, string, error) {
+			capturedComment = comment
+			capturedPassphrase = passphrase
+
+			return "/keys/" + profileName + "/id_ed25519", "ssh-ed25519 AAAAtest " + comment, nil
+		},
+		readPassphrase: func() (string, error) { return "hunter2", nil },
+	}
+
+	keygenPassphrase = true
+	keygenSign = true
+
+	defer func() {
+		keygenPassphrase = false
+		keygenSign = false
+	}()
+
+	var buf bytes.Buffer
+
+	keygenCmd := &cobra.Command{}
+	keygenCmd.SetOut(&buf)
+	runner.run(keygenCmd, []string{"work"})
+
+	if !saved {
+		t.Error("Expected config to be saved, but it wasn't.")
+	}
+
+	if capturedComment != "work@example.com" || capturedPassphrase != "hunter2" {
+		t.Errorf("Expected key to be generated with the profile email and passphrase, got '%s' and '%s'",
+			capturedComment, capturedPassphrase)
+	}
+
+	profile := mockCfg.Profiles["work"]
+	if profile.SSHKey != "/keys/work/id_ed25519" {
+		t.Errorf("Expected SSH key to be registered, got '%s'", profile.SSHKey)
+	}
+
+	if profile.SigningKey != "/keys/work/id_ed25519.pub" {
+		t.Errorf("Expected signing key to be the public key, got '%s'", profile.SigningKey)
+	}
+
+	if !strings.Contains(buf.String(), "ssh-ed25519 AAAAtest work@example.com") {
+		t.Errorf("Expected the public key to be printed, got:\n%s", buf.String())
+	}
+}
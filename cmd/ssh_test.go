@@ -0,0 +1,64 @@
+// cmd/ssh_test.go
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/spf13/cobra"
+)
+
+func TestSSHTestCommand(t *testing.T) {
+	mockCfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"work": {Name: "Work User", Email: "work@example.com", SSHKey: "~/.ssh/id_work"},
+		},
+	}
+
+	var resolvedEnv []string
+
+	var authTarget string
+
+	runner := &sshTestRunner{
+		load: func() (*config.Config, error) { return mockCfg, nil },
+		resolve: func(env []string, _ string, host string) (map[string]string, error) {
+			resolvedEnv = env
+
+			return map[string]string{
+				"identityfile":   "~/.ssh/id_work",
+				"identitiesonly": "yes",
+				"port":           "22",
+			}, nil
+		},
+		testAuth: func(_ []string, _ string, target string) (string, error) {
+			authTarget = target
+
+			return "Hi work-user! You've successfully authenticated.", nil
+		},
+	}
+
+	var buf bytes.Buffer
+
+	testCmd := &cobra.Command{}
+	testCmd.SetOut(&buf)
+	runner.run(testCmd, []string{"work"})
+
+	output := buf.String()
+
+	if len(resolvedEnv) != 1 || resolvedEnv[0] != config.SSHProfileEnv+"=work" {
+		t.Errorf("Expected ssh to be run with the profile selected, got env %v", resolvedEnv)
+	}
+
+	if authTarget != "git@github.com" {
+		t.Errorf("Expected to authenticate against 'git@github.com', got '%s'", authTarget)
+	}
+
+	for _, want := range []string{"~/.ssh/id_work", "IdentitiesOnly: yes", "Hi work-user!"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, but it didn't.\nOutput:\n%s", want, output)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package cmd
 
 import (
 	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
 	"github.com/bgreenwell/gitego/utils"
 	"github.com/spf13/cobra"
 )
@@ -12,17 +13,16 @@ import (
 type statusRunner struct {
 	load         func() (*config.Config, error)
 	getGitConfig func(string) (string, error)
+	findRepo     func() (*config.RepoConfig, error)
 }
 
 // run contains the core logic for the status command.
-func (sr *statusRunner) run(cmd *cobra.Command, args []string) {
+func (sr *statusRunner) run(cmd *cobra.Command, args []string) error {
 	name, errName := sr.getGitConfig("user.name")
 	email, errEmail := sr.getGitConfig("user.email")
 
 	if errName != nil || errEmail != nil {
-		cmd.PrintErrln("Not inside a Git repository or user not configured.")
-
-		return
+		return errors.NotFound("not inside a Git repository or user not configured")
 	}
 
 	cfg, err := sr.load()
@@ -35,7 +35,7 @@ func (sr *statusRunner) run(cmd *cobra.Command, args []string) {
 	if cfg != nil {
 		// This will check the current directory against the loaded rules.
 		_, ruleSource := cfg.GetActiveProfileForCurrentDir()
-		if ruleSource != "No active gitego profile" {
+		if ruleSource != config.NoProfileSource {
 			source = ruleSource
 		}
 	}
@@ -44,7 +44,20 @@ func (sr *statusRunner) run(cmd *cobra.Command, args []string) {
 	cmd.Printf("  Name:   %s\n", name)
 	cmd.Printf("  Email:  %s\n", email)
 	cmd.Printf("  Source: %s\n", source)
+
+	if sr.findRepo != nil {
+		if repoConfig, err := sr.findRepo(); err == nil && repoConfig != nil {
+			trust := "trusted"
+			if !repoConfig.Trusted {
+				trust = "not trusted; run 'gitego trust' to apply it"
+			}
+
+			cmd.Printf("  Repo:   %s (%s)\n", config.RepoConfigName, trust)
+		}
+	}
 	cmd.Println("---------------------------")
+
+	return nil
 }
 
 var statusCmd = &cobra.Command{
@@ -54,12 +67,13 @@ var statusCmd = &cobra.Command{
 to show you which user.name and user.email are currently in effect. It also
 tells you whether the configuration is coming from your global .gitconfig or
 from a gitego auto-switch rule.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		runner := &statusRunner{
 			load:         config.Load,
 			getGitConfig: utils.GetEffectiveGitConfig,
+			findRepo:     func() (*config.RepoConfig, error) { return config.FindRepoConfig(".") },
 		}
-		runner.run(cmd, args)
+		return runner.run(cmd, args)
 	},
 }
 
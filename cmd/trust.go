@@ -0,0 +1,136 @@
+// cmd/trust.go
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/spf13/cobra"
+)
+
+var trustRevoke bool
+
+// trustRunner holds the dependencies for the trust command for mocking.
+type trustRunner struct {
+	findRepo func(string) (*config.RepoConfig, error)
+	trust    func(*config.RepoConfig) error
+	revoke   func(string) (bool, error)
+}
+
+// run is the core logic for the trust command.
+func (r *trustRunner) run(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	repoConfig, err := r.findRepo(dir)
+	if err != nil {
+		return errors.IO(err, "could not read the repository's %s", config.RepoConfigName)
+	}
+
+	if repoConfig == nil {
+		return errors.NotFound("no %s found at the root of this repository", config.RepoConfigName)
+	}
+
+	out := cmd.OutOrStdout()
+
+	if trustRevoke {
+		revoked, err := r.revoke(repoConfig.Path)
+		if err != nil {
+			return errors.IO(err, "could not revoke trust")
+		}
+
+		if !revoked {
+			_, _ = fmt.Fprintf(out, "%s was not trusted.\n", repoConfig.Path)
+
+			return nil
+		}
+
+		fprintSuccess(out, "✓ %s is no longer trusted.\n", repoConfig.Path)
+
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "%s declares:\n", repoConfig.Path)
+
+	for _, line := range describeRepoConfig(repoConfig) {
+		_, _ = fmt.Fprintf(out, "  %s\n", line)
+	}
+
+	if repoConfig.Trusted {
+		fprintSuccess(out, "✓ Already trusted.\n")
+
+		return nil
+	}
+
+	if err := r.trust(repoConfig); err != nil {
+		return errors.IO(err, "could not record trust")
+	}
+
+	fprintSuccess(out, "✓ Trusted %s.\n", repoConfig.Path)
+
+	return nil
+}
+
+// describeRepoConfig lists what a .gitego.yaml asks for, one item per line.
+func describeRepoConfig(repoConfig *config.RepoConfig) []string {
+	var lines []string
+
+	if repoConfig.Profile != "" {
+		lines = append(lines, fmt.Sprintf("profile: %s", repoConfig.Profile))
+	}
+
+	if len(repoConfig.AllowedEmailDomains) > 0 {
+		lines = append(lines, "allowed email domains: "+strings.Join(repoConfig.AllowedEmailDomains, ", "))
+	}
+
+	if repoConfig.RequireSigning {
+		lines = append(lines, "signed commits required")
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, "(nothing)")
+	}
+
+	return lines
+}
+
+// trustCmd represents the trust command.
+var trustCmd = &cobra.Command{
+	Use:   "trust [path]",
+	Short: "Trusts a repository's .gitego.yaml.",
+	Long: `Repositories can commit a .gitego.yaml at their root naming the profile they
+expect, the email domains commits may use, and whether commits must be signed:
+
+  profile: work
+  allowed_email_domains: [corp.com]
+  require_signing: true
+
+gitego ignores the file until you trust it, so cloning a repository can't
+silently change your identity. The pre-commit hook asks the first time it sees
+a file; this command trusts it up front. Trust covers the file's exact
+contents, so you are asked again whenever it changes. Use --revoke to forget a
+trust decision.
+
+A profile named in a trusted .gitego.yaml is used when none of your auto-switch
+rules match the repository.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &trustRunner{
+			findRepo: config.FindRepoConfig,
+			trust:    (*config.RepoConfig).Trust,
+			revoke:   config.RevokeRepoTrust,
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trustCmd)
+
+	trustCmd.Flags().BoolVar(&trustRevoke, "revoke", false, "Stop trusting the repository's .gitego.yaml")
+}
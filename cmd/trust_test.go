@@ -0,0 +1,52 @@
+// cmd/trust_test.go
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/spf13/cobra"
+)
+
+func TestTrustCommand(t *testing.T) {
+	repoConfig := &config.RepoConfig{Path: "/src/app/.gitego.yaml", Profile: "work", RequireSigning: true}
+
+	var trusted, revoked []string
+
+	runner := &trustRunner{
+		findRepo: func(string) (*config.RepoConfig, error) { return repoConfig, nil },
+		trust: func(rc *config.RepoConfig) error {
+			trusted = append(trusted, rc.Path)
+
+			return nil
+		},
+		revoke: func(path string) (bool, error) {
+			revoked = append(revoked, path)
+
+			return true, nil
+		},
+	}
+
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	runner.run(cmd, []string{})
+
+	if len(trusted) != 1 || !strings.Contains(out.String(), "profile: work") {
+		t.Errorf("Expected the file to be described and trusted, got %v.\nOutput:\n%s", trusted, out.String())
+	}
+
+	trustRevoke = true
+
+	defer func() { trustRevoke = false }()
+
+	runner.run(cmd, []string{})
+
+	if len(revoked) != 1 || revoked[0] != repoConfig.Path {
+		t.Errorf("Expected trust for %s to be revoked, got %v", repoConfig.Path, revoked)
+	}
+}
@@ -0,0 +1,297 @@
+// cmd/uninstall.go
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/bgreenwell/gitego/utils"
+	"github.com/spf13/cobra"
+)
+
+// gitegoGlobalSettings are the global git settings 'gitego use' may point into ~/.gitego.
+var gitegoGlobalSettings = []string{"core.sshCommand", "gpg.ssh.allowedSignersFile", "user.signingkey"}
+
+var (
+	uninstallDryRun     bool
+	uninstallKeepConfig bool
+	uninstallYes        bool
+)
+
+// uninstallStep is one change uninstall makes.
+type uninstallStep struct {
+	description string
+	apply       func() error
+}
+
+// uninstallRunner holds the dependencies for the uninstall command for mocking.
+type uninstallRunner struct {
+	load                func() (*config.Config, error)
+	getGlobalGitAll     func(string) ([]string, error)
+	replaceGlobalGitAll func(string, []string) error
+	listIncludeIfs      func() ([]string, error)
+	removeIncludeIfs    func() error
+	findRepos           func(string) ([]string, error)
+	currentRepo         func() (string, error)
+	hookInstalled       func(string) bool
+	removeHook          func(string) error
+	deleteSecrets       func() error
+	exists              func(string) bool
+	removePath          func(string) error
+	stdin               io.Reader
+}
+
+// run is the core logic for the uninstall command.
+func (r *uninstallRunner) run(cmd *cobra.Command, args []string) error {
+	cfg, err := r.load()
+	if err != nil {
+		fmt.Printf("Warning: Could not load gitego config: %v\n", err)
+
+		cfg = &config.Config{}
+	}
+
+	steps := r.plan(cfg)
+	if len(steps) == 0 {
+		fmt.Println("Nothing to remove; gitego has left no traces.")
+
+		return nil
+	}
+
+	fmt.Println("gitego uninstall will:")
+
+	for _, step := range steps {
+		fmt.Printf("  - %s\n", step.description)
+	}
+
+	if uninstallDryRun {
+		return nil
+	}
+
+	if !uninstallYes {
+		fmt.Print("\nProceed? This cannot be undone. [y/N]: ")
+
+		if !readYes(bufio.NewReader(r.stdin)) {
+			fmt.Println("Uninstall cancelled.")
+
+			return nil
+		}
+	}
+
+	fmt.Println()
+
+	failed := 0
+
+	for _, step := range steps {
+		if err := step.apply(); err != nil {
+			printError(os.Stderr, errors.IO(err, "could not %s", step.description))
+
+			failed++
+
+			continue
+		}
+
+		printSuccess("✓ %s\n", capitalize(step.description))
+	}
+
+	if failed > 0 {
+		return &errors.Error{Kind: errors.KindIO, Message: fmt.Sprintf("%d step(s) failed; see the errors above", failed)}
+	}
+
+	printSuccess("\n✓ gitego has been uninstalled.\n")
+	fmt.Println("Remove any 'gitego shell-init' line from your shell startup files, then delete the gitego binary.")
+
+	return nil
+}
+
+// plan lists every change needed to remove gitego from this machine.
+func (r *uninstallRunner) plan(cfg *config.Config) []uninstallStep {
+	var steps []uninstallStep
+
+	if step, ok := r.credentialHelperStep(cfg); ok {
+		steps = append(steps, step)
+	}
+
+	for _, key := range gitegoGlobalSettings {
+		values, err := r.getGlobalGitAll(key)
+		if err != nil || !slices.ContainsFunc(values, config.RefersToGitego) {
+			continue
+		}
+
+		steps = append(steps, uninstallStep{
+			description: fmt.Sprintf("unset global %s (%s)", key, strings.Join(values, ", ")),
+			apply:       func() error { return r.replaceGlobalGitAll(key, nil) },
+		})
+	}
+
+	if headers, err := r.listIncludeIfs(); err == nil && len(headers) > 0 {
+		steps = append(steps, uninstallStep{
+			description: fmt.Sprintf("remove %d includeIf block(s) from ~/.gitconfig: %s",
+				len(headers), strings.Join(headers, ", ")),
+			apply: r.removeIncludeIfs,
+		})
+	}
+
+	for _, repo := range r.hookedRepos(cfg) {
+		steps = append(steps, uninstallStep{
+			description: "remove gitego from the hooks in " + repo,
+			apply:       func() error { return r.removeHook(repo) },
+		})
+	}
+
+	if uninstallKeepConfig {
+		for _, path := range config.GeneratedPaths() {
+			if r.exists(path) {
+				steps = append(steps, uninstallStep{
+					description: "delete generated " + path,
+					apply:       func() error { return r.removePath(path) },
+				})
+			}
+		}
+
+		return steps
+	}
+
+	steps = append(steps, uninstallStep{
+		description: "delete every token and secret gitego stored in the OS keychain",
+		apply:       r.deleteSecrets,
+	})
+
+	if dir := config.Dir(); r.exists(dir) {
+		description := "delete " + dir
+		if r.exists(config.KeysDir()) {
+			description += ", including the SSH keys in " + config.KeysDir()
+		}
+
+		steps = append(steps, uninstallStep{
+			description: description,
+			apply:       func() error { return r.removePath(dir) },
+		})
+	}
+
+	return steps
+}
+
+// credentialHelperStep returns the step that takes gitego out of credential.helper. Helpers
+// recorded by 'gitego init' are restored, along with any added since.
+func (r *uninstallRunner) credentialHelperStep(cfg *config.Config) (uninstallStep, bool) {
+	helpers, err := r.getGlobalGitAll("credential.helper")
+	if err != nil || !hasGitegoHelper(helpers) {
+		return uninstallStep{}, false
+	}
+
+	restored := slices.Clone(cfg.PreviousCredentialHelpers)
+
+	for _, helper := range helpers {
+		if helper != "" && !strings.Contains(helper, "gitego credential") && !slices.Contains(restored, helper) {
+			restored = append(restored, helper)
+		}
+	}
+
+	// Nothing but resets left means no helper at all.
+	if !slices.ContainsFunc(restored, func(helper string) bool { return helper != "" }) {
+		restored = nil
+	}
+
+	description := "remove gitego from credential.helper"
+	if len(restored) > 0 {
+		description = fmt.Sprintf("restore credential.helper to %q", restored)
+	}
+
+	return uninstallStep{
+		description: description,
+		apply:       func() error { return r.replaceGlobalGitAll("credential.helper", restored) },
+	}, true
+}
+
+// hookedRepos returns the repositories under each auto rule, and the current one, with a
+// hook that runs gitego.
+func (r *uninstallRunner) hookedRepos(cfg *config.Config) []string {
+	var candidates []string
+
+	if repo, err := r.currentRepo(); err == nil {
+		candidates = append(candidates, repo)
+	}
+
+	for _, rule := range cfg.AutoRules {
+		if repos, err := r.findRepos(config.ExpandHome(rule.Path)); err == nil {
+			candidates = append(candidates, repos...)
+		}
+	}
+
+	var hooked []string
+
+	for _, repo := range candidates {
+		if !slices.Contains(hooked, repo) && r.hookInstalled(repo) {
+			hooked = append(hooked, repo)
+		}
+	}
+
+	return hooked
+}
+
+// capitalize upper-cases the first letter of a step description for the progress output.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// uninstallCmd represents the uninstall command.
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Removes everything gitego has added to your system.",
+	Long: `Finds and removes everything gitego wrote outside its own config: the
+credential.helper entry (restoring the helpers 'gitego init' replaced), global
+settings pointing into ~/.gitego, the includeIf blocks in ~/.gitconfig, and
+gitego's hooks in the current repository and in repositories under your auto
+rules. It then deletes the tokens and secrets in the OS keychain and the
+~/.gitego directory, including any SSH keys generated by 'gitego ssh-keygen'.
+
+With --keep-config, config.yaml, trusted repositories, generated SSH keys and
+keychain entries are kept, so that gitego can be set up again later; only the
+files gitego regenerates are deleted.
+
+Use --dry-run to list the changes without making them. Your global user.name
+and user.email are left as they are.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &uninstallRunner{
+			load:                config.Load,
+			getGlobalGitAll:     utils.GetGlobalGitConfigAll,
+			replaceGlobalGitAll: utils.ReplaceGlobalGitConfigAll,
+			listIncludeIfs:      config.ListIncludeIfs,
+			removeIncludeIfs:    config.RemoveAllIncludeIfs,
+			findRepos:           findGitRepos,
+			currentRepo:         func() (string, error) { return findGitRoot(".") },
+			hookInstalled:       func(repo string) bool { return len(installedGitegoHooks(repo)) > 0 },
+			removeHook:          removeGitegoHooks,
+			deleteSecrets:       config.DeleteAllSecrets,
+			exists: func(path string) bool {
+				_, err := os.Stat(path)
+
+				return err == nil
+			},
+			removePath: os.RemoveAll,
+			stdin:      os.Stdin,
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uninstallCmd)
+
+	uninstallCmd.Flags().BoolVar(&uninstallDryRun, "dry-run", false, "List what would be removed without changing anything")
+	uninstallCmd.Flags().BoolVar(&uninstallKeepConfig, "keep-config", false,
+		"Keep config.yaml, SSH keys and stored tokens so gitego can be set up again")
+	uninstallCmd.Flags().BoolVarP(&uninstallYes, "yes", "y", false, "Uninstall without asking for confirmation")
+}
@@ -0,0 +1,145 @@
+// cmd/uninstall_hook.go
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/spf13/cobra"
+)
+
+var uninstallHookCmd = &cobra.Command{
+	Use:   "uninstall-hook [hook...]",
+	Short: "Removes the gitego hooks from the current repository.",
+	Long: `Removes gitego's block from the hooks in the current Git repository,
+leaving anything else in the hook scripts untouched. A hook script that gitego
+created is deleted once nothing else is left in it. gitego hooks added to
+.pre-commit-config.yaml or .husky/ are removed too.
+
+By default every hook type gitego installs is cleaned up; name hook types (for
+example, pre-commit) to remove only those.`,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return hookNames(), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hooks, err := selectGitHooks(args)
+		if err != nil {
+			return err
+		}
+
+		gitRoot, err := findGitRoot(".")
+		if err != nil {
+			return errNotGitRepo()
+		}
+
+		removed := 0
+		failed := false
+
+		// A hook that can't be cleaned up doesn't stop the others from being removed.
+		fail := func(err error) {
+			printError(os.Stderr, err)
+
+			failed = true
+		}
+
+		for _, hook := range hooks {
+			found, err := removeGitHook(gitRoot, hook)
+			if err != nil {
+				fail(errors.IO(err, "could not remove gitego from the %s hook", hook.name))
+			} else if found {
+				removed++
+
+				printSuccess("✓ gitego removed from %s\n", hook.path(gitRoot))
+			}
+
+			for _, framework := range hookFrameworks {
+				found, err := framework.remove(gitRoot, hook)
+				if err != nil {
+					fail(errors.IO(err, "could not remove the gitego %s hook from %s", hook.name, framework.path))
+				} else if found {
+					removed++
+
+					printSuccess("✓ gitego %s hook removed from %s\n", hook.name, framework.path)
+				}
+			}
+		}
+
+		if failed {
+			return &errors.Error{Kind: errors.KindIO, Message: "some hooks could not be removed; see the errors above"}
+		}
+
+		if removed == 0 {
+			fmt.Println("No gitego hooks are installed in this repository.")
+		}
+
+		return nil
+	},
+}
+
+// selectGitHooks returns the gitego hooks with the given names, or all of them if none are given.
+func selectGitHooks(names []string) ([]gitHook, error) {
+	if len(names) == 0 {
+		return gitegoHooks, nil
+	}
+
+	var hooks []gitHook
+
+	for _, name := range names {
+		index := slices.IndexFunc(gitegoHooks, func(hook gitHook) bool { return hook.name == name })
+		if index < 0 {
+			return nil, errors.InvalidInput("gitego does not install a '%s' hook; choose from %v", name, hookNames())
+		}
+
+		hooks = append(hooks, gitegoHooks[index])
+	}
+
+	return hooks, nil
+}
+
+// hookNames returns the names of the hook types gitego installs.
+func hookNames() []string {
+	names := make([]string, 0, len(gitegoHooks))
+	for _, hook := range gitegoHooks {
+		names = append(names, hook.name)
+	}
+
+	return names
+}
+
+// installedGitegoHooks returns the gitego hooks installed in the repository at gitRoot.
+func installedGitegoHooks(gitRoot string) []gitHook {
+	var installed []gitHook
+
+	for _, hook := range gitegoHooks {
+		if hook.installed(gitRoot) {
+			installed = append(installed, hook)
+		}
+	}
+
+	return installed
+}
+
+// removeGitegoHooks removes gitego from every hook in the repository at gitRoot, including
+// those set up through a hook manager.
+func removeGitegoHooks(gitRoot string) error {
+	for _, hook := range gitegoHooks {
+		if _, err := removeGitHook(gitRoot, hook); err != nil {
+			return fmt.Errorf("%s hook: %w", hook.name, err)
+		}
+
+		for _, framework := range hookFrameworks {
+			if _, err := framework.remove(gitRoot, hook); err != nil {
+				return fmt.Errorf("%s hook in %s: %w", hook.name, framework.path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(uninstallHookCmd)
+}
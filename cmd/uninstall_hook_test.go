@@ -0,0 +1,123 @@
+// cmd/uninstall_hook_test.go
+
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/errors"
+)
+
+func TestRemoveGitHook(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string // hook content before gitego is installed; "" for none
+		legacy   string // hook content written by an older, unmarked install
+		expected string // content after removal; "" if the file should be deleted
+	}{
+		{name: "created by gitego", expected: ""},
+		{name: "appended to a script", existing: "#!/bin/sh\nmake lint\n", expected: "#!/bin/sh\nmake lint\n"},
+		{name: "appended to a script without a trailing newline",
+			existing: "#!/bin/sh\nmake lint", expected: "#!/bin/sh\nmake lint\n"},
+		{name: "legacy created", legacy: "#!/bin/sh" + legacyHookScriptContent, expected: ""},
+		{name: "legacy appended", legacy: "#!/bin/sh\nmake lint\n" + legacyHookScriptContent,
+			expected: "#!/bin/sh\nmake lint\n"},
+		{name: "user's own empty script", legacy: "#!/bin/sh\ngitego internal check-commit\n", expected: "#!/bin/sh\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoRoot, hooksDir := setupTestGitRepo(t)
+			defer func() { _ = os.RemoveAll(repoRoot) }()
+
+			hookPath := filepath.Join(hooksDir, "pre-commit")
+
+			switch {
+			case tt.legacy != "":
+				createExistingHook(hooksDir, tt.legacy)
+			case tt.existing != "":
+				createExistingHook(hooksDir, tt.existing)
+
+				fallthrough
+			default:
+				captureOutput(t, "", func() {
+					installGitHook(repoRoot, preCommitHook, bufio.NewReader(strings.NewReader("y\n")))
+				})
+			}
+
+			removed, err := removeGitHook(repoRoot, preCommitHook)
+			if err != nil || !removed {
+				t.Fatalf("Expected the hook to be removed, got %v, %v", removed, err)
+			}
+
+			content, err := os.ReadFile(hookPath)
+
+			if tt.expected == "" {
+				if !os.IsNotExist(err) {
+					t.Errorf("Expected the hook file to be deleted, got %q", content)
+				}
+
+				return
+			}
+
+			if string(content) != tt.expected {
+				t.Errorf("Expected hook %q, got %q", tt.expected, content)
+			}
+		})
+	}
+}
+
+func TestUninstallHookCommand(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current working directory: %v", err)
+	}
+
+	_, hooksDir, cleanup := setupTestRepoAndChangeDir(t, originalWd)
+	defer cleanup()
+
+	output := captureOutput(t, "", func() {
+		if err := uninstallHookCmd.RunE(uninstallHookCmd, []string{}); err != nil {
+			t.Error(err)
+		}
+	})
+
+	if !strings.Contains(output, "No gitego hooks") {
+		t.Errorf("Expected a message that nothing is installed, got: %s", output)
+	}
+
+	captureOutput(t, "", func() {
+		if err := installHookCmd.RunE(installHookCmd, []string{}); err != nil {
+			t.Error(err)
+		}
+	})
+
+	output = captureOutput(t, "", func() {
+		if err := uninstallHookCmd.RunE(uninstallHookCmd, []string{"pre-commit"}); err != nil {
+			t.Error(err)
+		}
+	})
+
+	if !strings.Contains(output, "gitego removed from") {
+		t.Errorf("Expected a success message, got: %s", output)
+	}
+
+	if _, err := os.Stat(filepath.Join(hooksDir, "pre-commit")); !os.IsNotExist(err) {
+		t.Error("Expected the hook gitego created to be deleted.")
+	}
+
+	// The commit-msg hook was installed too, passes on the message file and is left alone.
+	content, err := os.ReadFile(filepath.Join(hooksDir, "commit-msg"))
+	if err != nil || !strings.Contains(string(content), `gitego hook commit-msg "$1"`) {
+		t.Errorf("Expected the commit-msg hook to be kept, got %q (%v)", content, err)
+	}
+
+	err = uninstallHookCmd.RunE(uninstallHookCmd, []string{"post-merge"})
+	if errors.KindOf(err) != errors.KindInvalidInput || !strings.Contains(err.Error(), "does not install a 'post-merge' hook") {
+		t.Errorf("Expected an error for an unknown hook type, got: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+// cmd/uninstall_test.go
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/spf13/cobra"
+)
+
+// newTestUninstallRunner returns an uninstallRunner over fake global git settings, recording
+// the repositories whose hooks and the paths it removes.
+func newTestUninstallRunner(
+	mockCfg *config.Config, settings map[string][]string,
+) (*uninstallRunner, *[]string, *[]string, *bool) {
+	var removedHooks, removedPaths []string
+
+	includeIfsRemoved := false
+
+	runner := &uninstallRunner{
+		load:            func() (*config.Config, error) { return mockCfg, nil },
+		getGlobalGitAll: func(key string) ([]string, error) { return settings[key], nil },
+		replaceGlobalGitAll: func(key string, values []string) error {
+			settings[key] = values
+
+			return nil
+		},
+		listIncludeIfs:   func() ([]string, error) { return []string{`[includeIf "gitdir:/src/work/"]`}, nil },
+		removeIncludeIfs: func() error { includeIfsRemoved = true; return nil },
+		findRepos: func(root string) ([]string, error) {
+			return []string{filepath.Join(root, "hooked"), filepath.Join(root, "plain")}, nil
+		},
+		currentRepo:   func() (string, error) { return "/src/work/hooked", nil },
+		hookInstalled: func(repo string) bool { return strings.HasSuffix(repo, "hooked") },
+		removeHook:    func(repo string) error { removedHooks = append(removedHooks, repo); return nil },
+		deleteSecrets: func() error { removedPaths = append(removedPaths, "keychain"); return nil },
+		exists:        func(string) bool { return true },
+		removePath:    func(path string) error { removedPaths = append(removedPaths, path); return nil },
+		stdin:         strings.NewReader("y\n"),
+	}
+
+	return runner, &removedHooks, &removedPaths, &includeIfsRemoved
+}
+
+func TestUninstallCommand(t *testing.T) {
+	mockCfg := &config.Config{
+		AutoRules:                 []*config.AutoRule{{Path: "/src/work/", Profile: "work"}},
+		PreviousCredentialHelpers: []string{"osxkeychain"},
+	}
+	settings := map[string][]string{
+		"credential.helper": {"", gitegoCredentialHelper, "cache"},
+		"core.sshCommand":   {config.SSHCommand("work")},
+		"user.signingkey":   {"ABCDEF"},
+	}
+
+	runner, removedHooks, removedPaths, includeIfsRemoved := newTestUninstallRunner(mockCfg, settings)
+
+	runner.run(&cobra.Command{}, []string{})
+
+	if !slices.Equal(settings["credential.helper"], []string{"osxkeychain", "cache"}) {
+		t.Errorf("Expected the previous helpers to be restored, got %q", settings["credential.helper"])
+	}
+
+	if settings["core.sshCommand"] != nil {
+		t.Errorf("Expected core.sshCommand to be unset, got %q", settings["core.sshCommand"])
+	}
+
+	if !slices.Equal(settings["user.signingkey"], []string{"ABCDEF"}) {
+		t.Error("Expected a signing key outside ~/.gitego to be left alone.")
+	}
+
+	if !*includeIfsRemoved {
+		t.Error("Expected the includeIf blocks to be removed.")
+	}
+
+	if !slices.Equal(*removedHooks, []string{"/src/work/hooked"}) {
+		t.Errorf("Expected only the hooked repository to be cleaned once, got %v", *removedHooks)
+	}
+
+	if !slices.Equal(*removedPaths, []string{"keychain", config.Dir()}) {
+		t.Errorf("Expected the keychain and %s to be removed, got %v", config.Dir(), *removedPaths)
+	}
+}
+
+func TestUninstallCommand_KeepConfig(t *testing.T) {
+	settings := map[string][]string{"credential.helper": {"", gitegoCredentialHelper}}
+	runner, _, removedPaths, _ := newTestUninstallRunner(&config.Config{}, settings)
+
+	uninstallKeepConfig = true
+	uninstallYes = true
+
+	defer func() {
+		uninstallKeepConfig = false
+		uninstallYes = false
+	}()
+
+	runner.run(&cobra.Command{}, []string{})
+
+	if settings["credential.helper"] != nil {
+		t.Errorf("Expected credential.helper to be unset, got %q", settings["credential.helper"])
+	}
+
+	if !slices.Equal(*removedPaths, config.GeneratedPaths()) {
+		t.Errorf("Expected only generated files to be removed, got %v", *removedPaths)
+	}
+}
+
+func TestUninstallCommand_DryRun(t *testing.T) {
+	settings := map[string][]string{"credential.helper": {gitegoCredentialHelper}}
+	runner, removedHooks, removedPaths, includeIfsRemoved := newTestUninstallRunner(&config.Config{}, settings)
+
+	uninstallDryRun = true
+
+	defer func() { uninstallDryRun = false }()
+
+	runner.run(&cobra.Command{}, []string{})
+
+	if len(settings["credential.helper"]) != 1 || len(*removedHooks) != 0 || len(*removedPaths) != 0 || *includeIfsRemoved {
+		t.Error("Expected --dry-run to change nothing.")
+	}
+}
+
+// TestRemoveGitegoHooks_Legacy verifies that hooks written before gitego marked its blocks
+// are still cleaned up.
+func TestRemoveGitegoHooks_Legacy(t *testing.T) {
+	repoRoot, hooksDir := setupTestGitRepo(t)
+	defer func() { _ = os.RemoveAll(repoRoot) }()
+
+	hookPath := filepath.Join(hooksDir, "pre-commit")
+
+	// An appended check leaves the rest of the hook in place.
+	if err := os.WriteFile(hookPath, []byte("#!/bin/sh\nmake lint\n"+legacyHookScriptContent), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+
+	if err := removeGitegoHooks(repoRoot); err != nil {
+		t.Fatalf("removeGitegoHooks returned an error: %v", err)
+	}
+
+	content, err := os.ReadFile(hookPath)
+	if err != nil || string(content) != "#!/bin/sh\nmake lint\n" {
+		t.Errorf("Expected only the gitego check to be removed, got %q (%v)", content, err)
+	}
+
+	// A hook gitego created is deleted.
+	if err := os.WriteFile(hookPath, []byte("#!/bin/sh"+legacyHookScriptContent), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+
+	if err := removeGitegoHooks(repoRoot); err != nil {
+		t.Fatalf("removeGitegoHooks returned an error: %v", err)
+	}
+
+	if _, err := os.Stat(hookPath); !os.IsNotExist(err) {
+		t.Error("Expected the hook gitego created to be deleted.")
+	}
+}
@@ -2,10 +2,10 @@
 package cmd
 
 import (
-	"fmt"
 	"runtime"
 
 	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
 	"github.com/bgreenwell/gitego/utils"
 	"github.com/spf13/cobra"
 )
@@ -19,55 +19,58 @@ type useRunner struct {
 	setGitCredential func(string, string) error
 	getOS            func() string
 	getToken         func(string) (string, error)
+	loadPolicy       func(*config.Config) (*config.Policy, error)
 }
 
 // run is the core logic for the use command.
-func (u *useRunner) run(cmd *cobra.Command, args []string) {
+func (u *useRunner) run(cmd *cobra.Command, args []string) error {
 	profileName := args[0]
 
 	cfg, err := u.load()
 	if err != nil {
-		fmt.Printf("Error loading config: %v\n", err)
-
-		return
+		return errLoadConfig(err)
 	}
 
 	profile, exists := cfg.Profiles[profileName]
 	if !exists {
-		fmt.Printf("Error: Profile '%s' not found.\n", profileName)
+		return errProfileNotFound(profileName)
+	}
+
+	if err := enforcePolicy(u.loadPolicy, cfg, func(p *config.Policy) []config.PolicyViolation {
+		token, err := u.getToken(profileName)
 
-		return
+		return p.CheckGlobalProfile(profile, err == nil && token != "")
+	}); err != nil {
+		return err
 	}
 
 	// Action 1: Set the global git config for user name and email.
 	if err := u.setGlobalGit("user.name", profile.Name); err != nil {
-		fmt.Printf("Error setting git user.name: %v\n", err)
-
-		return
+		return errors.IO(err, "could not set git user.name")
 	}
 
 	if err := u.setGlobalGit("user.email", profile.Email); err != nil {
-		fmt.Printf("Error setting git user.email: %v\n", err)
-
-		return
+		return errors.IO(err, "could not set git user.email")
 	}
 
-	if profile.SigningKey != "" {
-		if err := u.setGlobalGit("user.signingkey", profile.SigningKey); err != nil {
-			fmt.Printf("Error setting git user.signingkey: %v\n", err)
+	for _, setting := range config.SigningSettings(profile) {
+		if setting.Value == "" {
+			if u.unsetGlobalGit != nil {
+				_ = u.unsetGlobalGit(setting.Key)
+			}
 
-			return
+			continue
+		}
+
+		if err := u.setGlobalGit(setting.Key, setting.Value); err != nil {
+			return errors.IO(err, "could not set git %s", setting.Key)
 		}
-	} else if u.unsetGlobalGit != nil {
-		_ = u.unsetGlobalGit("user.signingkey")
 	}
 
 	if profile.SSHKey != "" {
-		sshCommand := fmt.Sprintf("ssh -i %s", profile.SSHKey)
+		sshCommand := config.SSHCommand(profileName)
 		if err := u.setGlobalGit("core.sshCommand", sshCommand); err != nil {
-			fmt.Printf("Error setting git core.sshCommand: %v\n", err)
-
-			return
+			return errors.IO(err, "could not set git core.sshCommand")
 		}
 	} else if u.unsetGlobalGit != nil {
 		_ = u.unsetGlobalGit("core.sshCommand")
@@ -76,9 +79,7 @@ func (u *useRunner) run(cmd *cobra.Command, args []string) {
 	// Action 2: Set this profile as the active one in gitego's config.
 	cfg.ActiveProfile = profileName
 	if err := u.save(cfg); err != nil {
-		fmt.Printf("Error saving active profile setting: %v\n", err)
-
-		return
+		return errSaveConfig(err)
 	}
 
 	// Action 3: If on macOS, also preemptively set the credential
@@ -90,7 +91,9 @@ func (u *useRunner) run(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	fmt.Printf("✓ Set active profile to '%s'.\n", profileName)
+	printSuccess("✓ Set active profile to '%s'.\n", profileName)
+
+	return nil
 }
 
 var useCmd = &cobra.Command{
@@ -101,7 +104,7 @@ for any repository that does not have a specific auto-switch rule.
 This command updates your global .gitconfig, sets the active profile for the
 credential helper, and preemptively updates the macOS Keychain.`,
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		runner := &useRunner{
 			load:             config.Load,
 			save:             func(c *config.Config) error { return c.Save() },
@@ -110,8 +113,9 @@ credential helper, and preemptively updates the macOS Keychain.`,
 			setGitCredential: config.SetGitCredential,
 			getOS:            func() string { return runtime.GOOS },
 			getToken:         config.GetToken,
+			loadPolicy:       config.LoadPolicy,
 		}
-		runner.run(cmd, args)
+		return runner.run(cmd, args)
 	},
 }
 
@@ -103,3 +103,47 @@ func TestUseCommand_macOS(t *testing.T) {
 		t.Error("Expected SetGitCredential to be called with correct username and token on macOS")
 	}
 }
+
+func TestUseCommand_SSHSigning(t *testing.T) {
+	mockCfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"work": {
+				Name:          "Work User",
+				Email:         "work@example.com",
+				SigningKey:    "~/.ssh/id_work.pub",
+				SigningFormat: config.SigningSSH,
+			},
+		},
+	}
+
+	gitConfigCalls := make(map[string]string)
+
+	runner := &useRunner{
+		load: func() (*config.Config, error) { return mockCfg, nil },
+		save: func(c *config.Config) error { return nil },
+		setGlobalGit: func(key, value string) error {
+			gitConfigCalls[key] = value
+
+			return nil
+		},
+		getOS:    func() string { return "linux" },
+		getToken: func(pn string) (string, error) { return "", fmt.Errorf("not found") },
+	}
+
+	runner.run(useCmd, []string{"work"})
+
+	expected := map[string]string{
+		"user.signingkey": "~/.ssh/id_work.pub",
+		"gpg.format":      "ssh",
+		"commit.gpgsign":  "true",
+	}
+	for key, value := range expected {
+		if gitConfigCalls[key] != value {
+			t.Errorf("Expected %s to be set to '%s', got '%s'", key, value, gitConfigCalls[key])
+		}
+	}
+
+	if gitConfigCalls["gpg.ssh.allowedSignersFile"] == "" {
+		t.Error("Expected gpg.ssh.allowedSignersFile to be set for SSH signing.")
+	}
+}
@@ -0,0 +1,54 @@
+// cmd/validate.go
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+)
+
+// checkProfile validates a profile about to be saved and returns an error listing its
+// problems if it may not be saved. With force, invalid fields are only warned about; an
+// invalid profile name is always an error.
+func checkProfile(name string, profile *config.Profile, force bool) error {
+	var problems config.ValidationErrors
+
+	forceable := true
+
+	for _, err := range config.ValidateProfile(name, profile) {
+		if force && err.Forceable() {
+			fmt.Printf("Warning: %v.\n", err)
+
+			continue
+		}
+
+		problems = append(problems, err)
+		forceable = forceable && err.Forceable()
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	err := errors.InvalidInput("%v", problems[0])
+	if len(problems) > 1 {
+		var message strings.Builder
+
+		fmt.Fprintf(&message, "profile '%s' failed validation:", name)
+
+		for _, problem := range problems {
+			fmt.Fprintf(&message, "\n  - %v", problem)
+		}
+
+		err = errors.InvalidInput("%s", message.String())
+	}
+
+	if forceable {
+		err.WithHint("Fix the values, or use --force to save the profile anyway.")
+	}
+
+	return err
+}
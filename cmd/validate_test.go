@@ -0,0 +1,78 @@
+// cmd/validate_test.go
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/spf13/cobra"
+)
+
+func TestAddCommand_Validation(t *testing.T) {
+	mockCfg := &config.Config{Profiles: make(map[string]*config.Profile)}
+
+	a := &adder{
+		load:     func() (*config.Config, error) { return mockCfg, nil },
+		save:     func(c *config.Config) error { return nil },
+		setToken: func(string, string) error { return nil },
+	}
+
+	addName, addEmail, addPAT, addSSHKey = "Jane Doe", "jane@corp.com", "", ""
+
+	defer func() { addName, addEmail, addSSHKey, addForce = "", "", "", false }()
+
+	err := a.run(addCmd, []string{"../x"})
+	if len(mockCfg.Profiles) != 0 || err == nil || !strings.Contains(err.Error(), "'../x' is not a valid profile name") {
+		t.Errorf("Expected the profile name to be rejected, got: %v", err)
+	}
+
+	addEmail, addSSHKey = "jane at corp", "/nonexistent/id_work"
+
+	err = a.run(addCmd, []string{"work"})
+	if len(mockCfg.Profiles) != 0 || errors.KindOf(err) != errors.KindInvalidInput ||
+		!strings.Contains(err.Error(), "email 'jane at corp' is not a valid email address") ||
+		!strings.Contains(err.Error(), "ssh_key '/nonexistent/id_work' does not exist") ||
+		!strings.Contains(errors.HintOf(err), "use --force") {
+		t.Errorf("Expected the email and SSH key to be rejected, got: %v", err)
+	}
+
+	addForce = true
+
+	if err := a.run(addCmd, []string{"../x"}); len(mockCfg.Profiles) != 0 || err == nil {
+		t.Errorf("Expected --force not to allow an invalid profile name, got: %v", err)
+	}
+
+	output := captureOutput(t, "", func() { err = a.run(addCmd, []string{"work"}) })
+	if _, ok := mockCfg.Profiles["work"]; !ok || err != nil || !strings.Contains(output, "Warning: profile 'work': email") {
+		t.Errorf("Expected --force to save the profile with warnings, got %v and output: %s", err, output)
+	}
+}
+
+func TestEditCommand_Validation(t *testing.T) {
+	mockCfg := &config.Config{Profiles: map[string]*config.Profile{
+		"work": {Name: "Jane Doe", Email: "jane@corp.com"},
+	}}
+
+	saved := false
+	e := &editor{
+		load: func() (*config.Config, error) { return mockCfg, nil },
+		save: func(c *config.Config) error { saved = true; return nil },
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringVar(&editUsername, "username", "", "")
+
+	defer func() { editUsername = "" }()
+
+	if err := cmd.Flags().Set("username", "jane doe"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := e.run(cmd, []string{"work"})
+	if saved || err == nil || !strings.Contains(err.Error(), "username 'jane doe' is not a valid username") {
+		t.Errorf("Expected the invalid username not to be saved, got: %v", err)
+	}
+}
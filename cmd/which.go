@@ -0,0 +1,220 @@
+// cmd/which.go
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/errors"
+	"github.com/bgreenwell/gitego/utils"
+	"github.com/spf13/cobra"
+)
+
+// whichRunner holds the dependencies for the which command for mocking.
+type whichRunner struct {
+	load       func() (*config.Config, error)
+	getOrigins func(string) ([]utils.GitConfigEntry, error)
+}
+
+// run is the core logic for the which command.
+func (r *whichRunner) run(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	dir := "."
+	if len(args) > 0 {
+		dir = config.ExpandHome(args[0])
+	}
+
+	cfg, err := r.load()
+	if err != nil {
+		return errLoadConfig(err)
+	}
+
+	absDir, matches, err := cfg.ExplainRules(dir)
+	if err != nil {
+		return errors.IO(err, "could not resolve %s", dir)
+	}
+
+	_, _ = fmt.Fprintf(out, "Path: %s\n\n", absDir)
+
+	printRuleMatches(out, matches)
+
+	profileName, source := cfg.GetProfileForDir(dir)
+	profile := cfg.Profiles[profileName]
+
+	_, _ = fmt.Fprintln(out, "\ngitego resolves:")
+
+	if profile == nil {
+		_, _ = fmt.Fprintf(out, "  %s\n", source)
+	} else {
+		_, _ = fmt.Fprintf(out, "  Profile '%s': %s <%s>\n  Source: %s\n", profileName, profile.Name, profile.Email, source)
+	}
+
+	entries, err := r.getOrigins(dir)
+	if err != nil {
+		return errors.IO(err, "could not read git configuration for %s", dir)
+	}
+
+	_, _ = fmt.Fprintln(out, "\nGit resolves:")
+	printGitOrigins(out, entries)
+
+	if profile == nil {
+		return nil
+	}
+
+	disagreements := gitDisagreements(entries, profileName, profile)
+	if len(disagreements) == 0 {
+		_, _ = fmt.Fprintln(out, "\n✓ Git and gitego agree.")
+
+		return nil
+	}
+
+	_, _ = fmt.Fprintln(out)
+
+	for _, disagreement := range disagreements {
+		_, _ = fmt.Fprintf(out, "✗ %s\n", disagreement)
+	}
+
+	return nil
+}
+
+// printRuleMatches lists every auto rule and why it did or didn't apply.
+func printRuleMatches(out io.Writer, matches []config.RuleMatch) {
+	_, _ = fmt.Fprintln(out, "Rules (the longest matching path wins):")
+
+	if len(matches) == 0 {
+		_, _ = fmt.Fprintln(out, "  No auto rules are configured.")
+
+		return
+	}
+
+	var selected *config.RuleMatch
+
+	matched := 0
+
+	for i := range matches {
+		if matches[i].Matched {
+			matched++
+		}
+
+		if matches[i].Selected {
+			selected = &matches[i]
+		}
+	}
+
+	for _, match := range matches {
+		marker := "✗"
+
+		var reason string
+
+		switch {
+		case match.Err != nil:
+			marker = "!"
+			reason = fmt.Sprintf("skipped: could not normalize path: %v", match.Err)
+		case match.Selected:
+			marker = "✓"
+			reason = fmt.Sprintf("selected: longest of %d matching rule(s)", matched)
+		case match.Matched:
+			marker = "-"
+			reason = fmt.Sprintf("matches, but %s is longer", selected.Path)
+		default:
+			reason = "does not contain this path"
+		}
+
+		if match.Err == nil && !match.Exists {
+			reason += " (rule path does not exist; compared as written)"
+		}
+
+		_, _ = fmt.Fprintf(out, "  %s %s -> %s: %s\n", marker, match.Path, match.Rule.Profile, reason)
+		if strings.TrimSuffix(match.Path, "/") != strings.TrimSuffix(filepath.ToSlash(match.Rule.Path), "/") {
+			_, _ = fmt.Fprintf(out, "      configured as %s\n", match.Rule.Path)
+		}
+	}
+}
+
+// printGitOrigins writes git's user.* values with their scope and origin.
+func printGitOrigins(out io.Writer, entries []utils.GitConfigEntry) {
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(out, "  No user.* values are set.")
+
+		return
+	}
+
+	// Git reads files in order, so a later value of the same key overrides earlier ones.
+	last := make(map[string]int)
+	for i, entry := range entries {
+		last[entry.Key] = i
+	}
+
+	w := tabwriter.NewWriter(out, minwidth, tabwidth, padding, padchar, flags)
+
+	for i, entry := range entries {
+		line := fmt.Sprintf("  %s\t%s\t%s\t%s", entry.Key, entry.Value, entry.Scope, entry.Origin)
+		if last[entry.Key] != i {
+			line += "\t(overridden)"
+		}
+
+		_, _ = fmt.Fprintln(w, line)
+	}
+
+	_ = w.Flush()
+}
+
+// gitDisagreements describes where the values git uses differ from the profile gitego expects.
+func gitDisagreements(entries []utils.GitConfigEntry, profileName string, profile *config.Profile) []string {
+	effective := make(map[string]utils.GitConfigEntry)
+	for _, entry := range entries {
+		effective[entry.Key] = entry
+	}
+
+	var disagreements []string
+
+	check := func(key, expected string, equal func(string, string) bool) {
+		entry, set := effective[key]
+
+		switch {
+		case !set && expected != "":
+			disagreements = append(disagreements, fmt.Sprintf("Git has no %s, but profile '%s' expects '%s'.",
+				key, profileName, expected))
+		case set && !equal(entry.Value, expected):
+			disagreements = append(disagreements, fmt.Sprintf("Git uses %s '%s' from %s %s, but profile '%s' expects '%s'.",
+				key, entry.Value, entry.Scope, entry.Origin, profileName, expected))
+		}
+	}
+
+	check("user.email", profile.Email, strings.EqualFold)
+	check("user.name", profile.Name, func(a, b string) bool { return a == b })
+
+	return disagreements
+}
+
+// whichCmd represents the which command.
+var whichCmd = &cobra.Command{
+	Use:   "which [path]",
+	Short: "Explains which profile applies to a path and why.",
+	Long: `Evaluates a path (the current directory by default) against every gitego
+auto rule, showing the normalized paths compared, which rules matched, and why
+the longest matching path won. Rules whose paths don't exist or can't be
+normalized are called out rather than silently ignored.
+
+It then shows the user.* values Git itself resolves for the path, with the scope
+and file each comes from, and flags any disagreement with the profile gitego
+expects, such as a repository-local override.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := &whichRunner{
+			load:       config.Load,
+			getOrigins: utils.GetGitConfigOrigins,
+		}
+		return runner.run(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whichCmd)
+}
@@ -0,0 +1,63 @@
+// cmd/which_test.go
+
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bgreenwell/gitego/config"
+	"github.com/bgreenwell/gitego/utils"
+	"github.com/spf13/cobra"
+)
+
+func TestWhichCommand(t *testing.T) {
+	root := t.TempDir()
+	workDir := filepath.Join(root, "work")
+
+	mockCfg := &config.Config{
+		Profiles: map[string]*config.Profile{
+			"work":     {Name: "Work User", Email: "work@corp.com"},
+			"personal": {Name: "Me", Email: "me@gmail.com"},
+		},
+		AutoRules: []*config.AutoRule{
+			{Path: root, Profile: "personal"},
+			{Path: workDir, Profile: "work"},
+		},
+	}
+
+	runner := &whichRunner{
+		load: func() (*config.Config, error) { return mockCfg, nil },
+		getOrigins: func(string) ([]utils.GitConfigEntry, error) {
+			return []utils.GitConfigEntry{
+				{Scope: "global", Origin: "file:/home/me/.gitego/profiles/work.gitconfig", Key: "user.name", Value: "Work User"},
+				{Scope: "global", Origin: "file:/home/me/.gitego/profiles/work.gitconfig", Key: "user.email", Value: "work@corp.com"},
+				{Scope: "local", Origin: "file:.git/config", Key: "user.email", Value: "me@gmail.com"},
+			}, nil
+		},
+	}
+
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	runner.run(cmd, []string{filepath.Join(workDir, "api")})
+
+	output := out.String()
+
+	expected := []string{
+		"-> personal: matches, but",
+		"-> work: selected: longest of 2 matching rule(s)",
+		"Profile 'work': Work User <work@corp.com>",
+		"(overridden)",
+		"✗ Git uses user.email 'me@gmail.com' from local file:.git/config, but profile 'work' expects 'work@corp.com'.",
+	}
+
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
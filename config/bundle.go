@@ -0,0 +1,281 @@
+// config/bundle.go
+
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// BundleVersion is the format version written by NewBundle.
+	BundleVersion = 1
+
+	// bundleKDF names the key derivation used for bundle tokens.
+	bundleKDF = "pbkdf2-sha256"
+	// bundleKDFIterations follows current OWASP guidance for PBKDF2-HMAC-SHA256.
+	bundleKDFIterations = 600000
+	// bundleKeyLength selects AES-256.
+	bundleKeyLength = 32
+	// bundleSaltLength is the size of the random salt in bytes.
+	bundleSaltLength = 16
+	// bundleFilePermissions keeps bundles private since they may hold encrypted tokens.
+	bundleFilePermissions = 0600
+)
+
+// ErrBadPassphrase is returned when bundle tokens cannot be decrypted.
+var ErrBadPassphrase = errors.New("incorrect passphrase or corrupted bundle")
+
+// Bundle is a portable snapshot of a gitego configuration. Paths under the
+// home directory are stored as ~/ so the bundle works on another machine.
+type Bundle struct {
+	Version       int                 `yaml:"version"`
+	Profiles      map[string]*Profile `yaml:"profiles"`
+	AutoRules     []*AutoRule         `yaml:"auto_rules,omitempty"`
+	ActiveProfile string              `yaml:"active_profile,omitempty"`
+	Tokens        *EncryptedTokens    `yaml:"tokens,omitempty"`
+}
+
+// EncryptedTokens holds profile PATs encrypted with a key derived from a passphrase.
+type EncryptedTokens struct {
+	KDF        string            `yaml:"kdf"`
+	Iterations int               `yaml:"iterations"`
+	Salt       string            `yaml:"salt"`
+	Entries    map[string]string `yaml:"entries"`
+}
+
+// NewBundle builds a bundle from a config, rewriting home paths to ~/. Profiles that
+// extend another keep only their own values, as in config.yaml.
+func NewBundle(cfg *Config) *Bundle {
+	bundle := &Bundle{
+		Version:       BundleVersion,
+		Profiles:      make(map[string]*Profile, len(cfg.Profiles)),
+		ActiveProfile: cfg.ActiveProfile,
+	}
+
+	for name, profile := range cfg.rawProfiles() {
+		p := *profile
+		p.SSHKey = HomeRelative(p.SSHKey)
+		p.SSHIdentityAgent = HomeRelative(p.SSHIdentityAgent)
+
+		if p.SigningMode() == SigningSSH {
+			p.SigningKey = HomeRelative(p.SigningKey)
+		}
+
+		bundle.Profiles[name] = &p
+	}
+
+	for _, rule := range cfg.AutoRules {
+		copied := *rule
+		copied.Path = HomeRelative(rule.Path)
+		bundle.AutoRules = append(bundle.AutoRules, &copied)
+	}
+
+	return bundle
+}
+
+// WriteBundle serializes a bundle to a file readable only by its owner.
+func WriteBundle(path string, bundle *Bundle) error {
+	data, err := yaml.Marshal(bundle)
+	if err != nil {
+		return fmt.Errorf("could not serialize bundle: %w", err)
+	}
+
+	header := "# gitego configuration bundle. Import it with 'gitego import-bundle'.\n"
+
+	return os.WriteFile(path, append([]byte(header), data...), bundleFilePermissions)
+}
+
+// ReadBundle reads and decodes a bundle file.
+func ReadBundle(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read bundle: %w", err)
+	}
+
+	bundle := &Bundle{}
+	if err := yaml.Unmarshal(data, bundle); err != nil {
+		return nil, fmt.Errorf("could not parse bundle: %w", err)
+	}
+
+	if bundle.Version > BundleVersion {
+		return nil, fmt.Errorf("bundle version %d is newer than this gitego supports (%d)", bundle.Version, BundleVersion)
+	}
+
+	if bundle.Profiles == nil {
+		bundle.Profiles = make(map[string]*Profile)
+	}
+
+	return bundle, nil
+}
+
+// HomeRelative rewrites an absolute path inside the home directory to start with ~/.
+// Other values are returned unchanged.
+func HomeRelative(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || path == "" {
+		return path
+	}
+
+	slashHome := strings.TrimSuffix(filepath.ToSlash(home), "/") + "/"
+	if rest, found := strings.CutPrefix(filepath.ToSlash(path), slashHome); found {
+		return "~/" + rest
+	}
+
+	return path
+}
+
+// BundleSecretKey returns the key of a profile's env secret among the bundle's encrypted
+// entries. A profile's token is keyed by the bare profile name.
+func BundleSecretKey(profileName, name string) string {
+	return secretAccount(profileName, name)
+}
+
+// ParseBundleSecretKey returns the profile and secret name of an encrypted entry key made
+// by BundleSecretKey. It reports false for the key of a profile's token.
+func ParseBundleSecretKey(key string) (string, string, bool) {
+	profileName, name, found := strings.Cut(key, secretAccount("", ""))
+
+	return profileName, name, found && profileName != "" && name != ""
+}
+
+// EncryptTokens stores the given tokens and env secrets in the bundle, encrypted with
+// AES-GCM under a key derived from the passphrase. Tokens are keyed by profile name and
+// secrets by BundleSecretKey.
+func (b *Bundle) EncryptTokens(tokens map[string]string, passphrase string) error {
+	salt := make([]byte, bundleSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("could not generate salt: %w", err)
+	}
+
+	aead, err := bundleCipher(passphrase, salt, bundleKDFIterations)
+	if err != nil {
+		return err
+	}
+
+	encrypted := &EncryptedTokens{
+		KDF:        bundleKDF,
+		Iterations: bundleKDFIterations,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Entries:    make(map[string]string, len(tokens)),
+	}
+
+	for name, token := range tokens {
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return fmt.Errorf("could not generate nonce: %w", err)
+		}
+
+		// The entry key is bound as additional data so entries can't be swapped.
+		sealed := aead.Seal(nonce, nonce, []byte(token), []byte(name))
+		encrypted.Entries[name] = base64.StdEncoding.EncodeToString(sealed)
+	}
+
+	b.Tokens = encrypted
+
+	return nil
+}
+
+// DecryptTokens returns the bundle's tokens keyed by profile name, and its env secrets
+// keyed by BundleSecretKey.
+func (b *Bundle) DecryptTokens(passphrase string) (map[string]string, error) {
+	if b.Tokens == nil {
+		return nil, nil
+	}
+
+	if b.Tokens.KDF != bundleKDF {
+		return nil, fmt.Errorf("unsupported key derivation '%s'", b.Tokens.KDF)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(b.Tokens.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt: %w", err)
+	}
+
+	aead, err := bundleCipher(passphrase, salt, b.Tokens.Iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make(map[string]string, len(b.Tokens.Entries))
+
+	for name, entry := range b.Tokens.Entries {
+		sealed, err := base64.StdEncoding.DecodeString(entry)
+		if err != nil || len(sealed) < aead.NonceSize() {
+			return nil, ErrBadPassphrase
+		}
+
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+		token, err := aead.Open(nil, nonce, ciphertext, []byte(name))
+		if err != nil {
+			return nil, ErrBadPassphrase
+		}
+
+		tokens[name] = string(token)
+	}
+
+	return tokens, nil
+}
+
+func bundleCipher(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, bundleKeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("could not derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// RenameProfile renames a profile inside the bundle along with its rules and the profiles
+// that extend it. Tokens stay under the name they were encrypted with, so decrypt them
+// before renaming.
+func (b *Bundle) RenameProfile(oldName, newName string) {
+	b.Profiles[newName] = b.Profiles[oldName]
+	delete(b.Profiles, oldName)
+
+	for _, profile := range b.Profiles {
+		if profile.Extends == oldName {
+			profile.Extends = newName
+		}
+	}
+
+	for _, rule := range b.AutoRules {
+		if rule.Profile == oldName {
+			rule.Profile = newName
+		}
+	}
+
+	if b.ActiveProfile == oldName {
+		b.ActiveProfile = newName
+	}
+}
+
+// LocalProfile returns a copy of a bundle profile with ~/ paths expanded for this machine.
+func (b *Bundle) LocalProfile(name string) *Profile {
+	p := *b.Profiles[name]
+	p.SSHKey = ExpandHome(p.SSHKey)
+	p.SSHIdentityAgent = ExpandHome(p.SSHIdentityAgent)
+
+	if p.SigningMode() == SigningSSH {
+		p.SigningKey = ExpandHome(p.SigningKey)
+	}
+
+	return &p
+}
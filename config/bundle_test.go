@@ -0,0 +1,125 @@
+// config/bundle_test.go
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestBundleRoundTrip verifies that home paths are made portable on export, expanded on
+// import, and that the bundle survives being written and read back.
+func TestBundleRoundTrip(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory available")
+	}
+
+	keyPath := filepath.Join(home, ".ssh", "id_work")
+	cfg := &Config{
+		Profiles: map[string]*Profile{
+			"work": {Name: "Work User", Email: "work@corp.com", SSHKey: keyPath, SSHPort: 2222},
+		},
+		AutoRules: []*AutoRule{
+			{Path: filepath.ToSlash(filepath.Join(home, "work")) + "/", Profile: "work", Signoff: SignoffRequire},
+		},
+		ActiveProfile: "work",
+	}
+
+	bundle := NewBundle(cfg)
+
+	if got := bundle.Profiles["work"].SSHKey; got != "~/.ssh/id_work" {
+		t.Errorf("Expected SSH key to be stored as '~/.ssh/id_work', got '%s'", got)
+	}
+
+	if cfg.Profiles["work"].SSHKey != keyPath {
+		t.Error("Expected NewBundle not to modify the source config.")
+	}
+
+	if got := bundle.AutoRules[0].Path; got != "~/work/" {
+		t.Errorf("Expected rule path '~/work/', got '%s'", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "bundle.yaml")
+	if err := WriteBundle(path, bundle); err != nil {
+		t.Fatalf("WriteBundle failed: %v", err)
+	}
+
+	loaded, err := ReadBundle(path)
+	if err != nil {
+		t.Fatalf("ReadBundle failed: %v", err)
+	}
+
+	local := loaded.LocalProfile("work")
+	if local.SSHKey != keyPath || local.SSHPort != 2222 || local.Email != "work@corp.com" {
+		t.Errorf("Expected the profile to round-trip with an expanded key path, got %+v", local)
+	}
+
+	if rule := loaded.AutoRules[0]; rule.Signoff != SignoffRequire {
+		t.Errorf("Expected the rule's signoff mode to round-trip, got %+v", rule)
+	}
+
+	if loaded.ActiveProfile != "work" || loaded.Version != BundleVersion {
+		t.Errorf("Expected active profile and version to round-trip, got %+v", loaded)
+	}
+}
+
+// TestBundleTokens verifies token encryption, decryption and rejection of a wrong passphrase.
+func TestBundleTokens(t *testing.T) {
+	bundle := &Bundle{Profiles: map[string]*Profile{"work": {}}}
+
+	if err := bundle.EncryptTokens(map[string]string{"work": "ghp_secret"}, "correct horse"); err != nil {
+		t.Fatalf("EncryptTokens failed: %v", err)
+	}
+
+	if bundle.Tokens.Entries["work"] == "ghp_secret" {
+		t.Fatal("Expected the token to be encrypted.")
+	}
+
+	tokens, err := bundle.DecryptTokens("correct horse")
+	if err != nil || tokens["work"] != "ghp_secret" {
+		t.Errorf("Expected to decrypt 'ghp_secret', got %v (err: %v)", tokens, err)
+	}
+
+	if _, err := bundle.DecryptTokens("wrong"); !errors.Is(err, ErrBadPassphrase) {
+		t.Errorf("Expected ErrBadPassphrase for a wrong passphrase, got %v", err)
+	}
+
+	if profileName, name, ok := ParseBundleSecretKey(BundleSecretKey("work", "NPM_TOKEN")); !ok ||
+		profileName != "work" || name != "NPM_TOKEN" {
+		t.Errorf("Expected the secret key to parse back, got %q, %q, %v", profileName, name, ok)
+	}
+
+	if _, _, ok := ParseBundleSecretKey("work"); ok {
+		t.Error("Expected a token key not to parse as a secret key.")
+	}
+}
+
+// TestBundleRenameProfile verifies that renaming moves the profile's rules, active status
+// and the profiles that extend it.
+func TestBundleRenameProfile(t *testing.T) {
+	bundle := &Bundle{
+		Profiles: map[string]*Profile{
+			"work": {Email: "work@corp.com"},
+			"acme": {Extends: "work", Email: "jane@acme.com"},
+		},
+		AutoRules:     []*AutoRule{{Path: "~/work/", Profile: "work"}},
+		ActiveProfile: "work",
+	}
+
+	bundle.RenameProfile("work", "work-2")
+
+	if _, exists := bundle.Profiles["work"]; exists {
+		t.Error("Expected the old profile name to be removed.")
+	}
+
+	if bundle.Profiles["work-2"] == nil || bundle.AutoRules[0].Profile != "work-2" || bundle.ActiveProfile != "work-2" {
+		t.Errorf("Expected the profile, rule and active profile to use 'work-2', got %+v", bundle)
+	}
+
+	if got := bundle.Profiles["acme"].Extends; got != "work-2" {
+		t.Errorf("Expected 'acme' to extend 'work-2', got '%s'", got)
+	}
+}
@@ -5,9 +5,11 @@ package config
 import (
 	"bufio"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -15,18 +17,33 @@ import (
 
 // Profile represents a single user profile with a name and email.
 type Profile struct {
-	Name       string `yaml:"name"`
-	Email      string `yaml:"email"`
-	Username   string `yaml:"username,omitempty"`
-	SSHKey     string `yaml:"ssh_key,omitempty"`
-	SigningKey string `yaml:"signing_key,omitempty"`
-	PAT        string `yaml:"-"`
+	// Extends names a base profile whose values fill in the fields this profile leaves empty.
+	Extends          string            `yaml:"extends,omitempty"`
+	Name             string            `yaml:"name,omitempty"`
+	Email            string            `yaml:"email,omitempty"`
+	Username         string            `yaml:"username,omitempty"`
+	SSHKey           string            `yaml:"ssh_key,omitempty"`
+	SSHUser          string            `yaml:"ssh_user,omitempty"`
+	SSHPort          int               `yaml:"ssh_port,omitempty"`
+	SSHIdentityAgent string            `yaml:"ssh_identity_agent,omitempty"`
+	SigningKey       string            `yaml:"signing_key,omitempty"`
+	SigningFormat    string            `yaml:"signing_format,omitempty"`
+	Env              map[string]string `yaml:"env,omitempty"`
+	Signoff          string            `yaml:"signoff,omitempty"`
+	PAT              string            `yaml:"-"`
+
+	// inherited maps the yaml name of each field taken from a base profile to the value
+	// it inherited, so that Save can leave it out again.
+	inherited map[string]any
+	// envSources maps each inherited env entry to the base profile it comes from.
+	envSources map[string]string
 }
 
 // AutoRule represents a single directory-to-profile mapping.
 type AutoRule struct {
 	Path    string `yaml:"path"`
 	Profile string `yaml:"profile"`
+	Signoff string `yaml:"signoff,omitempty"`
 }
 
 // Config represents the entire structure of our config file.
@@ -34,6 +51,12 @@ type Config struct {
 	Profiles      map[string]*Profile `yaml:"profiles"`
 	AutoRules     []*AutoRule         `yaml:"auto_rules,omitempty"`
 	ActiveProfile string              `yaml:"active_profile,omitempty"`
+	PolicyPath    string              `yaml:"policy_path,omitempty"`
+	// Pair lists the profiles of teammates credited as co-authors until 'gitego pair --end'.
+	Pair []string `yaml:"pair,omitempty"`
+	// PreviousCredentialHelpers records the credential.helper values that were set before
+	// 'gitego init' changed them, so 'gitego uninstall' can restore them.
+	PreviousCredentialHelpers []string `yaml:"previous_credential_helpers,omitempty"`
 }
 
 const (
@@ -44,9 +67,14 @@ const (
 )
 
 var (
-	gitegoConfigPath string
-	gitConfigPath    string
-	profilesDir      string
+	gitegoConfigPath   string
+	gitConfigPath      string
+	profilesDir        string
+	sshConfigPath      string
+	keysDir            string
+	allowedSignersPath string
+	systemPolicyPath   string
+	trustedReposPath   string
 )
 
 func init() {
@@ -57,7 +85,12 @@ func init() {
 
 	gitegoConfigPath = filepath.Join(home, ".gitego", "config.yaml")
 	profilesDir = filepath.Join(home, ".gitego", "profiles")
+	sshConfigPath = filepath.Join(home, ".gitego", "ssh_config")
+	keysDir = filepath.Join(home, ".gitego", "keys")
+	allowedSignersPath = filepath.Join(home, ".gitego", "allowed_signers")
+	trustedReposPath = filepath.Join(home, ".gitego", "trusted_repos.yaml")
 	gitConfigPath = filepath.Join(home, ".gitconfig")
+	systemPolicyPath = defaultSystemPolicyPath()
 }
 
 // Load reads and decodes the gitego config.yaml file and validates it.
@@ -79,6 +112,13 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("could not parse config file: %w", err)
 	}
 
+	for name, profile := range cfg.Profiles {
+		if profile == nil {
+			cfg.Profiles[name] = &Profile{}
+		}
+	}
+
+	cfg.ResolveProfiles()
 	validateConfig(cfg)
 
 	return cfg, nil
@@ -97,11 +137,39 @@ func validateConfig(cfg *Config) {
 				"Warning: Auto-switch rule for path '%s' points to a non-existent profile '%s'.\n",
 				rule.Path, rule.Profile)
 		}
+
+		if rule.Signoff != "" && !IsValidSignoffMode(rule.Signoff) {
+			fmt.Fprintf(os.Stderr, "Warning: Auto-switch rule for path '%s' has an unknown signoff mode '%s'.\n",
+				rule.Path, rule.Signoff)
+		}
+	}
+
+	// Only the names are checked here; 'gitego doctor' validates the profiles' fields.
+	for _, name := range slices.Sorted(maps.Keys(cfg.Profiles)) {
+		if err := ValidateProfileName(name); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v.\n", err)
+		}
+
+		if _, err := cfg.ExtendsChain(name); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v.\n", err)
+		}
+	}
+
+	for _, name := range cfg.Pair {
+		if _, exists := cfg.Profiles[name]; !exists {
+			fmt.Fprintf(os.Stderr, "Warning: Pairing profile '%s' not found. It may have been deleted.\n", name)
+		}
 	}
 }
 
+// Save writes the config to disk and regenerates the files derived from it:
+// the managed ssh_config and the allowed_signers file.
 func (c *Config) Save() error {
-	data, err := yaml.Marshal(c)
+	// Inherited values are written as empty fields and resolved again on load.
+	raw := *c
+	raw.Profiles = c.rawProfiles()
+
+	data, err := yaml.Marshal(&raw)
 	if err != nil {
 		return fmt.Errorf("could not serialize config to yaml: %w", err)
 	}
@@ -114,37 +182,80 @@ func (c *Config) Save() error {
 		return fmt.Errorf("could not write config file: %w", err)
 	}
 
+	// Bring profiles that extend an edited base up to date for the files derived below.
+	c.ResolveProfiles()
+
+	if err := WriteSSHConfig(c); err != nil {
+		return fmt.Errorf("could not write ssh config: %w", err)
+	}
+
+	if err := WriteAllowedSigners(c); err != nil {
+		return fmt.Errorf("could not write allowed signers file: %w", err)
+	}
+
 	return nil
 }
 
 func (c *Config) GetActiveProfileForCurrentDir() (profileName, source string) {
-	profileName = c.ActiveProfile
-	source = getDefaultSource(c.ActiveProfile)
-
-	if len(c.AutoRules) == 0 {
-		return profileName, source
+	currentAbsDir, err := getCurrentAbsDir()
+	if err != nil {
+		return c.ActiveProfile, getDefaultSource(c.ActiveProfile)
 	}
 
-	currentAbsDir, err := getCurrentAbsDir()
+	return c.profileForAbsDir(currentAbsDir)
+}
+
+// GetProfileForDir is GetActiveProfileForCurrentDir for an arbitrary directory.
+func (c *Config) GetProfileForDir(dir string) (profileName, source string) {
+	absDir, err := cleanPath(dir)
 	if err != nil {
-		return profileName, source
+		return c.ActiveProfile, getDefaultSource(c.ActiveProfile)
 	}
 
+	return c.profileForAbsDir(absDir)
+}
+
+func (c *Config) profileForAbsDir(currentAbsDir string) (profileName, source string) {
+	profileName = c.ActiveProfile
+	source = getDefaultSource(c.ActiveProfile)
+
 	bestMatch := c.findBestMatchingRule(currentAbsDir)
 	if bestMatch != nil {
-		profileName = bestMatch.Profile
-		source = fmt.Sprintf("gitego auto-rule for profile '%s'", bestMatch.Profile)
+		return bestMatch.Profile, fmt.Sprintf("gitego auto-rule for profile '%s'", bestMatch.Profile)
+	}
+
+	// Without a rule of the user's own, a trusted .gitego.yaml may name the profile.
+	if rc, err := FindRepoConfig(currentAbsDir); err == nil && rc != nil && rc.Trusted && rc.Profile != "" {
+		if _, exists := c.Profiles[rc.Profile]; exists {
+			return rc.Profile, fmt.Sprintf("%s for profile '%s'", rc.Path, rc.Profile)
+		}
 	}
 
 	return profileName, source
 }
 
+// MatchingRule returns the most specific auto rule that applies to dir, or nil if none does.
+func (c *Config) MatchingRule(dir string) *AutoRule {
+	absDir, err := cleanPath(dir)
+	if err != nil {
+		return nil
+	}
+
+	return c.findBestMatchingRule(absDir)
+}
+
+// Sources reported when no rule or repository file selects the profile.
+const (
+	NoProfileSource      = "No active gitego profile"
+	DefaultProfileSource = "Global gitego default"
+)
+
 func getDefaultSource(activeProfile string) string {
 	if activeProfile == "" {
-		return "No active gitego profile"
+		return NoProfileSource
 	}
 
-	return "Global gitego default"
+	return DefaultProfileSource
 }
 
 func getCurrentAbsDir() (string, error) {
@@ -192,6 +303,12 @@ func (c *Config) findBestMatchingRule(currentAbsDir string) *AutoRule {
 }
 
 func (c *Config) isPathMatch(currentAbsDir, ruleAbsPath string) bool {
+	return pathHasPrefix(currentAbsDir, ruleAbsPath)
+}
+
+// pathHasPrefix reports whether a normalized directory is inside a normalized rule path,
+// ignoring case on Windows.
+func pathHasPrefix(currentAbsDir, ruleAbsPath string) bool {
 	compareDir := currentAbsDir
 	compareRulePath := ruleAbsPath
 
@@ -223,26 +340,73 @@ func cleanPath(path string) (string, error) {
 	return ruleAbsPath, nil
 }
 
+// ProfileGitconfigPath returns the path of the gitconfig file generated for a profile.
+func ProfileGitconfigPath(profileName string) string {
+	return filepath.Join(profilesDir, fmt.Sprintf("%s.gitconfig", profileName))
+}
+
+// EnsureProfileGitconfig writes the profile-specific gitconfig that includeIf rules point at.
 func EnsureProfileGitconfig(profileName string, profile *Profile) error {
 	if err := os.MkdirAll(profilesDir, dirPermissions); err != nil {
 		return fmt.Errorf("could not create profiles directory: %w", err)
 	}
 
-	content := fmt.Sprintf("[user]\n    name = %s\n    email = %s\n", profile.Name, profile.Email)
-
-	if profile.SigningKey != "" {
-		content += fmt.Sprintf("    signingkey = %s\n", profile.SigningKey)
+	settings := []GitSetting{
+		{Key: "user.name", Value: profile.Name},
+		{Key: "user.email", Value: profile.Email},
 	}
 
+	settings = append(settings, SigningSettings(profile)...)
+
 	if profile.SSHKey != "" {
-		sshCommand := fmt.Sprintf("ssh -i %s", profile.SSHKey)
-		coreBlock := fmt.Sprintf("\n[core]\n    sshCommand = %s\n", sshCommand)
-		content += coreBlock
+		settings = append(settings, GitSetting{Key: "core.sshCommand", Value: SSHCommand(profileName)})
 	}
 
-	filePath := filepath.Join(profilesDir, fmt.Sprintf("%s.gitconfig", profileName))
+	return os.WriteFile(ProfileGitconfigPath(profileName), []byte(renderGitconfig(settings)), filePermissions)
+}
+
+// renderGitconfig formats settings as gitconfig sections, in the order each section
+// first appears. Settings with an empty value are left out.
+func renderGitconfig(settings []GitSetting) string {
+	var sections []string
 
-	return os.WriteFile(filePath, []byte(content), filePermissions)
+	entries := make(map[string][]string)
+
+	for _, setting := range settings {
+		if setting.Value == "" {
+			continue
+		}
+
+		dot := strings.LastIndex(setting.Key, ".")
+		section, name := setting.Key[:dot], setting.Key[dot+1:]
+
+		if first, sub, found := strings.Cut(section, "."); found {
+			section = fmt.Sprintf("%s \"%s\"", first, sub)
+		}
+
+		if _, seen := entries[section]; !seen {
+			sections = append(sections, section)
+		}
+
+		entries[section] = append(entries[section],
+			fmt.Sprintf("    %s = %s\n", name, quoteGitConfigValue(setting.Value)))
+	}
+
+	var b strings.Builder
+
+	for i, section := range sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "[%s]\n", section)
+
+		for _, entry := range entries[section] {
+			b.WriteString(entry)
+		}
+	}
+
+	return b.String()
 }
 
 func AddIncludeIf(profileName string, dirPath string) error {
@@ -323,9 +487,14 @@ func removeGitegoRules(lines []string, profileConfigPath string) []string {
 	for i, line := range lines {
 		trimmedLine := strings.TrimSpace(line)
 
+		// Any section header ends the block being removed, including another includeIf.
+		if isNewSection(trimmedLine) {
+			removing = false
+		}
+
 		if strings.HasPrefix(trimmedLine, "[includeIf") && isGitegoRule(lines, i, profileConfigPath) {
 			removing = true
-			newLines = removeCommentIfPresent(newLines, lines, i)
+			newLines = removeCommentIfPresent(newLines)
 
 			continue
 		}
@@ -333,17 +502,14 @@ func removeGitegoRules(lines []string, profileConfigPath string) []string {
 		if !removing {
 			newLines = append(newLines, line)
 		}
-
-		if removing && isNewSection(trimmedLine) {
-			removing = false
-		}
 	}
 
 	return newLines
 }
 
-func removeCommentIfPresent(newLines []string, lines []string, i int) []string {
-	if i > 0 && strings.TrimSpace(lines[i-1]) == "# gitego auto-switch rule" && len(newLines) > 0 {
+// removeCommentIfPresent drops the comment gitego writes above its includeIf blocks.
+func removeCommentIfPresent(newLines []string) []string {
+	if len(newLines) > 0 && strings.TrimSpace(newLines[len(newLines)-1]) == "# gitego auto-switch rule" {
 		return newLines[:len(newLines)-1]
 	}
 
@@ -351,7 +517,7 @@ func removeCommentIfPresent(newLines []string, lines []string, i int) []string {
 }
 
 func isNewSection(trimmedLine string) bool {
-	return strings.HasPrefix(trimmedLine, "[") && !strings.HasPrefix(trimmedLine, "[includeIf")
+	return strings.HasPrefix(trimmedLine, "[")
 }
 
 func formatOutput(lines []string) string {
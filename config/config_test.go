@@ -353,3 +353,81 @@ func TestEnsureProfileGitconfig_WithoutSigningKey(t *testing.T) {
 		t.Errorf("Expected 'name = Test User' in gitconfig, but got:\n%s", contentStr)
 	}
 }
+
+// TestMatchingRule tests that the most specific rule covering a directory is chosen.
+func TestMatchingRule(t *testing.T) {
+	root := t.TempDir()
+
+	cfg := &Config{
+		AutoRules: []*AutoRule{
+			{Path: filepath.Join(root, "work"), Profile: "work"},
+			{Path: filepath.Join(root, "work", "oss"), Profile: "oss"},
+		},
+	}
+
+	if rule := cfg.MatchingRule(filepath.Join(root, "work", "oss", "lib")); rule == nil || rule.Profile != "oss" {
+		t.Errorf("Expected the 'oss' rule, got %+v", rule)
+	}
+
+	if rule := cfg.MatchingRule(filepath.Join(root, "work", "api")); rule == nil || rule.Profile != "work" {
+		t.Errorf("Expected the 'work' rule, got %+v", rule)
+	}
+
+	if rule := cfg.MatchingRule(filepath.Join(root, "workshop")); rule != nil {
+		t.Errorf("Expected no rule for a sibling directory, got %+v", rule)
+	}
+}
+
+func TestRemoveAllIncludeIfs(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalGitConfigPath := gitConfigPath
+	originalProfilesDir := profilesDir
+	gitConfigPath = filepath.Join(tempDir, ".gitconfig")
+	profilesDir = filepath.Join(tempDir, ".gitego", "profiles")
+
+	defer func() {
+		gitConfigPath = originalGitConfigPath
+		profilesDir = originalProfilesDir
+	}()
+
+	content := `[user]
+	name = Test User
+
+# gitego auto-switch rule
+[includeIf "gitdir:/src/work/"]
+    path = ` + filepath.ToSlash(filepath.Join(profilesDir, "work.gitconfig")) + `
+
+[includeIf "gitdir:/src/other/"]
+    path = /home/me/other.gitconfig
+
+# gitego auto-switch rule
+[includeIf "gitdir:/src/old/"]
+    path = ` + filepath.ToSlash(filepath.Join(profilesDir, "deleted.gitconfig")) + `
+`
+	if err := os.WriteFile(gitConfigPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write .gitconfig: %v", err)
+	}
+
+	headers, err := ListIncludeIfs()
+	if err != nil || len(headers) != 2 || headers[1] != `[includeIf "gitdir:/src/old/"]` {
+		t.Fatalf("Expected both gitego blocks to be listed, got %q (%v)", headers, err)
+	}
+
+	if err := RemoveAllIncludeIfs(); err != nil {
+		t.Fatalf("RemoveAllIncludeIfs returned an unexpected error: %v", err)
+	}
+
+	final, err := os.ReadFile(gitConfigPath)
+	if err != nil {
+		t.Fatalf("Failed to read .gitconfig: %v", err)
+	}
+
+	if strings.Contains(string(final), ".gitego") || strings.Contains(string(final), "gitego auto-switch rule") {
+		t.Errorf("Expected every gitego block to be removed.\nContent:\n%s", final)
+	}
+
+	if !strings.Contains(string(final), "other.gitconfig") || !strings.Contains(string(final), "[user]") {
+		t.Errorf("Expected the user's own sections to remain.\nContent:\n%s", final)
+	}
+}
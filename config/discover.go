@@ -0,0 +1,419 @@
+// config/discover.go
+
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ImportCandidate is a profile gitego found in another tool's configuration.
+type ImportCandidate struct {
+	// Name is the suggested gitego profile name.
+	Name string
+	// Source describes where the candidate was found.
+	Source string
+	// Profile holds whatever identity fields the source provided.
+	Profile Profile
+	// RulePath is a directory the identity was already scoped to, if any.
+	RulePath string
+	// UnsupportedRule is an includeIf pattern that can't become a rule because it is
+	// relative or uses wildcards.
+	UnsupportedRule string
+	// Token is a credential found alongside the identity. It is never written to config.yaml.
+	Token string
+}
+
+// gitconfigEntry is a single key from a gitconfig file.
+type gitconfigEntry struct {
+	section    string
+	subsection string
+	key        string
+	value      string
+}
+
+// GlobalGitconfigPath returns the path of the user's global .gitconfig.
+func GlobalGitconfigPath() string {
+	return gitConfigPath
+}
+
+// UserSSHConfigPath returns the path of the user's own ssh config.
+func UserSSHConfigPath() string {
+	home, _ := os.UserHomeDir()
+
+	return filepath.Join(home, ".ssh", "config")
+}
+
+// GHHostsPath returns the path of the GitHub CLI's hosts.yml, following the same
+// lookup order as gh itself.
+func GHHostsPath() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "hosts.yml")
+	}
+
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh", "hosts.yml")
+	}
+
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("AppData"); dir != "" {
+			return filepath.Join(dir, "GitHub CLI", "hosts.yml")
+		}
+	}
+
+	home, _ := os.UserHomeDir()
+
+	return filepath.Join(home, ".config", "gh", "hosts.yml")
+}
+
+// DiscoverGitconfigUser returns a candidate for the [user] section of a gitconfig file,
+// or nil if the file has no user.email.
+func DiscoverGitconfigUser(path, name, source string) (*ImportCandidate, error) {
+	entries, err := readGitconfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	candidate := &ImportCandidate{Name: name, Source: source}
+
+	for _, entry := range entries {
+		if entry.section != "user" || entry.subsection != "" {
+			continue
+		}
+
+		switch entry.key {
+		case "name":
+			candidate.Profile.Name = entry.value
+		case "email":
+			candidate.Profile.Email = entry.value
+		case "signingkey":
+			candidate.Profile.SigningKey = entry.value
+		}
+	}
+
+	if candidate.Profile.Email == "" {
+		return nil, nil
+	}
+
+	return candidate, nil
+}
+
+// DiscoverIncludeIfs returns a candidate for every gitdir includeIf block in a gitconfig
+// that points at a file not generated by gitego. The block's directory becomes the
+// candidate's RulePath, or its UnsupportedRule if it is not an absolute or "~/" path.
+func DiscoverIncludeIfs(gitconfigPath string) ([]ImportCandidate, error) {
+	entries, err := readGitconfig(gitconfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []ImportCandidate
+
+	for _, entry := range entries {
+		if entry.section != "includeif" || entry.key != "path" {
+			continue
+		}
+
+		dir, found := strings.CutPrefix(entry.subsection, "gitdir:")
+		if !found {
+			dir, found = strings.CutPrefix(entry.subsection, "gitdir/i:")
+		}
+
+		if !found {
+			continue
+		}
+
+		includePath := ExpandHome(entry.value)
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(filepath.Dir(gitconfigPath), includePath)
+		}
+
+		if strings.HasPrefix(filepath.ToSlash(includePath), filepath.ToSlash(profilesDir)) {
+			continue
+		}
+
+		name := strings.TrimPrefix(filepath.Base(includePath), ".")
+		name = strings.TrimPrefix(strings.TrimSuffix(name, filepath.Ext(name)), "gitconfig-")
+
+		candidate, err := DiscoverGitconfigUser(includePath, name,
+			fmt.Sprintf("includeIf %s in %s", entry.subsection, filepath.Base(gitconfigPath)))
+		if err != nil || candidate == nil {
+			continue
+		}
+
+		if rulePath, ok := includeIfRulePath(dir); ok {
+			candidate.RulePath = rulePath
+		} else {
+			candidate.UnsupportedRule = dir
+		}
+
+		candidates = append(candidates, *candidate)
+	}
+
+	return candidates, nil
+}
+
+// includeIfRulePath returns the directory a gitdir pattern applies to, if an auto rule can
+// express it. Git matches a relative pattern at any depth and wildcards can match many
+// directories, so only absolute and "~/" patterns qualify. A trailing "/**" is what git
+// assumes for a pattern ending in "/" anyway.
+func includeIfRulePath(pattern string) (string, bool) {
+	dir := pattern
+	if strings.HasSuffix(dir, "/**") {
+		dir = strings.TrimSuffix(dir, "**")
+	}
+
+	if strings.ContainsAny(dir, "*?[") {
+		return "", false
+	}
+
+	if !strings.HasPrefix(dir, "~/") && !filepath.IsAbs(dir) {
+		return "", false
+	}
+
+	return dir, true
+}
+
+// DiscoverSSHHosts returns a candidate for every non-wildcard Host block in an ssh config
+// that sets an IdentityFile. These candidates carry no name or email.
+func DiscoverSSHHosts(sshConfigPath string) ([]ImportCandidate, error) {
+	file, err := os.Open(sshConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var candidates []ImportCandidate
+
+	var current *ImportCandidate
+
+	flush := func() {
+		if current != nil && current.Profile.SSHKey != "" {
+			candidates = append(candidates, *current)
+		}
+
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		keyword, value := splitSSHConfigLine(scanner.Text())
+
+		switch keyword {
+		case "":
+			continue
+		case "host":
+			flush()
+
+			alias := strings.Fields(value)[0]
+			if !strings.ContainsAny(alias, "*?!") {
+				current = &ImportCandidate{Name: alias, Source: fmt.Sprintf("Host %s in ssh config", alias)}
+			}
+		case "match":
+			flush()
+		case "identityfile":
+			if current != nil && current.Profile.SSHKey == "" {
+				current.Profile.SSHKey = value
+			}
+		case "user":
+			if current != nil {
+				current.Profile.SSHUser = value
+			}
+		case "port":
+			if current != nil {
+				current.Profile.SSHPort, _ = strconv.Atoi(value)
+			}
+		}
+	}
+
+	flush()
+
+	return candidates, scanner.Err()
+}
+
+// splitSSHConfigLine returns the lowercase keyword and unquoted value of an ssh config line.
+func splitSSHConfigLine(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", ""
+	}
+
+	end := strings.IndexAny(line, " \t=")
+	if end < 0 {
+		return "", ""
+	}
+
+	keyword := line[:end]
+	value := strings.TrimSpace(line[end:])
+	value = strings.Trim(strings.TrimSpace(strings.TrimPrefix(value, "=")), `"`)
+
+	if value == "" {
+		return "", ""
+	}
+
+	return strings.ToLower(keyword), value
+}
+
+// ghHost is the part of a GitHub CLI hosts.yml entry that gitego can import.
+type ghHost struct {
+	User       string `yaml:"user"`
+	OAuthToken string `yaml:"oauth_token"`
+	Users      map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	} `yaml:"users"`
+}
+
+// DiscoverGHHosts returns a candidate for every account the GitHub CLI is logged in to.
+// Tokens are only present when gh stores them in the file rather than the OS keyring.
+func DiscoverGHHosts(hostsPath string) ([]ImportCandidate, error) {
+	data, err := os.ReadFile(hostsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	hosts := make(map[string]ghHost)
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", hostsPath, err)
+	}
+
+	hostNames := make([]string, 0, len(hosts))
+	for host := range hosts {
+		hostNames = append(hostNames, host)
+	}
+
+	sort.Strings(hostNames)
+
+	var candidates []ImportCandidate
+
+	for _, host := range hostNames {
+		entry := hosts[host]
+
+		logins := map[string]string{}
+		if entry.User != "" {
+			logins[entry.User] = entry.OAuthToken
+		}
+
+		for login, user := range entry.Users {
+			if logins[login] == "" {
+				logins[login] = user.OAuthToken
+			}
+		}
+
+		names := make([]string, 0, len(logins))
+		for login := range logins {
+			names = append(names, login)
+		}
+
+		sort.Strings(names)
+
+		for _, login := range names {
+			candidates = append(candidates, ImportCandidate{
+				Name:    login,
+				Source:  fmt.Sprintf("GitHub CLI login for %s", host),
+				Profile: Profile{Username: login},
+				Token:   logins[login],
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+// readGitconfig parses the sections and keys of a gitconfig file. Section and key names
+// are lowercased as git treats them case-insensitively; subsections keep their case.
+// A missing file yields no entries.
+func readGitconfig(path string) ([]gitconfigEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var entries []gitconfigEntry
+
+	var section, subsection string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			header := strings.TrimSuffix(strings.TrimPrefix(line, "["), "]")
+			if end := strings.Index(header, "]"); end >= 0 {
+				header = header[:end]
+			}
+
+			name, sub, _ := strings.Cut(header, " ")
+			section = strings.ToLower(strings.TrimSpace(name))
+			subsection = strings.Trim(strings.TrimSpace(sub), `"`)
+
+			if dot := strings.Index(section, "."); dot >= 0 && subsection == "" {
+				section, subsection = section[:dot], section[dot+1:]
+			}
+
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, "=")
+		entries = append(entries, gitconfigEntry{
+			section:    section,
+			subsection: subsection,
+			key:        strings.ToLower(strings.TrimSpace(key)),
+			value:      unquoteGitconfigValue(strings.TrimSpace(value)),
+		})
+	}
+
+	return entries, scanner.Err()
+}
+
+// unquoteGitconfigValue strips inline comments, surrounding quotes and escapes from a value.
+func unquoteGitconfigValue(value string) string {
+	var b strings.Builder
+
+	inQuotes := false
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+		case c == '\\' && i+1 < len(value):
+			i++
+			b.WriteByte(value[i])
+		case (c == '#' || c == ';') && !inQuotes:
+			return strings.TrimSpace(b.String())
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
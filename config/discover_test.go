@@ -0,0 +1,152 @@
+// config/discover_test.go
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestDiscoverIncludeIfs verifies that includeIf blocks pointing at the user's own files
+// become candidates with a rule path, while gitego's generated files are ignored and relative
+// or wildcard patterns get no rule path.
+func TestDiscoverIncludeIfs(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalProfilesDir := profilesDir
+	profilesDir = filepath.Join(tempDir, ".gitego", "profiles")
+
+	defer func() {
+		profilesDir = originalProfilesDir
+	}()
+
+	workConfig := filepath.Join(tempDir, ".gitconfig-work")
+	if err := os.WriteFile(workConfig, []byte("[user]\n\tname = \"Work User\"\n\temail = work@corp.com ; comment\n"), 0644); err != nil {
+		t.Fatalf("Failed to write include file: %v", err)
+	}
+
+	gitconfig := filepath.Join(tempDir, ".gitconfig")
+	content := `[user]
+	name = Personal
+	email = me@example.com
+[includeIf "gitdir:~/work/"]
+	path = ` + workConfig + `
+[includeIf "gitdir:~/gitego/"]
+	path = ` + filepath.Join(profilesDir, "client.gitconfig") + `
+[includeIf "gitdir:oss/"]
+	path = ` + workConfig + `
+[includeIf "gitdir:**/client/"]
+	path = ` + workConfig + `
+[includeIf "gitdir:/src/team/**"]
+	path = ` + workConfig + `
+`
+	if err := os.WriteFile(gitconfig, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write gitconfig: %v", err)
+	}
+
+	candidates, err := DiscoverIncludeIfs(gitconfig)
+	if err != nil {
+		t.Fatalf("DiscoverIncludeIfs returned an error: %v", err)
+	}
+
+	if len(candidates) != 4 {
+		t.Fatalf("Expected 4 candidates, got %d: %+v", len(candidates), candidates)
+	}
+
+	// Relative and wildcard patterns can't be resolved to a directory.
+	for i, want := range []string{"oss/", "**/client/"} {
+		if got := candidates[i+1]; got.RulePath != "" || got.UnsupportedRule != want {
+			t.Errorf("Expected '%s' to be unsupported, got rule '%s'", want, got.RulePath)
+		}
+	}
+
+	if got := candidates[3].RulePath; got != "/src/team/" {
+		t.Errorf("Expected '/src/team/**' to become rule '/src/team/', got '%s'", got)
+	}
+
+	candidate := candidates[0]
+	if candidate.Name != "work" || candidate.RulePath != "~/work/" {
+		t.Errorf("Expected candidate 'work' for '~/work/', got '%s' for '%s'", candidate.Name, candidate.RulePath)
+	}
+
+	if candidate.Profile.Name != "Work User" || candidate.Profile.Email != "work@corp.com" {
+		t.Errorf("Expected identity 'Work User <work@corp.com>', got '%s <%s>'",
+			candidate.Profile.Name, candidate.Profile.Email)
+	}
+
+	global, err := DiscoverGitconfigUser(gitconfig, "global", "global .gitconfig")
+	if err != nil || global == nil || global.Profile.Email != "me@example.com" {
+		t.Errorf("Expected the global user to be discovered, got %+v (%v)", global, err)
+	}
+}
+
+// TestDiscoverSSHHosts verifies that only concrete hosts with an IdentityFile are returned.
+func TestDiscoverSSHHosts(t *testing.T) {
+	sshConfig := filepath.Join(t.TempDir(), "config")
+	content := `Host *
+    AddKeysToAgent yes
+    IdentityFile ~/.ssh/id_default
+
+Host github-work
+    HostName github.com
+    User git
+    IdentityFile "~/.ssh/id work"
+    Port=2222
+
+Host plain
+    HostName example.com
+`
+	if err := os.WriteFile(sshConfig, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write ssh config: %v", err)
+	}
+
+	candidates, err := DiscoverSSHHosts(sshConfig)
+	if err != nil {
+		t.Fatalf("DiscoverSSHHosts returned an error: %v", err)
+	}
+
+	if len(candidates) != 1 {
+		t.Fatalf("Expected 1 candidate, got %d: %+v", len(candidates), candidates)
+	}
+
+	profile := candidates[0].Profile
+	if candidates[0].Name != "github-work" || profile.SSHKey != "~/.ssh/id work" || profile.SSHPort != 2222 {
+		t.Errorf("Unexpected candidate: %+v", candidates[0])
+	}
+}
+
+// TestDiscoverGHHosts verifies that every gh login becomes a candidate with its token.
+func TestDiscoverGHHosts(t *testing.T) {
+	hostsPath := filepath.Join(t.TempDir(), "hosts.yml")
+	content := `github.com:
+    user: octocat
+    oauth_token: gho_abc
+    git_protocol: https
+    users:
+        octocat:
+            oauth_token: gho_abc
+        hubot:
+            oauth_token: gho_def
+`
+	if err := os.WriteFile(hostsPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write hosts.yml: %v", err)
+	}
+
+	candidates, err := DiscoverGHHosts(hostsPath)
+	if err != nil {
+		t.Fatalf("DiscoverGHHosts returned an error: %v", err)
+	}
+
+	if len(candidates) != 2 {
+		t.Fatalf("Expected 2 candidates, got %d: %+v", len(candidates), candidates)
+	}
+
+	if candidates[0].Name != "hubot" || candidates[0].Token != "gho_def" {
+		t.Errorf("Unexpected first candidate: %+v", candidates[0])
+	}
+
+	if candidates[1].Profile.Username != "octocat" || candidates[1].Token != "gho_abc" {
+		t.Errorf("Unexpected second candidate: %+v", candidates[1])
+	}
+}
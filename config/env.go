@@ -0,0 +1,70 @@
+// config/env.go
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// SecretRefPrefix marks an env value as a reference to a secret in gitego's vault,
+	// e.g. "vault:GITLAB_TOKEN". The secret itself never appears in config.yaml.
+	SecretRefPrefix = "vault:"
+	// PATSecretName is the secret name that refers to a profile's Personal Access Token.
+	PATSecretName = "pat"
+)
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// IsValidEnvName reports whether name can be used as an environment variable name.
+func IsValidEnvName(name string) bool {
+	return envNamePattern.MatchString(name)
+}
+
+// SecretRef returns the env value that refers to the named secret.
+func SecretRef(name string) string {
+	return SecretRefPrefix + name
+}
+
+// ParseSecretRef returns the secret name an env value refers to, if it is a reference.
+func ParseSecretRef(value string) (string, bool) {
+	name, found := strings.CutPrefix(value, SecretRefPrefix)
+
+	return name, found && name != ""
+}
+
+// ResolveEnv returns the profile's environment with every secret reference replaced by
+// the secret's value, looked up with getSecret (see EnvSecret).
+func (p *Profile) ResolveEnv(profileName string, getSecret func(profileName, name string) (string, error)) (map[string]string, error) {
+	env := make(map[string]string, len(p.Env))
+
+	for key, value := range p.Env {
+		if name, isRef := ParseSecretRef(value); isRef {
+			secret, err := p.EnvSecret(profileName, key, name, getSecret)
+			if err != nil {
+				return nil, fmt.Errorf("could not read secret '%s' for %s: %w", name, key, err)
+			}
+
+			value = secret
+		}
+
+		env[key] = value
+	}
+
+	return env, nil
+}
+
+// EnvSecret reads the named secret that the env entry key refers to. The profile's own
+// vault is tried first, so it can override a secret it inherits; an inherited reference
+// then falls back to the vault of the base profile the entry comes from.
+func (p *Profile) EnvSecret(profileName, key, name string, getSecret func(profileName, name string) (string, error)) (string, error) {
+	secret, err := getSecret(profileName, name)
+
+	if source := p.EnvSource(profileName, key); err != nil && source != profileName {
+		secret, err = getSecret(source, name)
+	}
+
+	return secret, err
+}
@@ -0,0 +1,69 @@
+// config/env_test.go
+
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolveEnv(t *testing.T) {
+	profile := &Profile{Env: map[string]string{
+		"GH_HOST":      "github.corp.com",
+		"GH_TOKEN":     "vault:pat",
+		"GITLAB_TOKEN": "vault:GITLAB_TOKEN",
+	}}
+
+	secrets := map[string]string{"work/pat": "ghp_123", "work/GITLAB_TOKEN": "glpat_456"}
+
+	getSecret := func(profileName, name string) (string, error) {
+		secret, ok := secrets[profileName+"/"+name]
+		if !ok {
+			return "", errors.New("not found")
+		}
+
+		return secret, nil
+	}
+
+	env, err := profile.ResolveEnv("work", getSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if env["GH_HOST"] != "github.corp.com" || env["GH_TOKEN"] != "ghp_123" || env["GITLAB_TOKEN"] != "glpat_456" {
+		t.Errorf("Unexpected resolved env: %v", env)
+	}
+
+	if _, err := profile.ResolveEnv("personal", func(string, string) (string, error) {
+		return "", errors.New("not found")
+	}); err == nil {
+		t.Error("Expected an error for a missing secret")
+	}
+
+	// A reference a profile inherits is read from its base's vault unless it has its own.
+	cfg := &Config{Profiles: map[string]*Profile{
+		"work":   {Env: profile.Env},
+		"client": {Extends: "work"},
+	}}
+	cfg.ResolveProfiles()
+
+	env, err = cfg.Profiles["client"].ResolveEnv("client", getSecret)
+	if err != nil || env["GH_TOKEN"] != "ghp_123" {
+		t.Errorf("Expected client to read the secrets of work, got %v, %v", env, err)
+	}
+
+	secrets["client/pat"] = "ghp_789"
+
+	env, err = cfg.Profiles["client"].ResolveEnv("client", getSecret)
+	if err != nil || env["GH_TOKEN"] != "ghp_789" || env["GITLAB_TOKEN"] != "glpat_456" {
+		t.Errorf("Expected client's own token to override the inherited one, got %v, %v", env, err)
+	}
+}
+
+func TestIsValidEnvName(t *testing.T) {
+	for name, valid := range map[string]bool{"GH_TOKEN": true, "_x1": true, "1X": false, "NPM-TOKEN": false, "": false} {
+		if IsValidEnvName(name) != valid {
+			t.Errorf("IsValidEnvName(%q) = %v, expected %v", name, !valid, valid)
+		}
+	}
+}
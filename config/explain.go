@@ -0,0 +1,48 @@
+// config/explain.go
+
+package config
+
+import "os"
+
+// RuleMatch records how one auto rule was evaluated against a directory.
+type RuleMatch struct {
+	Rule *AutoRule
+	// Path is the normalized rule path that was compared with the directory.
+	Path string
+	// Exists reports whether the rule's path exists. One that doesn't can't have its
+	// symlinks resolved and is compared as written.
+	Exists bool
+	// Err is set when the rule's path could not be normalized; such rules are skipped.
+	Err      error
+	Matched  bool
+	Selected bool
+}
+
+// ExplainRules evaluates every auto rule against dir the way profile resolution does. It
+// returns the normalized directory and, for each rule in order, whether it matched and
+// whether it was selected as the longest matching prefix.
+func (c *Config) ExplainRules(dir string) (string, []RuleMatch, error) {
+	absDir, err := cleanPath(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	best := c.findBestMatchingRule(absDir)
+	matches := make([]RuleMatch, 0, len(c.AutoRules))
+
+	for _, rule := range c.AutoRules {
+		match := RuleMatch{Rule: rule, Selected: rule == best}
+
+		_, statErr := os.Stat(rule.Path)
+		match.Exists = statErr == nil
+
+		match.Path, match.Err = cleanPath(rule.Path)
+		if match.Err == nil {
+			match.Matched = c.isPathMatch(absDir, match.Path)
+		}
+
+		matches = append(matches, match)
+	}
+
+	return absDir, matches, nil
+}
@@ -0,0 +1,51 @@
+// config/explain_test.go
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExplainRules(t *testing.T) {
+	root := t.TempDir()
+	workDir := filepath.Join(root, "work")
+
+	if err := os.MkdirAll(filepath.Join(workDir, "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{
+		AutoRules: []*AutoRule{
+			{Path: root, Profile: "personal"},
+			{Path: workDir, Profile: "work"},
+			{Path: filepath.Join(root, "gone"), Profile: "old"},
+		},
+	}
+
+	dir, matches, err := cfg.ExplainRules(filepath.Join(workDir, "api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.Base(filepath.Clean(dir)) != "api" || dir[len(dir)-1] != '/' {
+		t.Errorf("Expected a normalized directory ending in api/, got %s", dir)
+	}
+
+	if len(matches) != 3 {
+		t.Fatalf("Expected 3 evaluated rules, got %d", len(matches))
+	}
+
+	if !matches[0].Matched || matches[0].Selected {
+		t.Errorf("Expected the root rule to match without being selected, got %+v", matches[0])
+	}
+
+	if !matches[1].Matched || !matches[1].Selected || !matches[1].Exists {
+		t.Errorf("Expected the work rule to be selected, got %+v", matches[1])
+	}
+
+	if matches[2].Matched || matches[2].Exists {
+		t.Errorf("Expected the missing rule path not to match, got %+v", matches[2])
+	}
+}
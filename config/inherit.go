@@ -0,0 +1,331 @@
+// config/inherit.go
+
+package config
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+const (
+	// extendsField is the yaml name of Profile.Extends, which is never inherited itself.
+	extendsField = "extends"
+	// envField is the yaml name of Profile.Env, which is merged with its bases entry by entry.
+	envField = "env"
+)
+
+// InheritedFields returns the yaml names of the fields the profile takes from the profile
+// it extends, in the order they are declared.
+func (p *Profile) InheritedFields() []string {
+	var fields []string
+
+	for _, field := range profileFields() {
+		if p.Inherits(field.name) {
+			fields = append(fields, field.name)
+		}
+	}
+
+	return fields
+}
+
+// Inherits reports whether the profile takes the field with the given yaml name from the
+// profile it extends. Env counts as inherited if any of its entries is.
+func (p *Profile) Inherits(field string) bool {
+	if field == envField {
+		for key := range p.envSources {
+			if p.EnvSource("", key) != "" {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	_, ok := p.inherited[field]
+
+	return ok
+}
+
+// FieldValue is a field a profile sets or inherits, formatted for display.
+type FieldValue struct {
+	Name      string
+	Value     string
+	Inherited bool
+}
+
+// FieldValues returns the profile's effective fields that have a value, in the order they
+// are declared, noting which are inherited.
+func (p *Profile) FieldValues() []FieldValue {
+	var values []FieldValue
+
+	value := reflect.ValueOf(p).Elem()
+
+	for _, field := range profileFields() {
+		fieldValue := value.Field(field.index)
+		if isEmptyValue(fieldValue) {
+			continue
+		}
+
+		formatted := fmt.Sprint(fieldValue.Interface())
+
+		if fieldValue.Kind() == reflect.Map {
+			var pairs []string
+			for iter := fieldValue.MapRange(); iter.Next(); {
+				pairs = append(pairs, fmt.Sprintf("%v=%v", iter.Key(), iter.Value()))
+			}
+
+			slices.Sort(pairs)
+			formatted = strings.Join(pairs, ", ")
+		}
+
+		values = append(values, FieldValue{Name: field.name, Value: formatted, Inherited: p.Inherits(field.name)})
+	}
+
+	return values
+}
+
+// ExtendsChain returns the profiles the named profile extends, nearest first. It fails
+// if a base profile does not exist or the chain loops back on itself.
+func (c *Config) ExtendsChain(profileName string) ([]string, error) {
+	var chain []string
+
+	seen := []string{profileName}
+
+	for name := profileName; c.Profiles[name] != nil && c.Profiles[name].Extends != ""; {
+		base := c.Profiles[name].Extends
+
+		if slices.Contains(seen, base) {
+			return nil, fmt.Errorf("profile '%s' extends itself: %s -> %s",
+				profileName, strings.Join(seen, " -> "), base)
+		}
+
+		if _, exists := c.Profiles[base]; !exists {
+			return nil, fmt.Errorf("profile '%s' extends '%s', which does not exist", name, base)
+		}
+
+		chain = append(chain, base)
+		seen = append(seen, base)
+		name = base
+	}
+
+	return chain, nil
+}
+
+// ResolveProfiles merges every profile that extends another with its bases, field by field:
+// each field the profile leaves empty takes the value of the nearest base that sets it.
+// Env is merged entry by entry instead; see inheritEnv.
+// Profiles whose chain is broken are left as they are; validateConfig reports them.
+// Load and Save call it; call it again after changing a base profile in memory.
+func (c *Config) ResolveProfiles() {
+	raw := make(map[string]Profile, len(c.Profiles))
+	for name, profile := range c.rawProfiles() {
+		*c.Profiles[name] = *profile
+		raw[name] = *profile
+	}
+
+	for name, profile := range c.Profiles {
+		profile.inherited = nil
+		profile.envSources = nil
+
+		chain, err := c.ExtendsChain(name)
+		if err != nil || len(chain) == 0 {
+			continue
+		}
+
+		profile.inheritEnv(chain, raw)
+
+		value := reflect.ValueOf(profile).Elem()
+
+		for _, field := range profileFields() {
+			target := value.Field(field.index)
+			if field.name == envField || !isEmptyValue(target) {
+				continue
+			}
+
+			for _, base := range chain {
+				baseValue := reflect.ValueOf(raw[base]).Field(field.index)
+				if isEmptyValue(baseValue) {
+					continue
+				}
+
+				// Maps are copied so that editing the profile leaves its base alone.
+				target.Set(cloneValue(baseValue))
+
+				if profile.inherited == nil {
+					profile.inherited = make(map[string]any)
+				}
+
+				profile.inherited[field.name] = cloneValue(baseValue).Interface()
+
+				break
+			}
+		}
+	}
+}
+
+// inheritEnv merges the env entries of the profile's bases into its own, the nearest base
+// winning. An entry the profile sets to an empty value removes the one it would inherit,
+// so a profile can drop variables, or its whole env, that it doesn't want.
+func (p *Profile) inheritEnv(chain []string, raw map[string]Profile) {
+	inherited := make(map[string]string)
+	sources := make(map[string]string)
+
+	merge := func(env map[string]string, source string) {
+		for key, value := range env {
+			if _, exists := inherited[key]; exists && value == "" {
+				delete(inherited, key)
+				delete(sources, key)
+
+				continue
+			}
+
+			inherited[key] = value
+			sources[key] = source
+		}
+	}
+
+	for _, base := range slices.Backward(chain) {
+		merge(raw[base].Env, base)
+	}
+
+	if len(inherited) == 0 {
+		return
+	}
+
+	base := maps.Clone(inherited)
+	merge(p.Env, "")
+	maps.DeleteFunc(sources, func(_, source string) bool { return source == "" })
+
+	p.Env = inherited
+	p.envSources = sources
+
+	if p.inherited == nil {
+		p.inherited = make(map[string]any)
+	}
+
+	p.inherited[envField] = base
+}
+
+// EnvSource returns the name of the profile an env entry comes from: the base profile it
+// is inherited from, or profileName if the profile sets the entry itself.
+func (p *Profile) EnvSource(profileName, key string) string {
+	inherited, _ := p.inherited[envField].(map[string]string)
+
+	if source, ok := p.envSources[key]; ok && inherited[key] == p.Env[key] {
+		return source
+	}
+
+	return profileName
+}
+
+// rawProfiles returns the profiles as they are written to config.yaml: a field still
+// holding the value it inherited is left empty, so it keeps following its base. Fields of
+// a profile whose base has been removed are kept, so no values are lost.
+func (c *Config) rawProfiles() map[string]*Profile {
+	raw := make(map[string]*Profile, len(c.Profiles))
+
+	for name, profile := range c.Profiles {
+		copied := *profile
+		copied.inherited = nil
+		copied.envSources = nil
+		raw[name] = &copied
+
+		if _, exists := c.Profiles[profile.Extends]; !exists || len(profile.inherited) == 0 {
+			continue
+		}
+
+		value := reflect.ValueOf(&copied).Elem()
+
+		for _, field := range profileFields() {
+			inherited, ok := profile.inherited[field.name]
+			target := value.Field(field.index)
+
+			if ok && field.name != envField && reflect.DeepEqual(target.Interface(), inherited) {
+				target.Set(reflect.Zero(target.Type()))
+			}
+		}
+
+		if inherited, ok := profile.inherited[envField].(map[string]string); ok {
+			copied.Env = ownEnv(profile.Env, inherited)
+		}
+	}
+
+	return raw
+}
+
+// ownEnv returns the env entries a profile sets itself: those that differ from what it
+// inherits, plus an empty value for each inherited entry it has removed.
+func ownEnv(env, inherited map[string]string) map[string]string {
+	own := make(map[string]string)
+
+	for key, value := range env {
+		if base, ok := inherited[key]; !ok || base != value {
+			own[key] = value
+		}
+	}
+
+	for key := range inherited {
+		if _, ok := env[key]; !ok {
+			own[key] = ""
+		}
+	}
+
+	if len(own) == 0 {
+		return nil
+	}
+
+	return own
+}
+
+// profileField is a Profile field that can be inherited.
+type profileField struct {
+	index int
+	name  string
+}
+
+// profileFields returns the Profile fields that are stored in config.yaml and can be
+// inherited, with their yaml names.
+func profileFields() []profileField {
+	var fields []profileField
+
+	profileType := reflect.TypeFor[Profile]()
+
+	for i := range profileType.NumField() {
+		field := profileType.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if !field.IsExported() || name == "" || name == "-" || name == extendsField {
+			continue
+		}
+
+		fields = append(fields, profileField{index: i, name: name})
+	}
+
+	return fields
+}
+
+// cloneValue returns a copy of a profile field's value that shares no map with it.
+func cloneValue(value reflect.Value) reflect.Value {
+	if value.Kind() != reflect.Map {
+		return value
+	}
+
+	clone := reflect.MakeMapWithSize(value.Type(), value.Len())
+	for iter := value.MapRange(); iter.Next(); {
+		clone.SetMapIndex(iter.Key(), iter.Value())
+	}
+
+	return clone
+}
+
+// isEmptyValue reports whether a profile field is unset. An empty map counts as unset.
+func isEmptyValue(value reflect.Value) bool {
+	if value.Kind() == reflect.Map {
+		return value.Len() == 0
+	}
+
+	return value.IsZero()
+}
@@ -0,0 +1,170 @@
+// config/inherit_test.go
+
+package config
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestProfileInheritance verifies that profiles extending a base take its values on load,
+// and that Save writes only their own values back.
+func TestProfileInheritance(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalConfigPath, originalSSHConfigPath, originalAllowedSignersPath :=
+		gitegoConfigPath, sshConfigPath, allowedSignersPath
+	gitegoConfigPath = filepath.Join(tempDir, "config.yaml")
+	sshConfigPath = filepath.Join(tempDir, "ssh_config")
+	allowedSignersPath = filepath.Join(tempDir, "allowed_signers")
+
+	defer func() {
+		gitegoConfigPath, sshConfigPath, allowedSignersPath =
+			originalConfigPath, originalSSHConfigPath, originalAllowedSignersPath
+	}()
+
+	content := `
+profiles:
+  consultant:
+    name: Jane Doe
+    ssh_key: ~/.ssh/id_consulting
+    env:
+      EDITOR: vim
+  acme:
+    extends: consultant
+    email: jane@acme.com
+  acme-ops:
+    extends: acme
+    ssh_key: ~/.ssh/id_acme
+`
+	if err := os.WriteFile(gitegoConfigPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned an unexpected error: %v", err)
+	}
+
+	ops := cfg.Profiles["acme-ops"]
+	if ops.Name != "Jane Doe" || ops.Email != "jane@acme.com" || ops.SSHKey != "~/.ssh/id_acme" {
+		t.Errorf("Expected acme-ops to merge its chain, got %+v", ops)
+	}
+
+	if got := ops.InheritedFields(); !slices.Equal(got, []string{"name", "email", "env"}) {
+		t.Errorf("Expected name, email and env to be inherited, got %v", got)
+	}
+
+	// Editing an inherited map must not change the base.
+	ops.Env["EDITOR"] = "nano"
+
+	if cfg.Profiles["consultant"].Env["EDITOR"] != "vim" {
+		t.Error("Expected the base profile's env to be left alone")
+	}
+
+	cfg.Profiles["consultant"].Name = "Jane Q. Doe"
+
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() returned an unexpected error: %v", err)
+	}
+
+	if cfg.Profiles["acme"].Name != "Jane Q. Doe" {
+		t.Errorf("Expected acme to follow its base's new name after saving, got %q", cfg.Profiles["acme"].Name)
+	}
+
+	data, err := os.ReadFile(gitegoConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Count(string(data), "name:") != 1 || !strings.Contains(string(data), "EDITOR: nano") {
+		t.Errorf("Expected only the base's name and the overridden env to be written, got:\n%s", data)
+	}
+}
+
+// TestExtendsChain verifies that missing bases and cycles are reported.
+func TestExtendsChain(t *testing.T) {
+	cfg := &Config{Profiles: map[string]*Profile{
+		"base":    {Name: "Base"},
+		"child":   {Extends: "base"},
+		"orphan":  {Extends: "gone"},
+		"a":       {Extends: "b"},
+		"b":       {Extends: "a"},
+		"herself": {Extends: "herself"},
+	}}
+
+	if chain, err := cfg.ExtendsChain("child"); err != nil || !slices.Equal(chain, []string{"base"}) {
+		t.Errorf("ExtendsChain(child) = %v, %v; expected [base]", chain, err)
+	}
+
+	if _, err := cfg.ExtendsChain("orphan"); err == nil || !strings.Contains(err.Error(), "'gone', which does not exist") {
+		t.Errorf("Expected a missing base error, got %v", err)
+	}
+
+	if _, err := cfg.ExtendsChain("a"); err == nil || !strings.Contains(err.Error(), "a -> b -> a") {
+		t.Errorf("Expected a cycle error, got %v", err)
+	}
+
+	if _, err := cfg.ExtendsChain("herself"); err == nil {
+		t.Error("Expected a profile extending itself to be reported")
+	}
+
+	cfg.ResolveProfiles()
+
+	if cfg.Profiles["child"].Name != "Base" || cfg.Profiles["a"].Name != "" {
+		t.Errorf("Expected only valid chains to be resolved, got %+v", cfg.Profiles)
+	}
+}
+
+// TestProfileInheritance_Env verifies that env is merged entry by entry, that an empty
+// value removes an inherited entry, and that inherited entries remember their source.
+func TestProfileInheritance_Env(t *testing.T) {
+	cfg := &Config{Profiles: map[string]*Profile{
+		"consultant": {Env: map[string]string{"EDITOR": "vim", "GH_TOKEN": "vault:pat", "NPM_TOKEN": "vault:NPM_TOKEN"}},
+		"acme":       {Extends: "consultant", Env: map[string]string{"EDITOR": "nano", "NPM_TOKEN": ""}},
+		"acme-ops":   {Extends: "acme", Env: map[string]string{"GH_HOST": "github.acme.com"}},
+		"quiet":      {Extends: "consultant", Env: map[string]string{"EDITOR": "", "GH_TOKEN": "", "NPM_TOKEN": ""}},
+	}}
+
+	cfg.ResolveProfiles()
+
+	ops := cfg.Profiles["acme-ops"]
+	expected := map[string]string{"EDITOR": "nano", "GH_TOKEN": "vault:pat", "GH_HOST": "github.acme.com"}
+
+	if !maps.Equal(ops.Env, expected) {
+		t.Errorf("Expected acme-ops env %v, got %v", expected, ops.Env)
+	}
+
+	for key, source := range map[string]string{"EDITOR": "acme", "GH_TOKEN": "consultant", "GH_HOST": "acme-ops"} {
+		if got := ops.EnvSource("acme-ops", key); got != source {
+			t.Errorf("EnvSource(%s) = %q, expected %q", key, got, source)
+		}
+	}
+
+	if len(cfg.Profiles["quiet"].Env) != 0 || cfg.Profiles["quiet"].Inherits("env") {
+		t.Errorf("Expected quiet to drop its whole inherited env, got %v", cfg.Profiles["quiet"].Env)
+	}
+
+	// Overriding an inherited entry makes it the profile's own.
+	ops.Env["GH_TOKEN"] = "vault:GH_TOKEN"
+	delete(ops.Env, "EDITOR")
+
+	if got := ops.EnvSource("acme-ops", "GH_TOKEN"); got != "acme-ops" {
+		t.Errorf("Expected an overridden entry to belong to acme-ops, got %q", got)
+	}
+
+	raw := cfg.rawProfiles()
+	expected = map[string]string{"EDITOR": "", "GH_TOKEN": "vault:GH_TOKEN", "GH_HOST": "github.acme.com"}
+
+	if !maps.Equal(raw["acme-ops"].Env, expected) {
+		t.Errorf("Expected acme-ops to be saved with env %v, got %v", expected, raw["acme-ops"].Env)
+	}
+
+	if !maps.Equal(raw["quiet"].Env, map[string]string{"EDITOR": "", "GH_TOKEN": "", "NPM_TOKEN": ""}) {
+		t.Errorf("Expected quiet to keep its removals when saved, got %v", raw["quiet"].Env)
+	}
+}
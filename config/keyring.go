@@ -8,6 +8,9 @@ import "github.com/zalando/go-keyring"
 // This is the name under which gitego stores its own library of PATs.
 const gitegoKeyringService = "gitego"
 
+// ErrSecretNotFound is returned when gitego's vault has no entry for a token or secret.
+var ErrSecretNotFound = keyring.ErrNotFound
+
 // SetToken securely stores a PAT for a given profile name in gitego's vault.
 func SetToken(profileName, token string) error {
 	return keyring.Set(gitegoKeyringService, profileName, token)
@@ -22,3 +25,33 @@ func GetToken(profileName string) (string, error) {
 func DeleteToken(profileName string) error {
 	return keyring.Delete(gitegoKeyringService, profileName)
 }
+
+// secretAccount is the vault account under which a profile's named secret is stored.
+func secretAccount(profileName, name string) string {
+	return profileName + "/env/" + name
+}
+
+// SetSecret securely stores a named secret for a profile in gitego's vault.
+func SetSecret(profileName, name, value string) error {
+	return keyring.Set(gitegoKeyringService, secretAccount(profileName, name), value)
+}
+
+// GetSecret retrieves a profile's named secret from gitego's vault. The name "pat"
+// refers to the profile's Personal Access Token.
+func GetSecret(profileName, name string) (string, error) {
+	if name == PATSecretName {
+		return GetToken(profileName)
+	}
+
+	return keyring.Get(gitegoKeyringService, secretAccount(profileName, name))
+}
+
+// DeleteSecret removes a profile's named secret from gitego's vault.
+func DeleteSecret(profileName, name string) error {
+	return keyring.Delete(gitegoKeyringService, secretAccount(profileName, name))
+}
+
+// DeleteAllSecrets removes every token and secret gitego stored in the vault.
+func DeleteAllSecrets() error {
+	return keyring.DeleteAll(gitegoKeyringService)
+}
@@ -0,0 +1,86 @@
+// config/keys.go
+
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// privateKeyPermissions are the permissions ssh requires for private key files.
+	privateKeyPermissions = 0600
+	// keysDirPermissions keeps generated keys readable only by their owner.
+	keysDirPermissions = 0700
+	// sshKeyFilename is the file name of keys generated by gitego.
+	sshKeyFilename = "id_ed25519"
+)
+
+// ErrKeyExists is returned by GenerateSSHKey when the profile already has a generated key.
+var ErrKeyExists = errors.New("key already exists")
+
+// SSHKeyPath returns the path of the private key gitego generates for a profile.
+// The public key sits next to it with a .pub suffix.
+func SSHKeyPath(profileName string) string {
+	return filepath.Join(keysDir, profileName, sshKeyFilename)
+}
+
+// GenerateSSHKey creates an ed25519 keypair for a profile under ~/.gitego/keys/<profile>.
+// If passphrase is non-empty the private key is encrypted with it. The comment is appended
+// to the public key, which is returned in authorized_keys format without a trailing newline.
+// Existing keys are only replaced when overwrite is true.
+func GenerateSSHKey(profileName, comment, passphrase string, overwrite bool) (string, string, error) {
+	privPath := SSHKeyPath(profileName)
+
+	if _, err := os.Stat(privPath); err == nil && !overwrite {
+		return "", "", fmt.Errorf("%s: %w", privPath, ErrKeyExists)
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", fmt.Errorf("could not generate key: %w", err)
+	}
+
+	var block *pem.Block
+	if passphrase != "" {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(priv, comment, []byte(passphrase))
+	} else {
+		block, err = ssh.MarshalPrivateKey(priv, comment)
+	}
+
+	if err != nil {
+		return "", "", fmt.Errorf("could not encode private key: %w", err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return "", "", fmt.Errorf("could not encode public key: %w", err)
+	}
+
+	authorizedKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+	if comment != "" {
+		authorizedKey += " " + comment
+	}
+
+	if err := os.MkdirAll(filepath.Dir(privPath), keysDirPermissions); err != nil {
+		return "", "", fmt.Errorf("could not create keys directory: %w", err)
+	}
+
+	if err := os.WriteFile(privPath, pem.EncodeToMemory(block), privateKeyPermissions); err != nil {
+		return "", "", fmt.Errorf("could not write private key: %w", err)
+	}
+
+	if err := os.WriteFile(privPath+".pub", []byte(authorizedKey+"\n"), filePermissions); err != nil {
+		return "", "", fmt.Errorf("could not write public key: %w", err)
+	}
+
+	return privPath, authorizedKey, nil
+}
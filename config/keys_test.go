@@ -0,0 +1,55 @@
+// config/keys_test.go
+
+package config
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// TestGenerateSSHKey verifies that a generated keypair is written with safe
+// permissions and can be parsed back with its passphrase.
+func TestGenerateSSHKey(t *testing.T) {
+	originalKeysDir := keysDir
+	keysDir = t.TempDir()
+
+	defer func() {
+		keysDir = originalKeysDir
+	}()
+
+	privPath, pubKey, err := GenerateSSHKey("work", "work@example.com", "s3cret", false)
+	if err != nil {
+		t.Fatalf("GenerateSSHKey returned an error: %v", err)
+	}
+
+	if !strings.HasPrefix(pubKey, "ssh-ed25519 ") || !strings.HasSuffix(pubKey, " work@example.com") {
+		t.Errorf("Expected an ed25519 authorized key with a comment, got '%s'", pubKey)
+	}
+
+	pemBytes, err := os.ReadFile(privPath)
+	if err != nil {
+		t.Fatalf("Failed to read private key: %v", err)
+	}
+
+	if _, err := ssh.ParseRawPrivateKeyWithPassphrase(pemBytes, []byte("s3cret")); err != nil {
+		t.Errorf("Failed to parse private key with passphrase: %v", err)
+	}
+
+	if info, err := os.Stat(privPath); err == nil && runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		t.Errorf("Expected private key to be readable only by its owner, got %v", info.Mode().Perm())
+	}
+
+	pubFile, err := os.ReadFile(privPath + ".pub")
+	if err != nil || strings.TrimSpace(string(pubFile)) != pubKey {
+		t.Errorf("Expected public key file to match the returned key, got '%s' (%v)", pubFile, err)
+	}
+
+	if _, _, err := GenerateSSHKey("work", "", "", false); !errors.Is(err, ErrKeyExists) {
+		t.Errorf("Expected ErrKeyExists when a key is already present, got %v", err)
+	}
+}
@@ -0,0 +1,370 @@
+// config/policy.go
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy is a read-only set of constraints, usually distributed by a security team, that
+// gitego enforces on profiles, auto-switch rules and commits. gitego never writes it.
+type Policy struct {
+	// Sources lists the files the policy was loaded from.
+	Sources []string `yaml:"-"`
+	// Rules constrain the identity used in matching repositories.
+	Rules []PolicyRule `yaml:"rules,omitempty"`
+	// ForbiddenHosts are hosts that no repository remote may point at.
+	ForbiddenHosts []string `yaml:"forbidden_hosts,omitempty"`
+}
+
+// PolicyRule constrains the identity used under a path or for a remote. A rule with
+// neither a path nor a remote applies everywhere.
+type PolicyRule struct {
+	// Path is a directory; the rule applies to repositories inside it. ~/ is expanded.
+	Path string `yaml:"path,omitempty"`
+	// Remote is a pattern such as github.com/corp/* matched against repository remotes.
+	Remote string `yaml:"remote,omitempty"`
+	// AllowedEmailDomains lists the domains the commit email may use.
+	AllowedEmailDomains []string `yaml:"allowed_email_domains,omitempty"`
+	// RequireSigning requires commits to be signed.
+	RequireSigning bool `yaml:"require_signing,omitempty"`
+	// RequireSSH requires SSH authentication: no stored PAT and no HTTPS remotes.
+	RequireSSH bool `yaml:"require_ssh,omitempty"`
+
+	// source overrides the scope shown in violations, for rules not read from a policy file.
+	source string
+}
+
+// PolicyViolation describes one way a profile or commit breaks a policy rule.
+type PolicyViolation struct {
+	Scope   string
+	Message string
+}
+
+// String formats the violation for display.
+func (v PolicyViolation) String() string {
+	return fmt.Sprintf("policy for %s: %s", v.Scope, v.Message)
+}
+
+// defaultSystemPolicyPath returns the machine-wide policy location.
+func defaultSystemPolicyPath() string {
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("ProgramData"); dir != "" {
+			return filepath.Join(dir, "gitego", "policy.yaml")
+		}
+	}
+
+	return "/etc/gitego/policy.yaml"
+}
+
+// LoadPolicy reads the system-wide policy and the policy file named in the config, if any,
+// and combines them. A missing system policy is not an error; a missing configured one is.
+// The result is never nil.
+func LoadPolicy(cfg *Config) (*Policy, error) {
+	policy := &Policy{}
+
+	if err := policy.merge(systemPolicyPath, true); err != nil {
+		return nil, err
+	}
+
+	if cfg != nil && cfg.PolicyPath != "" {
+		if err := policy.merge(ExpandHome(cfg.PolicyPath), false); err != nil {
+			return nil, err
+		}
+	}
+
+	return policy, nil
+}
+
+// merge adds the rules of the policy file at path.
+func (p *Policy) merge(path string, optional bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if optional && os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("could not read policy file: %w", err)
+	}
+
+	var file Policy
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("could not parse policy file %s: %w", path, err)
+	}
+
+	p.Sources = append(p.Sources, path)
+	p.Rules = append(p.Rules, file.Rules...)
+	p.ForbiddenHosts = append(p.ForbiddenHosts, file.ForbiddenHosts...)
+
+	return nil
+}
+
+// IsEmpty reports whether the policy has no constraints.
+func (p *Policy) IsEmpty() bool {
+	return len(p.Rules) == 0 && len(p.ForbiddenHosts) == 0
+}
+
+// CheckGlobalProfile checks a profile used as the global default against rules that apply
+// everywhere.
+func (p *Policy) CheckGlobalProfile(profile *Profile, hasToken bool) []PolicyViolation {
+	var violations []PolicyViolation
+
+	for _, rule := range p.Rules {
+		if rule.Path == "" && rule.Remote == "" {
+			violations = append(violations, rule.CheckProfile(profile, hasToken)...)
+		}
+	}
+
+	return violations
+}
+
+// CheckRule checks a profile used by an auto-switch rule on dir against every rule whose
+// path overlaps dir, as well as rules that apply everywhere.
+func (p *Policy) CheckRule(dir string, profile *Profile, hasToken bool) []PolicyViolation {
+	var violations []PolicyViolation
+
+	dir = policyDir(dir)
+
+	for _, rule := range p.Rules {
+		if rule.Remote != "" {
+			continue
+		}
+
+		if rule.Path != "" {
+			rulePath := policyDir(rule.Path)
+			if !strings.HasPrefix(dir, rulePath) && !strings.HasPrefix(rulePath, dir) {
+				continue
+			}
+		}
+
+		violations = append(violations, rule.CheckProfile(profile, hasToken)...)
+	}
+
+	return violations
+}
+
+// CheckProfile checks a profile everywhere it applies in cfg: against rules that apply
+// everywhere, and under each of its auto-switch rules. Remote rules are only checked at
+// commit time, when the remotes are known.
+func (p *Policy) CheckProfile(cfg *Config, name string, profile *Profile, hasToken bool) []PolicyViolation {
+	var violations []PolicyViolation
+
+	seen := make(map[string]bool)
+	add := func(found []PolicyViolation) {
+		for _, v := range found {
+			if !seen[v.String()] {
+				seen[v.String()] = true
+				violations = append(violations, v)
+			}
+		}
+	}
+
+	add(p.CheckGlobalProfile(profile, hasToken))
+
+	for _, rule := range cfg.AutoRules {
+		if rule.Profile == name {
+			add(p.CheckRule(rule.Path, profile, hasToken))
+		}
+	}
+
+	return violations
+}
+
+// CheckCommit checks the identity a commit is about to be made with in the repository at
+// dir with the given remote URLs.
+func (p *Policy) CheckCommit(dir string, remotes []string, email string, signing bool) []PolicyViolation {
+	var violations []PolicyViolation
+
+	for _, remote := range remotes {
+		host := RemoteHost(remote)
+
+		for _, forbidden := range p.ForbiddenHosts {
+			if hostMatches(host, forbidden) {
+				violations = append(violations, PolicyViolation{"all repositories",
+					fmt.Sprintf("remote '%s' points at forbidden host '%s'", remote, forbidden)})
+			}
+		}
+	}
+
+	dir = policyDir(dir)
+
+	for _, rule := range p.Rules {
+		if rule.appliesToRepo(dir, remotes) {
+			violations = append(violations, rule.checkCommit(remotes, email, signing)...)
+		}
+	}
+
+	return violations
+}
+
+// checkCommit checks a commit identity against this rule alone.
+func (r PolicyRule) checkCommit(remotes []string, email string, signing bool) []PolicyViolation {
+	var violations []PolicyViolation
+
+	if v, ok := r.checkEmail(email); !ok {
+		violations = append(violations, v)
+	}
+
+	if r.RequireSigning && !signing {
+		violations = append(violations, PolicyViolation{r.scope(), "commits must be signed (commit.gpgsign is off)"})
+	}
+
+	if r.RequireSSH {
+		for _, remote := range remotes {
+			if lower := strings.ToLower(remote); strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+				violations = append(violations, PolicyViolation{r.scope(),
+					fmt.Sprintf("remote '%s' must use SSH instead of HTTPS", remote)})
+			}
+		}
+	}
+
+	return violations
+}
+
+// CheckProfile checks a profile against this rule alone.
+func (r PolicyRule) CheckProfile(profile *Profile, hasToken bool) []PolicyViolation {
+	var violations []PolicyViolation
+
+	if v, ok := r.checkEmail(profile.Email); !ok {
+		violations = append(violations, v)
+	}
+
+	if r.RequireSigning && profile.SigningMode() == SigningNone {
+		violations = append(violations, PolicyViolation{r.scope(), "commits must be signed; set a signing key"})
+	}
+
+	if r.RequireSSH {
+		if hasToken {
+			violations = append(violations, PolicyViolation{r.scope(), "a stored personal access token is not allowed; use SSH"})
+		}
+
+		if profile.SSHKey == "" {
+			violations = append(violations, PolicyViolation{r.scope(), "SSH authentication is required; set an SSH key"})
+		}
+	}
+
+	return violations
+}
+
+func (r PolicyRule) checkEmail(email string) (PolicyViolation, bool) {
+	if len(r.AllowedEmailDomains) == 0 {
+		return PolicyViolation{}, true
+	}
+
+	domain := ""
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		domain = email[at+1:]
+	}
+
+	for _, allowed := range r.AllowedEmailDomains {
+		if strings.EqualFold(domain, strings.TrimPrefix(allowed, "@")) {
+			return PolicyViolation{}, true
+		}
+	}
+
+	return PolicyViolation{r.scope(), fmt.Sprintf("email '%s' is not in an allowed domain (%s)",
+		email, strings.Join(r.AllowedEmailDomains, ", "))}, false
+}
+
+// appliesToRepo reports whether the rule covers a repository at dir with the given remotes.
+func (r PolicyRule) appliesToRepo(dir string, remotes []string) bool {
+	if r.Path != "" && !strings.HasPrefix(dir, policyDir(r.Path)) {
+		return false
+	}
+
+	if r.Remote == "" {
+		return true
+	}
+
+	for _, remote := range remotes {
+		if remoteMatches(NormalizeRemote(remote), r.Remote) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scope describes where the rule applies.
+func (r PolicyRule) scope() string {
+	switch {
+	case r.source != "":
+		return r.source
+	case r.Path != "" && r.Remote != "":
+		return fmt.Sprintf("%s (remote %s)", r.Path, r.Remote)
+	case r.Path != "":
+		return r.Path
+	case r.Remote != "":
+		return "remote " + r.Remote
+	default:
+		return "all repositories"
+	}
+}
+
+// policyDir expands and cleans a directory so prefixes can be compared.
+func policyDir(dir string) string {
+	cleaned := filepath.ToSlash(filepath.Clean(ExpandHome(dir)))
+
+	return strings.TrimSuffix(cleaned, "/") + "/"
+}
+
+// NormalizeRemote reduces a remote URL to host/path form, e.g. both
+// git@github.com:corp/app.git and https://github.com/corp/app become github.com/corp/app.
+func NormalizeRemote(remote string) string {
+	remote = strings.TrimSpace(remote)
+
+	if scheme := strings.Index(remote, "://"); scheme >= 0 {
+		remote = remote[scheme+3:]
+	} else if colon := strings.Index(remote, ":"); colon >= 0 {
+		remote = remote[:colon] + "/" + remote[colon+1:]
+	}
+
+	if at := strings.Index(remote, "@"); at >= 0 && at < strings.Index(remote+"/", "/") {
+		remote = remote[at+1:]
+	}
+
+	host, rest, _ := strings.Cut(remote, "/")
+	if colon := strings.Index(host, ":"); colon >= 0 {
+		host = host[:colon]
+	}
+
+	rest = strings.TrimSuffix(strings.TrimSuffix(rest, "/"), ".git")
+
+	return strings.ToLower(host) + "/" + rest
+}
+
+// RemoteHost returns the host of a remote URL.
+func RemoteHost(remote string) string {
+	host, _, _ := strings.Cut(NormalizeRemote(remote), "/")
+
+	return host
+}
+
+// remoteMatches reports whether a normalized remote matches a pattern, ignoring case:
+// either a glob or a prefix such as github.com/corp.
+func remoteMatches(normalized, pattern string) bool {
+	pattern = strings.TrimSuffix(strings.ToLower(pattern), "/")
+	normalized = strings.ToLower(normalized)
+
+	if normalized == pattern || strings.HasPrefix(normalized, pattern+"/") {
+		return true
+	}
+
+	matched, _ := path.Match(pattern, normalized)
+
+	return matched
+}
+
+// hostMatches reports whether host is forbidden or a subdomain of it.
+func hostMatches(host, forbidden string) bool {
+	forbidden = strings.ToLower(forbidden)
+
+	return host == forbidden || strings.HasSuffix(host, "."+forbidden)
+}
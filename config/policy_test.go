@@ -0,0 +1,135 @@
+// config/policy_test.go
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPolicy = `rules:
+  - path: /src/work
+    allowed_email_domains: ["corp.com"]
+    require_signing: true
+    require_ssh: true
+  - remote: github.com/corp
+    allowed_email_domains: ["@corp.com"]
+forbidden_hosts:
+  - pastebin.example
+`
+
+// TestLoadPolicy verifies that the system and configured policies are combined and that
+// a missing system policy is ignored.
+func TestLoadPolicy(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalSystemPolicyPath := systemPolicyPath
+	systemPolicyPath = filepath.Join(tempDir, "missing.yaml")
+
+	defer func() { systemPolicyPath = originalSystemPolicyPath }()
+
+	policy, err := LoadPolicy(&Config{})
+	if err != nil || !policy.IsEmpty() {
+		t.Fatalf("Expected an empty policy without policy files, got %+v (err: %v)", policy, err)
+	}
+
+	systemPolicyPath = filepath.Join(tempDir, "system.yaml")
+	if err := os.WriteFile(systemPolicyPath, []byte(testPolicy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	userPolicy := filepath.Join(tempDir, "user.yaml")
+	if err := os.WriteFile(userPolicy, []byte("forbidden_hosts: [gist.example]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err = LoadPolicy(&Config{PolicyPath: userPolicy})
+	if err != nil {
+		t.Fatalf("LoadPolicy failed: %v", err)
+	}
+
+	if len(policy.Rules) != 2 || len(policy.ForbiddenHosts) != 2 || len(policy.Sources) != 2 {
+		t.Errorf("Expected both policy files to be merged, got %+v", policy)
+	}
+
+	if _, err := LoadPolicy(&Config{PolicyPath: filepath.Join(tempDir, "nope.yaml")}); err == nil {
+		t.Error("Expected an error for a missing configured policy file.")
+	}
+}
+
+// TestPolicyCheckRule verifies that profiles are checked against rules overlapping a path.
+func TestPolicyCheckRule(t *testing.T) {
+	policy := &Policy{Rules: []PolicyRule{{
+		Path:                "/src/work",
+		AllowedEmailDomains: []string{"corp.com"},
+		RequireSigning:      true,
+		RequireSSH:          true,
+	}}}
+
+	compliant := &Profile{Email: "me@corp.com", SigningKey: "ABCD1234", SSHKey: "~/.ssh/id_work"}
+	if v := policy.CheckRule("/src/work/app/", compliant, false); len(v) != 0 {
+		t.Errorf("Expected no violations, got %v", v)
+	}
+
+	if v := policy.CheckRule("/src/personal/", &Profile{Email: "me@gmail.com"}, true); len(v) != 0 {
+		t.Errorf("Expected rules outside the path to be ignored, got %v", v)
+	}
+
+	// A rule on a parent directory also covers the policy path.
+	violations := policy.CheckRule("/src/", &Profile{Email: "me@gmail.com"}, true)
+	if len(violations) != 4 {
+		t.Fatalf("Expected 4 violations, got %v", violations)
+	}
+
+	if !strings.Contains(violations[0].String(), "email 'me@gmail.com' is not in an allowed domain") {
+		t.Errorf("Unexpected violation message: %s", violations[0])
+	}
+}
+
+// TestPolicyCheckCommit verifies remote matching, forbidden hosts and HTTPS detection.
+func TestPolicyCheckCommit(t *testing.T) {
+	policy := &Policy{
+		Rules: []PolicyRule{
+			{Remote: "github.com/corp", AllowedEmailDomains: []string{"@corp.com"}, RequireSSH: true},
+		},
+		ForbiddenHosts: []string{"pastebin.example"},
+	}
+
+	tests := []struct {
+		name     string
+		remotes  []string
+		email    string
+		expected int
+	}{
+		{"no matching remote", []string{"git@github.com:me/dotfiles.git"}, "me@gmail.com", 0},
+		{"compliant", []string{"git@github.com:corp/app.git"}, "me@corp.com", 0},
+		{"wrong domain", []string{"ssh://git@github.com/corp/app"}, "me@gmail.com", 1},
+		{"https remote", []string{"https://github.com/corp/app.git"}, "me@corp.com", 1},
+		{"forbidden host", []string{"https://git.pastebin.example/x"}, "me@corp.com", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if v := policy.CheckCommit("/src/", tt.remotes, tt.email, false); len(v) != tt.expected {
+				t.Errorf("Expected %d violation(s), got %v", tt.expected, v)
+			}
+		})
+	}
+}
+
+// TestNormalizeRemote verifies that SSH, scp-style and HTTPS remotes normalize alike.
+func TestNormalizeRemote(t *testing.T) {
+	remotes := []string{
+		"git@github.com:Corp/app.git",
+		"ssh://git@github.com:22/Corp/app.git",
+		"https://user@github.com/Corp/app",
+	}
+
+	for _, remote := range remotes {
+		if got := NormalizeRemote(remote); got != "github.com/Corp/app" {
+			t.Errorf("NormalizeRemote(%q) = %q, expected 'github.com/Corp/app'", remote, got)
+		}
+	}
+}
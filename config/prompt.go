@@ -0,0 +1,156 @@
+// config/prompt.go
+
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// promptCacheVersion is bumped whenever the cache layout changes, so stale caches are rebuilt.
+const promptCacheVersion = 2
+
+// PromptCache is a precompiled form of the auto rules for shell prompts, which read it
+// instead of parsing config.yaml and calling git on every prompt. It is rebuilt whenever
+// config.yaml changes. Trust in .gitego.yaml files is checked on each lookup instead, so
+// trusting or revoking a repository takes effect at once.
+type PromptCache struct {
+	Version int `json:"version"`
+	// ConfigModTime and ConfigSize identify the config.yaml the cache was built from.
+	ConfigModTime int64      `json:"config_mod_time"`
+	ConfigSize    int64      `json:"config_size"`
+	Default       PromptRule `json:"default"`
+	// Rules are normalized and ordered longest path first, so the first match is the best.
+	Rules []PromptRule `json:"rules"`
+	// Emails maps every profile to its email, for profiles named by a trusted .gitego.yaml.
+	Emails map[string]string `json:"emails,omitempty"`
+}
+
+// PromptRule is a normalized rule path with the profile it selects.
+type PromptRule struct {
+	Path    string `json:"path,omitempty"`
+	Profile string `json:"profile"`
+	Email   string `json:"email,omitempty"`
+}
+
+// promptCachePath lives next to config.yaml so it follows the config wherever it is.
+func promptCachePath() string {
+	return filepath.Join(filepath.Dir(gitegoConfigPath), "prompt_cache.json")
+}
+
+// NewPromptCache compiles the auto rules and default profile of cfg.
+func NewPromptCache(cfg *Config) *PromptCache {
+	cache := &PromptCache{Version: promptCacheVersion, Rules: []PromptRule{}, Emails: map[string]string{}}
+
+	for name, profile := range cfg.Profiles {
+		cache.Emails[name] = profile.Email
+	}
+
+	if profile, exists := cfg.Profiles[cfg.ActiveProfile]; exists {
+		cache.Default = PromptRule{Profile: cfg.ActiveProfile, Email: profile.Email}
+	}
+
+	for _, rule := range cfg.AutoRules {
+		path, err := cleanPath(rule.Path)
+		if err != nil {
+			continue
+		}
+
+		compiled := PromptRule{Path: path, Profile: rule.Profile}
+		if profile, exists := cfg.Profiles[rule.Profile]; exists {
+			compiled.Email = profile.Email
+		}
+
+		cache.Rules = append(cache.Rules, compiled)
+	}
+
+	// A stable sort keeps the first of equally long rules first, as findBestMatchingRule does.
+	sort.SliceStable(cache.Rules, func(i, j int) bool {
+		return len(cache.Rules[i].Path) > len(cache.Rules[j].Path)
+	})
+
+	return cache
+}
+
+// LoadPromptCache returns the prompt cache, rebuilding it from config.yaml if it is
+// missing or out of date. A cache that can't be written is still returned.
+func LoadPromptCache() (*PromptCache, error) {
+	var modTime, size int64
+
+	if info, err := os.Stat(gitegoConfigPath); err == nil {
+		modTime, size = info.ModTime().UnixNano(), info.Size()
+	}
+
+	if data, err := os.ReadFile(promptCachePath()); err == nil {
+		cache := &PromptCache{}
+		if json.Unmarshal(data, cache) == nil && cache.Version == promptCacheVersion &&
+			cache.ConfigModTime == modTime && cache.ConfigSize == size {
+			return cache, nil
+		}
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	cache := NewPromptCache(cfg)
+	cache.ConfigModTime, cache.ConfigSize = modTime, size
+
+	if data, err := json.Marshal(cache); err == nil {
+		if err := os.MkdirAll(filepath.Dir(promptCachePath()), dirPermissions); err == nil {
+			_ = os.WriteFile(promptCachePath(), data, filePermissions)
+		}
+	}
+
+	return cache, nil
+}
+
+// Resolve returns the rule that applies to dir. Without one, it falls back to the profile
+// of a trusted .gitego.yaml and then to the default profile, as GetActiveProfileForCurrentDir does.
+func (pc *PromptCache) Resolve(dir string) PromptRule {
+	absDir, err := cleanPath(dir)
+	if err != nil {
+		return pc.Default
+	}
+
+	for _, rule := range pc.Rules {
+		if pathHasPrefix(absDir, rule.Path) {
+			return rule
+		}
+	}
+
+	if rc, err := FindRepoConfig(absDir); err == nil && rc != nil && rc.Trusted && rc.Profile != "" {
+		if email, exists := pc.Emails[rc.Profile]; exists {
+			return PromptRule{Profile: rc.Profile, Email: email}
+		}
+	}
+
+	return pc.Default
+}
+
+// LocalUserEmail returns the user.email set in the .git/config of the repository containing
+// dir, reading the file directly rather than running git. It returns "" if there is none.
+func LocalUserEmail(dir string) string {
+	root, err := findRepoRoot(dir)
+	if err != nil || root == "" {
+		return ""
+	}
+
+	entries, err := readGitconfig(filepath.Join(root, ".git", "config"))
+	if err != nil {
+		return ""
+	}
+
+	email := ""
+
+	for _, entry := range entries {
+		if entry.section == "user" && entry.subsection == "" && entry.key == "email" {
+			email = entry.value
+		}
+	}
+
+	return email
+}
@@ -0,0 +1,130 @@
+// config/prompt_test.go
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPromptCache(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalConfigPath := gitegoConfigPath
+	gitegoConfigPath = filepath.Join(tempDir, ".gitego", "config.yaml")
+
+	defer func() { gitegoConfigPath = originalConfigPath }()
+
+	workDir := filepath.Join(tempDir, "work")
+	if err := os.MkdirAll(filepath.Join(workDir, "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(gitegoConfigPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "profiles:\n  work:\n    name: Work\n    email: work@corp.com\n  me:\n    name: Me\n    email: me@gmail.com\n" +
+		"active_profile: me\nauto_rules:\n  - path: " + tempDir + "\n    profile: me\n  - path: " + workDir + "\n    profile: work\n"
+	if err := os.WriteFile(gitegoConfigPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache, err := LoadPromptCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(promptCachePath()); err != nil {
+		t.Errorf("Expected the cache to be written: %v", err)
+	}
+
+	if rule := cache.Resolve(filepath.Join(workDir, "api")); rule.Profile != "work" || rule.Email != "work@corp.com" {
+		t.Errorf("Expected the work rule, got %+v", rule)
+	}
+
+	if rule := cache.Resolve(os.TempDir()); rule.Profile != "me" {
+		t.Errorf("Expected the default profile outside any rule, got %+v", rule)
+	}
+
+	// Changing config.yaml must invalidate the cache.
+	content = "profiles:\n  work:\n    email: work@corp.com\nauto_rules:\n  - path: " + workDir + "\n    profile: work\n"
+	if err := os.WriteFile(gitegoConfigPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache, err = LoadPromptCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cache.Rules) != 1 || cache.Default.Profile != "" {
+		t.Errorf("Expected a rebuilt cache, got %+v", cache)
+	}
+}
+
+// TestPromptCacheRepoConfig verifies that the prompt shows the profile of a trusted
+// .gitego.yaml when no auto rule applies, and ignores it until it is trusted.
+func TestPromptCacheRepoConfig(t *testing.T) {
+	root := setupTestRepo(t, "profile: work\n")
+
+	cfg := &Config{
+		Profiles: map[string]*Profile{
+			"work": {Email: "work@corp.com"},
+			"me":   {Email: "me@gmail.com"},
+		},
+		ActiveProfile: "me",
+	}
+	cache := NewPromptCache(cfg)
+	subDir := filepath.Join(root, "sub", "dir")
+
+	if rule := cache.Resolve(subDir); rule.Profile != "me" {
+		t.Errorf("Expected an untrusted .gitego.yaml to be ignored, got %+v", rule)
+	}
+
+	rc, err := FindRepoConfig(root)
+	if err != nil || rc == nil {
+		t.Fatalf("Expected to find the repo config, got %v (err: %v)", rc, err)
+	}
+
+	if err := rc.Trust(); err != nil {
+		t.Fatalf("Trust failed: %v", err)
+	}
+
+	if rule := cache.Resolve(subDir); rule.Profile != "work" || rule.Email != "work@corp.com" {
+		t.Errorf("Expected the trusted .gitego.yaml profile, got %+v", rule)
+	}
+
+	// An auto rule of the user's own still wins.
+	cfg.AutoRules = []*AutoRule{{Path: root, Profile: "me"}}
+	if rule := NewPromptCache(cfg).Resolve(subDir); rule.Profile != "me" {
+		t.Errorf("Expected the auto rule to win over .gitego.yaml, got %+v", rule)
+	}
+}
+
+func TestLocalUserEmail(t *testing.T) {
+	repo := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := LocalUserEmail(repo); got != "" {
+		t.Errorf("Expected no override without a .git/config, got %q", got)
+	}
+
+	gitConfig := "[core]\n\tbare = false\n[user]\n\temail = \"me@gmail.com\"\n"
+	if err := os.WriteFile(filepath.Join(repo, ".git", "config"), []byte(gitConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := filepath.Join(repo, "src")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := LocalUserEmail(sub); got != "me@gmail.com" {
+		t.Errorf("Expected me@gmail.com, got %q", got)
+	}
+}
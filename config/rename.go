@@ -0,0 +1,133 @@
+// config/rename.go
+
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ValidateNewProfileName checks that name can be used for a new profile: it must be a
+// valid profile name that is not taken.
+func (c *Config) ValidateNewProfileName(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+
+	if _, exists := c.Profiles[name]; exists {
+		return fmt.Errorf("profile '%s' already exists", name)
+	}
+
+	return nil
+}
+
+// RenameProfile renames a profile in memory together with every reference to it in the
+// config: auto rules, the active profile, the pairing session and profiles extending it.
+func (c *Config) RenameProfile(oldName, newName string) error {
+	profile, exists := c.Profiles[oldName]
+	if !exists {
+		return fmt.Errorf("profile '%s' not found", oldName)
+	}
+
+	if err := c.ValidateNewProfileName(newName); err != nil {
+		return err
+	}
+
+	delete(c.Profiles, oldName)
+	c.Profiles[newName] = profile
+
+	for _, rule := range c.AutoRules {
+		if rule.Profile == oldName {
+			rule.Profile = newName
+		}
+	}
+
+	if c.ActiveProfile == oldName {
+		c.ActiveProfile = newName
+	}
+
+	for i, name := range c.Pair {
+		if name == oldName {
+			c.Pair[i] = newName
+		}
+	}
+
+	for _, other := range c.Profiles {
+		if other.Extends == oldName {
+			other.Extends = newName
+		}
+	}
+
+	return nil
+}
+
+// Clone returns a copy of the profile that shares no maps with it.
+func (p *Profile) Clone() *Profile {
+	clone := *p
+	clone.Env = maps.Clone(p.Env)
+	clone.inherited = maps.Clone(p.inherited)
+	clone.envSources = maps.Clone(p.envSources)
+
+	return &clone
+}
+
+// RenameProfileGitconfig moves the gitconfig file generated for a profile to the new
+// profile name. It reports false if the profile has no such file.
+func RenameProfileGitconfig(oldName, newName string) (bool, error) {
+	err := os.Rename(ProfileGitconfigPath(oldName), ProfileGitconfigPath(newName))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// RenameIncludeIfs points the includeIf blocks in the global .gitconfig that include the
+// old profile's gitconfig at the new profile's. It returns how many were changed.
+func RenameIncludeIfs(oldName, newName string) (int, error) {
+	input, err := os.ReadFile(gitConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	oldPath := filepath.ToSlash(ProfileGitconfigPath(oldName))
+	newPath := filepath.ToSlash(ProfileGitconfigPath(newName))
+
+	lines := strings.Split(string(input), "\n")
+	renamed := 0
+	inIncludeIf := false
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "[") {
+			inIncludeIf = strings.HasPrefix(trimmed, "[includeIf")
+
+			continue
+		}
+
+		key, value, found := strings.Cut(trimmed, "=")
+		if !inIncludeIf || !found || strings.TrimSpace(key) != "path" {
+			continue
+		}
+
+		if filepath.ToSlash(strings.Trim(strings.TrimSpace(value), `"`)) == oldPath {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			lines[i] = indent + "path = " + newPath
+			renamed++
+		}
+	}
+
+	if renamed == 0 {
+		return 0, nil
+	}
+
+	return renamed, os.WriteFile(gitConfigPath, []byte(strings.Join(lines, "\n")), filePermissions)
+}
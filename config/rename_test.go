@@ -0,0 +1,115 @@
+// config/rename_test.go
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestRenameProfile verifies that every reference to a renamed profile follows it.
+func TestRenameProfile(t *testing.T) {
+	cfg := &Config{
+		Profiles: map[string]*Profile{
+			"work":   {Name: "Work", Email: "work@corp.com"},
+			"client": {Extends: "work", Email: "me@client.com"},
+			"pal":    {Name: "Pal", Email: "pal@corp.com"},
+		},
+		AutoRules:     []*AutoRule{{Path: "/src/work/", Profile: "work"}, {Path: "/src/pal/", Profile: "pal"}},
+		ActiveProfile: "work",
+		Pair:          []string{"pal", "work"},
+	}
+
+	if err := cfg.RenameProfile("work", "pal"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected renaming onto an existing profile to fail, got %v", err)
+	}
+
+	if err := cfg.RenameProfile("work", "a/b"); err == nil {
+		t.Error("Expected a name with a path separator to be rejected")
+	}
+
+	if err := cfg.RenameProfile("work", "corp"); err != nil {
+		t.Fatalf("RenameProfile returned an unexpected error: %v", err)
+	}
+
+	if _, exists := cfg.Profiles["work"]; exists || cfg.Profiles["corp"].Email != "work@corp.com" {
+		t.Errorf("Expected 'work' to be renamed to 'corp', got %v", cfg.Profiles)
+	}
+
+	if cfg.AutoRules[0].Profile != "corp" || cfg.AutoRules[1].Profile != "pal" {
+		t.Errorf("Expected only the work rule to follow, got %+v %+v", cfg.AutoRules[0], cfg.AutoRules[1])
+	}
+
+	if cfg.ActiveProfile != "corp" || !slices.Equal(cfg.Pair, []string{"pal", "corp"}) || cfg.Profiles["client"].Extends != "corp" {
+		t.Errorf("Expected the active profile, pair and base to follow, got %q %v %q",
+			cfg.ActiveProfile, cfg.Pair, cfg.Profiles["client"].Extends)
+	}
+}
+
+// TestRenameIncludeIfs verifies that only includeIf paths to the old profile's gitconfig
+// are changed, and that the gitconfig file itself is moved.
+func TestRenameIncludeIfs(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalGitConfigPath := gitConfigPath
+	originalProfilesDir := profilesDir
+	gitConfigPath = filepath.Join(tempDir, ".gitconfig")
+	profilesDir = filepath.Join(tempDir, ".gitego", "profiles")
+
+	defer func() {
+		gitConfigPath = originalGitConfigPath
+		profilesDir = originalProfilesDir
+	}()
+
+	workPath := filepath.ToSlash(ProfileGitconfigPath("work"))
+	content := `[include]
+    path = ` + workPath + `
+
+# gitego auto-switch rule
+[includeIf "gitdir:/src/work/"]
+    path = ` + workPath + `
+
+# gitego auto-switch rule
+[includeIf "gitdir:/src/work-2/"]
+    path = ` + filepath.ToSlash(ProfileGitconfigPath("work-2")) + `
+`
+	if err := os.WriteFile(gitConfigPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	renamed, err := RenameIncludeIfs("work", "corp")
+	if err != nil || renamed != 1 {
+		t.Fatalf("Expected 1 includeIf to be renamed, got %d (%v)", renamed, err)
+	}
+
+	final, err := os.ReadFile(gitConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Replace(content, `"gitdir:/src/work/"]
+    path = `+workPath, `"gitdir:/src/work/"]
+    path = `+filepath.ToSlash(ProfileGitconfigPath("corp")), 1)
+	if string(final) != expected {
+		t.Errorf("Unexpected .gitconfig:\n%s\nexpected:\n%s", final, expected)
+	}
+
+	if moved, err := RenameProfileGitconfig("work", "corp"); err != nil || moved {
+		t.Errorf("Expected a missing gitconfig file to be skipped, got %v (%v)", moved, err)
+	}
+
+	if err := EnsureProfileGitconfig("work", &Profile{Name: "Work", Email: "work@corp.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if moved, err := RenameProfileGitconfig("work", "corp"); err != nil || !moved {
+		t.Fatalf("Expected the gitconfig file to be moved, got %v (%v)", moved, err)
+	}
+
+	if _, err := os.Stat(ProfileGitconfigPath("corp")); err != nil {
+		t.Errorf("Expected the renamed gitconfig file to exist: %v", err)
+	}
+}
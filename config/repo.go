@@ -0,0 +1,182 @@
+// config/repo.go
+
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RepoConfigName is the file a repository commits at its root to declare the identity it
+// expects.
+const RepoConfigName = ".gitego.yaml"
+
+// RepoConfig is a repository's .gitego.yaml. It only takes effect once the user has
+// trusted the file's current contents, so cloning a repository can't change identities.
+type RepoConfig struct {
+	// Profile is the name of the gitego profile the repository expects, if the user has one.
+	Profile string `yaml:"profile,omitempty"`
+	// AllowedEmailDomains lists the domains commits in the repository may use.
+	AllowedEmailDomains []string `yaml:"allowed_email_domains,omitempty"`
+	// RequireSigning requires commits in the repository to be signed.
+	RequireSigning bool `yaml:"require_signing,omitempty"`
+
+	// Path is the absolute path of the file.
+	Path string `yaml:"-"`
+	// Hash identifies the file's contents for trust decisions.
+	Hash string `yaml:"-"`
+	// Trusted reports whether the user has trusted these exact contents.
+	Trusted bool `yaml:"-"`
+}
+
+// trustedRepos is the on-disk record of trusted repository files.
+type trustedRepos struct {
+	// Files maps a .gitego.yaml path to the hash of the contents that were trusted.
+	Files map[string]string `yaml:"files"`
+}
+
+// FindRepoConfig returns the .gitego.yaml at the root of the repository containing dir,
+// or nil if there is no repository or no file.
+func FindRepoConfig(dir string) (*RepoConfig, error) {
+	root, err := findRepoRoot(dir)
+	if err != nil || root == "" {
+		return nil, err
+	}
+
+	path := filepath.Join(root, RepoConfigName)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+
+	rc := &RepoConfig{}
+	if err := yaml.Unmarshal(data, rc); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+
+	sum := sha256.Sum256(data)
+	rc.Path = path
+	rc.Hash = hex.EncodeToString(sum[:])
+
+	trusted, err := loadTrustedRepos()
+	if err != nil {
+		return nil, err
+	}
+
+	rc.Trusted = trusted.Files[path] == rc.Hash
+
+	return rc, nil
+}
+
+// findRepoRoot walks up from dir to the first directory containing .git.
+func findRepoRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if evalDir, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = evalDir
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+
+		dir = parent
+	}
+}
+
+// Dir returns the repository root the file applies to.
+func (rc *RepoConfig) Dir() string {
+	return filepath.Dir(rc.Path)
+}
+
+// Trust records the file's current contents as trusted.
+func (rc *RepoConfig) Trust() error {
+	trusted, err := loadTrustedRepos()
+	if err != nil {
+		return err
+	}
+
+	trusted.Files[rc.Path] = rc.Hash
+	rc.Trusted = true
+
+	return saveTrustedRepos(trusted)
+}
+
+// RevokeRepoTrust forgets the trust decision for a .gitego.yaml path. It reports whether
+// the path was trusted.
+func RevokeRepoTrust(path string) (bool, error) {
+	trusted, err := loadTrustedRepos()
+	if err != nil {
+		return false, err
+	}
+
+	if _, exists := trusted.Files[path]; !exists {
+		return false, nil
+	}
+
+	delete(trusted.Files, path)
+
+	return true, saveTrustedRepos(trusted)
+}
+
+// CheckCommit checks a commit identity against the file's requirements.
+func (rc *RepoConfig) CheckCommit(email string, signing bool) []PolicyViolation {
+	rule := PolicyRule{
+		AllowedEmailDomains: rc.AllowedEmailDomains,
+		RequireSigning:      rc.RequireSigning,
+		source:              rc.Path,
+	}
+
+	return rule.checkCommit(nil, email, signing)
+}
+
+func loadTrustedRepos() (*trustedRepos, error) {
+	trusted := &trustedRepos{}
+
+	data, err := os.ReadFile(trustedReposPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("could not read trusted repositories: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, trusted); err != nil {
+		return nil, fmt.Errorf("could not parse trusted repositories: %w", err)
+	}
+
+	if trusted.Files == nil {
+		trusted.Files = make(map[string]string)
+	}
+
+	return trusted, nil
+}
+
+func saveTrustedRepos(trusted *trustedRepos) error {
+	if err := os.MkdirAll(filepath.Dir(trustedReposPath), dirPermissions); err != nil {
+		return fmt.Errorf("could not create config directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(trusted)
+	if err != nil {
+		return fmt.Errorf("could not serialize trusted repositories: %w", err)
+	}
+
+	return os.WriteFile(trustedReposPath, data, filePermissions)
+}
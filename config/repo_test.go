@@ -0,0 +1,130 @@
+// config/repo_test.go
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupTestRepo creates a repository with a .gitego.yaml and points the trust store at a
+// temporary file. It returns the repository root.
+func setupTestRepo(t *testing.T, contents string) string {
+	t.Helper()
+
+	tempDir := t.TempDir()
+
+	originalTrustedReposPath := trustedReposPath
+	trustedReposPath = filepath.Join(tempDir, "trusted_repos.yaml")
+
+	t.Cleanup(func() { trustedReposPath = originalTrustedReposPath })
+
+	root := filepath.Join(tempDir, "repo")
+	if err := os.MkdirAll(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, "sub", "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, RepoConfigName), []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return root
+}
+
+// TestRepoConfigTrust verifies that a .gitego.yaml is found from a subdirectory, starts out
+// untrusted, and needs trusting again after it changes.
+func TestRepoConfigTrust(t *testing.T) {
+	root := setupTestRepo(t, "profile: work\nallowed_email_domains: [corp.com]\n")
+
+	rc, err := FindRepoConfig(filepath.Join(root, "sub", "dir"))
+	if err != nil || rc == nil {
+		t.Fatalf("Expected to find the repo config, got %v (err: %v)", rc, err)
+	}
+
+	if rc.Profile != "work" || rc.Trusted {
+		t.Errorf("Expected an untrusted config for profile 'work', got %+v", rc)
+	}
+
+	if err := rc.Trust(); err != nil {
+		t.Fatalf("Trust failed: %v", err)
+	}
+
+	if rc, _ = FindRepoConfig(root); !rc.Trusted {
+		t.Error("Expected the config to be trusted after Trust.")
+	}
+
+	if err := os.WriteFile(rc.Path, []byte("profile: personal\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if rc, _ = FindRepoConfig(root); rc.Trusted {
+		t.Error("Expected a changed config to need trusting again.")
+	}
+
+	if revoked, err := RevokeRepoTrust(rc.Path); err != nil || !revoked {
+		t.Errorf("Expected the trust entry to be revoked, got %v (err: %v)", revoked, err)
+	}
+}
+
+// TestFindRepoConfigOutsideRepo verifies that directories outside a repository have no config.
+func TestFindRepoConfigOutsideRepo(t *testing.T) {
+	if rc, err := FindRepoConfig(t.TempDir()); err != nil || rc != nil {
+		t.Errorf("Expected no config outside a repository, got %v (err: %v)", rc, err)
+	}
+}
+
+// TestRepoConfigCheckCommit verifies the file's email and signing requirements.
+func TestRepoConfigCheckCommit(t *testing.T) {
+	rc := &RepoConfig{Path: "/src/app/.gitego.yaml", AllowedEmailDomains: []string{"corp.com"}, RequireSigning: true}
+
+	if v := rc.CheckCommit("me@corp.com", true); len(v) != 0 {
+		t.Errorf("Expected no violations, got %v", v)
+	}
+
+	violations := rc.CheckCommit("me@gmail.com", false)
+	if len(violations) != 2 || !strings.Contains(violations[0].String(), "/src/app/.gitego.yaml") {
+		t.Errorf("Expected 2 violations naming the file, got %v", violations)
+	}
+}
+
+// TestGetActiveProfileForCurrentDir_RepoConfig verifies that a trusted profile hint is used
+// when no auto-rule matches, and that an untrusted one is ignored.
+func TestGetActiveProfileForCurrentDir_RepoConfig(t *testing.T) {
+	root := setupTestRepo(t, "profile: work\n")
+
+	originalWd, _ := os.Getwd()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = os.Chdir(originalWd) }()
+
+	cfg := &Config{
+		Profiles:      map[string]*Profile{"work": {}, "personal": {}},
+		ActiveProfile: "personal",
+	}
+
+	if name, _ := cfg.GetActiveProfileForCurrentDir(); name != "personal" {
+		t.Errorf("Expected an untrusted hint to be ignored, got '%s'", name)
+	}
+
+	rc, err := FindRepoConfig(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rc.Trust(); err != nil {
+		t.Fatal(err)
+	}
+
+	name, source := cfg.GetActiveProfileForCurrentDir()
+	if name != "work" || !strings.Contains(source, RepoConfigName) {
+		t.Errorf("Expected the trusted hint 'work', got '%s' (%s)", name, source)
+	}
+}
@@ -0,0 +1,222 @@
+// config/signing.go
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Signing modes a profile can use for commits and tags.
+const (
+	SigningGPG  = "gpg"
+	SigningSSH  = "ssh"
+	SigningX509 = "x509"
+	SigningNone = "none"
+)
+
+// SigningModes lists every supported signing mode.
+var SigningModes = []string{SigningGPG, SigningSSH, SigningX509, SigningNone}
+
+// gpgFormats maps gitego signing modes to git's gpg.format values.
+var gpgFormats = map[string]string{
+	SigningGPG:  "openpgp",
+	SigningSSH:  "ssh",
+	SigningX509: "x509",
+}
+
+// GitSetting is a single git config key and value. An empty value means the
+// key should be unset.
+type GitSetting struct {
+	Key   string
+	Value string
+}
+
+// SigningMode returns the profile's signing mode. Profiles created before signing
+// modes existed only have a signing key, so the mode is inferred from it: SSH keys
+// are recognized by their path or key prefix, anything else is treated as a GPG key ID.
+func (p *Profile) SigningMode() string {
+	if p.SigningFormat != "" {
+		return p.SigningFormat
+	}
+
+	if p.SigningKey == "" {
+		return SigningNone
+	}
+
+	if isSSHSigningKey(p.SigningKey) {
+		return SigningSSH
+	}
+
+	return SigningGPG
+}
+
+func isSSHSigningKey(key string) bool {
+	return strings.HasPrefix(key, "key::") ||
+		strings.HasPrefix(key, "ssh-") ||
+		strings.HasSuffix(key, ".pub") ||
+		strings.ContainsAny(key, `/\`)
+}
+
+// IsValidSigningMode reports whether mode is one of SigningModes.
+func IsValidSigningMode(mode string) bool {
+	for _, m := range SigningModes {
+		if m == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SigningSettings returns the git config entries that apply a profile's signing mode.
+// Only a profile whose signing format is explicitly "none" turns signing off; for one
+// that merely has no signing key, such as a profile created before signing modes existed,
+// the key and format are unset and commit.gpgsign and tag.gpgsign are left as they are.
+func SigningSettings(profile *Profile) []GitSetting {
+	mode := profile.SigningMode()
+
+	// GPG and x509 can pick a key from the committer identity, but SSH signing cannot.
+	if mode == SigningNone || (mode == SigningSSH && profile.SigningKey == "") {
+		settings := []GitSetting{
+			{Key: "user.signingkey"},
+			{Key: "gpg.format"},
+			{Key: "gpg.ssh.allowedSignersFile"},
+		}
+
+		if profile.SigningFormat != SigningNone {
+			return settings
+		}
+
+		return append(settings,
+			GitSetting{Key: "commit.gpgsign", Value: "false"},
+			GitSetting{Key: "tag.gpgsign", Value: "false"},
+		)
+	}
+
+	settings := []GitSetting{
+		{Key: "user.signingkey", Value: profile.SigningKey},
+		{Key: "gpg.format", Value: gpgFormats[mode]},
+	}
+
+	if mode == SigningSSH {
+		settings = append(settings, GitSetting{
+			Key: "gpg.ssh.allowedSignersFile", Value: filepath.ToSlash(allowedSignersPath),
+		})
+	} else {
+		settings = append(settings, GitSetting{Key: "gpg.ssh.allowedSignersFile"})
+	}
+
+	return append(settings,
+		GitSetting{Key: "commit.gpgsign", Value: "true"},
+		GitSetting{Key: "tag.gpgsign", Value: "true"},
+	)
+}
+
+// AllowedSignersPath returns the location of the allowed_signers file managed by gitego.
+func AllowedSignersPath() string {
+	return allowedSignersPath
+}
+
+// WriteAllowedSigners regenerates the allowed_signers file that git uses to verify
+// SSH signatures, with one line for every profile that signs with an SSH key.
+// Profiles whose public key cannot be read are skipped with a warning.
+func WriteAllowedSigners(cfg *Config) error {
+	names := make([]string, 0, len(cfg.Profiles))
+	for name, profile := range cfg.Profiles {
+		if profile.SigningMode() == SigningSSH && profile.SigningKey != "" {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	var b strings.Builder
+
+	b.WriteString("# This file is generated by gitego from your profiles' SSH signing keys.\n")
+
+	for _, name := range names {
+		profile := cfg.Profiles[name]
+
+		pubKey, err := SSHSigningPublicKey(profile.SigningKey)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not read signing key for profile '%s': %v\n", name, err)
+
+			continue
+		}
+
+		fmt.Fprintf(&b, "%s namespaces=\"git\" %s\n", profile.Email, pubKey)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(allowedSignersPath), dirPermissions); err != nil {
+		return fmt.Errorf("could not create allowed signers directory: %w", err)
+	}
+
+	return os.WriteFile(allowedSignersPath, []byte(b.String()), filePermissions)
+}
+
+// SSHSigningPublicKey returns the "<type> <base64>" public key for an SSH signing key,
+// which may be a literal key, a "key::" literal, or a path to either half of a keypair.
+func SSHSigningPublicKey(signingKey string) (string, error) {
+	literal := strings.TrimPrefix(signingKey, "key::")
+	if strings.HasPrefix(literal, "ssh-") || strings.HasPrefix(literal, "ecdsa-") || strings.HasPrefix(literal, "sk-") {
+		return firstTwoFields(literal), nil
+	}
+
+	path := ExpandHome(signingKey)
+	if !strings.HasSuffix(path, ".pub") {
+		path += ".pub"
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return firstTwoFields(strings.TrimSpace(string(data))), nil
+}
+
+// SSHSigningFingerprint returns the SHA256 fingerprint of an SSH signing key, in the
+// "SHA256:..." form git reports for SSH signatures.
+func SSHSigningFingerprint(signingKey string) (string, error) {
+	publicKey, err := SSHSigningPublicKey(signingKey)
+	if err != nil {
+		return "", err
+	}
+
+	parsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		return "", fmt.Errorf("invalid SSH public key: %w", err)
+	}
+
+	return ssh.FingerprintSHA256(parsed), nil
+}
+
+// firstTwoFields drops the comment from an authorized_keys style public key.
+func firstTwoFields(key string) string {
+	fields := strings.Fields(key)
+	if len(fields) > 2 {
+		fields = fields[:2]
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// ExpandHome replaces a leading ~/ with the user's home directory.
+func ExpandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,182 @@
+// config/signing_test.go
+
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// TestSigningMode verifies explicit and inferred signing modes.
+func TestSigningMode(t *testing.T) {
+	tests := []struct {
+		profile  Profile
+		expected string
+	}{
+		{Profile{}, SigningNone},
+		{Profile{SigningKey: "ABCD1234"}, SigningGPG},
+		{Profile{SigningKey: "~/.ssh/id_ed25519.pub"}, SigningSSH},
+		{Profile{SigningKey: "key::ssh-ed25519 AAAA"}, SigningSSH},
+		{Profile{SigningKey: "ABCD1234", SigningFormat: SigningX509}, SigningX509},
+	}
+
+	for _, tt := range tests {
+		if got := tt.profile.SigningMode(); got != tt.expected {
+			t.Errorf("SigningMode() for key '%s' = '%s', expected '%s'", tt.profile.SigningKey, got, tt.expected)
+		}
+	}
+}
+
+// TestWriteAllowedSigners verifies that SSH signing profiles are listed with their email
+// and public key, and that GPG profiles are left out.
+func TestWriteAllowedSigners(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalAllowedSignersPath := allowedSignersPath
+	allowedSignersPath = filepath.Join(tempDir, "allowed_signers")
+
+	defer func() {
+		allowedSignersPath = originalAllowedSignersPath
+	}()
+
+	keyPath := filepath.Join(tempDir, "id_ed25519")
+	if err := os.WriteFile(keyPath+".pub", []byte("ssh-ed25519 AAAAwork work@example.com\n"), 0644); err != nil {
+		t.Fatalf("Failed to write public key: %v", err)
+	}
+
+	cfg := &Config{
+		Profiles: map[string]*Profile{
+			"work":     {Email: "work@example.com", SigningKey: keyPath, SigningFormat: SigningSSH},
+			"literal":  {Email: "me@example.com", SigningKey: "key::ssh-ed25519 AAAAme"},
+			"gpg-user": {Email: "gpg@example.com", SigningKey: "ABCD1234"},
+		},
+	}
+
+	if err := WriteAllowedSigners(cfg); err != nil {
+		t.Fatalf("WriteAllowedSigners returned an error: %v", err)
+	}
+
+	content, err := os.ReadFile(allowedSignersPath)
+	if err != nil {
+		t.Fatalf("Failed to read allowed_signers: %v", err)
+	}
+
+	contentStr := string(content)
+
+	for _, line := range []string{
+		`work@example.com namespaces="git" ssh-ed25519 AAAAwork`,
+		`me@example.com namespaces="git" ssh-ed25519 AAAAme`,
+	} {
+		if !strings.Contains(contentStr, line+"\n") {
+			t.Errorf("Expected allowed_signers to contain %q, but got:\n%s", line, contentStr)
+		}
+	}
+
+	if strings.Contains(contentStr, "gpg@example.com") {
+		t.Errorf("Did not expect a GPG profile in allowed_signers, but got:\n%s", contentStr)
+	}
+}
+
+// TestEnsureProfileGitconfig_SSHSigning tests that an SSH signing profile enables
+// SSH signing and points git at the allowed_signers file.
+func TestEnsureProfileGitconfig_SSHSigning(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalProfilesDir := profilesDir
+	profilesDir = tempDir
+
+	defer func() {
+		profilesDir = originalProfilesDir
+	}()
+
+	profile := &Profile{
+		Name:          "Test User",
+		Email:         "test@example.com",
+		SigningKey:    "~/.ssh/id_ed25519.pub",
+		SigningFormat: SigningSSH,
+	}
+
+	if err := EnsureProfileGitconfig("signer", profile); err != nil {
+		t.Fatalf("EnsureProfileGitconfig returned an error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "signer.gitconfig"))
+	if err != nil {
+		t.Fatalf("Failed to read generated gitconfig file: %v", err)
+	}
+
+	contentStr := string(content)
+
+	for _, want := range []string{
+		"[gpg]\n    format = ssh\n",
+		"[gpg \"ssh\"]\n    allowedSignersFile = " + filepath.ToSlash(allowedSignersPath) + "\n",
+		"[commit]\n    gpgsign = true\n",
+		"signingkey = ~/.ssh/id_ed25519.pub",
+	} {
+		if !strings.Contains(contentStr, want) {
+			t.Errorf("Expected gitconfig to contain %q, but got:\n%s", want, contentStr)
+		}
+	}
+}
+
+// TestSigningSettings_NoKey verifies that only an explicit "none" format turns signing off,
+// so switching to a profile without a signing key doesn't disable it globally.
+func TestSigningSettings_NoKey(t *testing.T) {
+	gpgsign := func(settings []GitSetting) []string {
+		var values []string
+
+		for _, setting := range settings {
+			if strings.HasSuffix(setting.Key, ".gpgsign") {
+				values = append(values, setting.Key+"="+setting.Value)
+			}
+		}
+
+		return values
+	}
+
+	for _, profile := range []*Profile{{}, {SigningFormat: SigningSSH}} {
+		settings := SigningSettings(profile)
+		if values := gpgsign(settings); values != nil {
+			t.Errorf("Expected %+v to leave gpgsign alone, got %v", profile, values)
+		}
+
+		if settings[0].Key != "user.signingkey" || settings[0].Value != "" {
+			t.Errorf("Expected %+v to unset user.signingkey, got %v", profile, settings)
+		}
+	}
+
+	values := gpgsign(SigningSettings(&Profile{SigningFormat: SigningNone}))
+	if strings.Join(values, " ") != "commit.gpgsign=false tag.gpgsign=false" {
+		t.Errorf("Expected an explicit none to turn signing off, got %v", values)
+	}
+}
+
+// TestSSHSigningFingerprint verifies that literal keys report the fingerprint git shows.
+func TestSSHSigningFingerprint(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	literal := "key::" + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+
+	fingerprint, err := SSHSigningFingerprint(literal)
+	if err != nil {
+		t.Fatalf("SSHSigningFingerprint failed: %v", err)
+	}
+
+	if fingerprint != ssh.FingerprintSHA256(sshPub) {
+		t.Errorf("Expected fingerprint %s, got %s", ssh.FingerprintSHA256(sshPub), fingerprint)
+	}
+}
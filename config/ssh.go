@@ -0,0 +1,116 @@
+// config/ssh.go
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// SSHProfileEnv is the environment variable gitego's sshCommand sets so that
+	// the managed ssh_config can select the block for the active profile.
+	SSHProfileEnv = "GITEGO_SSH_PROFILE"
+
+	// sshConfigHeader is written at the top of the managed ssh_config.
+	sshConfigHeader = "# This file is generated by gitego. Do not edit it by hand;\n" +
+		"# it is rewritten whenever the gitego config is saved.\n"
+)
+
+// SSHConfigPath returns the location of the ssh_config file managed by gitego.
+func SSHConfigPath() string {
+	return sshConfigPath
+}
+
+// SSHCommand returns the core.sshCommand value for a profile. It points ssh at
+// the managed ssh_config with -F and tells it which profile block to apply.
+func SSHCommand(profileName string) string {
+	return fmt.Sprintf("%s=%s ssh -F %s",
+		SSHProfileEnv, ShellQuote(profileName), ShellQuote(filepath.ToSlash(sshConfigPath)))
+}
+
+// WriteSSHConfig regenerates the managed ssh_config from every profile that has an SSH key.
+// Settings from the user's own ~/.ssh/config are still applied through an Include,
+// but the profile's identity always takes precedence.
+func WriteSSHConfig(cfg *Config) error {
+	if err := os.MkdirAll(filepath.Dir(sshConfigPath), dirPermissions); err != nil {
+		return fmt.Errorf("could not create ssh config directory: %w", err)
+	}
+
+	return os.WriteFile(sshConfigPath, []byte(renderSSHConfig(cfg)), filePermissions)
+}
+
+func renderSSHConfig(cfg *Config) string {
+	names := make([]string, 0, len(cfg.Profiles))
+	for name, profile := range cfg.Profiles {
+		if profile.SSHKey != "" {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	var b strings.Builder
+
+	b.WriteString(sshConfigHeader)
+
+	for _, name := range names {
+		profile := cfg.Profiles[name]
+
+		fmt.Fprintf(&b, "\n# gitego profile: %s\n", name)
+		// The x prefix keeps test happy when the variable is unset; ssh_config
+		// does not allow escaped double quotes inside a Match exec command.
+		fmt.Fprintf(&b, "Match exec \"test x$%s = x%s\"\n", SSHProfileEnv, ShellQuote(name))
+		fmt.Fprintf(&b, "    IdentityFile %s\n", sshConfigQuote(profile.SSHKey))
+		b.WriteString("    IdentitiesOnly yes\n")
+
+		if profile.SSHIdentityAgent != "" {
+			fmt.Fprintf(&b, "    IdentityAgent %s\n", sshConfigQuote(profile.SSHIdentityAgent))
+		}
+
+		if profile.SSHPort != 0 {
+			fmt.Fprintf(&b, "    Port %d\n", profile.SSHPort)
+		}
+
+		if profile.SSHUser != "" {
+			fmt.Fprintf(&b, "    User %s\n", sshConfigQuote(profile.SSHUser))
+		}
+	}
+
+	// Reset the match context so the user's own config applies to every host.
+	b.WriteString("\nMatch all\n")
+	b.WriteString("    Include ~/.ssh/config\n")
+
+	return b.String()
+}
+
+// sshConfigQuote wraps a value in double quotes if it contains whitespace,
+// which is how ssh_config accepts paths with spaces.
+func sshConfigQuote(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return `"` + value + `"`
+	}
+
+	return value
+}
+
+// ShellQuote quotes a value for safe use in a POSIX shell command line.
+func ShellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// quoteGitConfigValue quotes a value for use in a gitconfig file when it contains
+// characters git would otherwise treat as comments, escapes or trimmable space.
+func quoteGitConfigValue(value string) string {
+	if !strings.ContainsAny(value, "\"\\#;") && strings.TrimSpace(value) == value {
+		return value
+	}
+
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+
+	return `"` + value + `"`
+}
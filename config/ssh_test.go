@@ -0,0 +1,92 @@
+// config/ssh_test.go
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRenderSSHConfig verifies the per-profile blocks of the managed ssh_config.
+func TestRenderSSHConfig(t *testing.T) {
+	cfg := &Config{
+		Profiles: map[string]*Profile{
+			"work": {
+				Name:             "Work User",
+				Email:            "work@example.com",
+				SSHKey:           "/home/user/my keys/id_work",
+				SSHPort:          2222,
+				SSHUser:          "git",
+				SSHIdentityAgent: "none",
+			},
+			"personal": {Name: "Personal User", Email: "me@example.com"},
+		},
+	}
+
+	content := renderSSHConfig(cfg)
+
+	expected := []string{
+		"Match exec \"test x$GITEGO_SSH_PROFILE = x'work'\"",
+		`IdentityFile "/home/user/my keys/id_work"`,
+		"IdentitiesOnly yes",
+		"IdentityAgent none",
+		"Port 2222",
+		"User git",
+		"Include ~/.ssh/config",
+	}
+	for _, line := range expected {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected ssh_config to contain %q, but got:\n%s", line, content)
+		}
+	}
+
+	if strings.Contains(content, "personal") {
+		t.Errorf("Did not expect a block for a profile without an SSH key, but got:\n%s", content)
+	}
+
+	if strings.Index(content, "Match all") < strings.Index(content, "IdentityFile") {
+		t.Errorf("Expected the user's config to be included after the profile blocks, but got:\n%s", content)
+	}
+}
+
+// TestEnsureProfileGitconfig_WithSSHKey tests that a profile with an SSH key points
+// core.sshCommand at the managed ssh_config instead of passing -i.
+func TestEnsureProfileGitconfig_WithSSHKey(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalProfilesDir := profilesDir
+	originalSSHConfigPath := sshConfigPath
+	profilesDir = tempDir
+	sshConfigPath = filepath.Join(tempDir, "ssh_config")
+
+	defer func() {
+		profilesDir = originalProfilesDir
+		sshConfigPath = originalSSHConfigPath
+	}()
+
+	profile := &Profile{
+		Name:   "Test User",
+		Email:  "test@example.com",
+		SSHKey: "~/.ssh/id_work",
+	}
+
+	if err := EnsureProfileGitconfig("work", profile); err != nil {
+		t.Fatalf("EnsureProfileGitconfig returned an error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "work.gitconfig"))
+	if err != nil {
+		t.Fatalf("Failed to read generated gitconfig file: %v", err)
+	}
+
+	contentStr := string(content)
+	if strings.Contains(contentStr, "ssh -i") {
+		t.Errorf("Did not expect 'ssh -i' in gitconfig, but got:\n%s", contentStr)
+	}
+
+	if !strings.Contains(contentStr, "-F '"+filepath.ToSlash(sshConfigPath)+"'") {
+		t.Errorf("Expected sshCommand to reference the managed ssh_config, but got:\n%s", contentStr)
+	}
+}
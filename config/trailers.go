@@ -0,0 +1,62 @@
+// config/trailers.go
+
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Sign-off modes for the Signed-off-by (DCO) trailer added by the commit-msg hook.
+const (
+	// SignoffAppend appends the profile's Signed-off-by trailer when it is missing.
+	SignoffAppend = "append"
+	// SignoffRequire rejects commit messages without the profile's Signed-off-by trailer.
+	SignoffRequire = "require"
+)
+
+// Trailer keys the commit-msg hook reads and writes.
+const (
+	SignedOffByTrailer  = "Signed-off-by"
+	CoAuthoredByTrailer = "Co-authored-by"
+)
+
+// SignoffModes lists the valid sign-off modes.
+var SignoffModes = []string{SignoffAppend, SignoffRequire}
+
+// IsValidSignoffMode reports whether mode is a known sign-off mode.
+func IsValidSignoffMode(mode string) bool {
+	return slices.Contains(SignoffModes, mode)
+}
+
+// Identity returns the profile's identity as Git writes it in trailers: "Name <email>".
+func (p *Profile) Identity() string {
+	return fmt.Sprintf("%s <%s>", p.Name, p.Email)
+}
+
+// SignoffForDir returns the profile expected in dir and the sign-off mode that applies
+// there: the matching rule's if it sets one, otherwise the profile's own.
+func (c *Config) SignoffForDir(dir string) (profileName, mode string) {
+	profileName, _ = c.GetProfileForDir(dir)
+
+	if rule := c.MatchingRule(dir); rule != nil && rule.Signoff != "" {
+		return profileName, rule.Signoff
+	}
+
+	if profile, exists := c.Profiles[profileName]; exists {
+		return profileName, profile.Signoff
+	}
+
+	return profileName, ""
+}
+
+// ParseTrailer splits a "Key: value" trailer line. It reports false for anything else.
+func ParseTrailer(line string) (key, value string, ok bool) {
+	key, value, ok = strings.Cut(line, ":")
+	if !ok || key == "" || strings.ContainsAny(key, " \t") {
+		return "", "", false
+	}
+
+	return key, strings.TrimSpace(value), true
+}
@@ -0,0 +1,63 @@
+// config/trailers_test.go
+
+package config
+
+import (
+	"testing"
+)
+
+// TestSignoffForDir verifies that a rule's sign-off mode overrides the profile's own.
+func TestSignoffForDir(t *testing.T) {
+	workDir := t.TempDir()
+	otherDir := t.TempDir()
+
+	cfg := &Config{
+		ActiveProfile: "personal",
+		Profiles: map[string]*Profile{
+			"work":     {Name: "Work User", Email: "work@corp.com", Signoff: SignoffAppend},
+			"personal": {Name: "Me", Email: "me@gmail.com"},
+		},
+		AutoRules: []*AutoRule{{Path: workDir, Profile: "work", Signoff: SignoffRequire}},
+	}
+
+	if profile, mode := cfg.SignoffForDir(workDir); profile != "work" || mode != SignoffRequire {
+		t.Errorf("SignoffForDir(rule dir) = %s, %s; expected work, %s", profile, mode, SignoffRequire)
+	}
+
+	cfg.AutoRules[0].Signoff = ""
+
+	if profile, mode := cfg.SignoffForDir(workDir); profile != "work" || mode != SignoffAppend {
+		t.Errorf("SignoffForDir(rule dir) = %s, %s; expected work, %s", profile, mode, SignoffAppend)
+	}
+
+	if profile, mode := cfg.SignoffForDir(otherDir); profile != "personal" || mode != "" {
+		t.Errorf("SignoffForDir(other dir) = %s, %s; expected personal and no mode", profile, mode)
+	}
+}
+
+// TestParseTrailer verifies that trailer lines are split into key and value.
+func TestParseTrailer(t *testing.T) {
+	tests := []struct {
+		line  string
+		key   string
+		value string
+		ok    bool
+	}{
+		{"Signed-off-by: Jane Doe <jane@corp.com>", "Signed-off-by", "Jane Doe <jane@corp.com>", true},
+		{"Co-authored-by:Sam <sam@corp.com>", "Co-authored-by", "Sam <sam@corp.com>", true},
+		{"Not a trailer: at all", "", "", false},
+		{"no colon", "", "", false},
+	}
+
+	for _, tt := range tests {
+		key, value, ok := ParseTrailer(tt.line)
+		if key != tt.key || value != tt.value || ok != tt.ok {
+			t.Errorf("ParseTrailer(%q) = %q, %q, %v; expected %q, %q, %v",
+				tt.line, key, value, ok, tt.key, tt.value, tt.ok)
+		}
+	}
+
+	if identity := (&Profile{Name: "Jane Doe", Email: "jane@corp.com"}).Identity(); identity != "Jane Doe <jane@corp.com>" {
+		t.Errorf("Identity() = %q", identity)
+	}
+}
@@ -0,0 +1,79 @@
+// config/uninstall.go
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dir returns gitego's own directory, which holds config.yaml and every file gitego generates.
+func Dir() string {
+	return filepath.Dir(gitegoConfigPath)
+}
+
+// KeysDir returns the directory holding SSH keys generated by 'gitego ssh-keygen'.
+func KeysDir() string {
+	return keysDir
+}
+
+// GeneratedPaths returns the files and directories gitego regenerates from config.yaml.
+// They can be removed without losing any configuration.
+func GeneratedPaths() []string {
+	return []string{profilesDir, sshConfigPath, allowedSignersPath, promptCachePath()}
+}
+
+// RefersToGitego reports whether a git config value points into gitego's directory.
+func RefersToGitego(value string) bool {
+	return strings.Contains(filepath.ToSlash(value), filepath.ToSlash(Dir())+"/")
+}
+
+// profilesDirPrefix matches the path of every generated profile gitconfig.
+func profilesDirPrefix() string {
+	return filepath.ToSlash(profilesDir) + "/"
+}
+
+// ListIncludeIfs returns the section headers of every includeIf block gitego added to
+// the global .gitconfig, for any profile.
+func ListIncludeIfs() ([]string, error) {
+	input, err := os.ReadFile(gitConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var headers []string
+
+	lines := strings.Split(string(input), "\n")
+	for i, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmedLine, "[includeIf") && isGitegoRule(lines, i, profilesDirPrefix()) {
+			headers = append(headers, trimmedLine)
+		}
+	}
+
+	return headers, nil
+}
+
+// RemoveAllIncludeIfs removes every includeIf block gitego added to the global .gitconfig,
+// including blocks left behind for profiles that no longer exist.
+func RemoveAllIncludeIfs() error {
+	input, err := os.ReadFile(gitConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	lines := strings.Split(string(input), "\n")
+	output := formatOutput(removeGitegoRules(lines, profilesDirPrefix()))
+
+	return os.WriteFile(gitConfigPath, []byte(output), filePermissions)
+}
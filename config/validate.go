@@ -0,0 +1,208 @@
+// config/validate.go
+
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net/mail"
+	"os"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FieldProfileName is the ValidationError field of a problem with the profile name itself.
+const FieldProfileName = "profile"
+
+// maxProfileNameLength keeps profile names usable as file names and keychain keys.
+const maxProfileNameLength = 64
+
+var (
+	profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	usernamePattern    = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._@+-]*$`)
+)
+
+// ValidationError describes one invalid value of a profile.
+type ValidationError struct {
+	// Profile is the name of the profile the value belongs to.
+	Profile string
+	// Field is the yaml name of the invalid field, or FieldProfileName.
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == FieldProfileName {
+		return fmt.Sprintf("%s is not a valid profile name: %s", displayValue(e.Value), e.Reason)
+	}
+
+	return fmt.Sprintf("profile '%s': %s %s %s", e.Profile, e.Field, displayValue(e.Value), e.Reason)
+}
+
+// Forceable reports whether the profile may be saved anyway when asked to. Profile names
+// never can, since they become part of file paths and keychain keys.
+func (e *ValidationError) Forceable() bool {
+	return e.Field != FieldProfileName
+}
+
+// ValidationErrors is the list of problems found in one or more profiles.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// displayValue quotes a value for an error message, escaping it if it holds characters
+// that would not show up in a terminal, such as a newline.
+func displayValue(value string) string {
+	if strings.IndexFunc(value, func(r rune) bool { return !unicode.IsPrint(r) }) >= 0 {
+		return strconv.Quote(value)
+	}
+
+	return "'" + value + "'"
+}
+
+// ValidateProfileName checks that name can be used as a profile name: it must start with a
+// letter or digit and contain only letters, digits, '.', '_' and '-'.
+func ValidateProfileName(name string) error {
+	if err := profileNameError(name); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// profileNameError is ValidateProfileName without the error interface, for ValidateProfile.
+func profileNameError(name string) *ValidationError {
+	reason := ""
+
+	switch {
+	case name == "":
+		reason = "it is empty"
+	case len(name) > maxProfileNameLength:
+		reason = fmt.Sprintf("it is longer than %d characters", maxProfileNameLength)
+	case !profileNamePattern.MatchString(name):
+		reason = "use letters, digits, '.', '_' and '-', starting with a letter or digit"
+	}
+
+	if reason == "" {
+		return nil
+	}
+
+	return &ValidationError{Profile: name, Field: FieldProfileName, Value: name, Reason: reason}
+}
+
+// ValidateProfile checks the profile name and the fields a profile sets itself; inherited
+// fields are left to the validation of the base profile they come from. Empty fields are
+// not checked. It returns nil if the profile is valid.
+func ValidateProfile(name string, p *Profile) ValidationErrors {
+	var errs ValidationErrors
+
+	if err := profileNameError(name); err != nil {
+		errs = append(errs, err)
+	}
+
+	check := func(field, value string, validate func(string) string) {
+		if value == "" || p.Inherits(field) {
+			return
+		}
+
+		if reason := validate(value); reason != "" {
+			errs = append(errs, &ValidationError{Profile: name, Field: field, Value: value, Reason: reason})
+		}
+	}
+
+	check("name", p.Name, validateUserName)
+	check("email", p.Email, validateEmail)
+	check("username", p.Username, validateUsername)
+	check("ssh_key", p.SSHKey, validateKeyFile)
+
+	if p.SigningMode() == SigningSSH {
+		check("signing_key", p.SigningKey, validateSigningKey)
+	}
+
+	return errs
+}
+
+// ValidateProfiles checks every profile of the config, in name order.
+func (c *Config) ValidateProfiles() ValidationErrors {
+	var errs ValidationErrors
+
+	for _, name := range slices.Sorted(maps.Keys(c.Profiles)) {
+		errs = append(errs, ValidateProfile(name, c.Profiles[name])...)
+	}
+
+	return errs
+}
+
+// validateUserName checks a user.name; git would silently drop the characters it rejects.
+func validateUserName(value string) string {
+	switch {
+	case strings.TrimSpace(value) == "":
+		return "is blank"
+	case strings.IndexFunc(value, unicode.IsControl) >= 0:
+		return "contains control characters"
+	case strings.ContainsAny(value, "<>"):
+		return "must not contain '<' or '>'"
+	}
+
+	return ""
+}
+
+// validateEmail checks for a bare address, such as "jane@example.com".
+func validateEmail(value string) string {
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value || !strings.Contains(value, "@") {
+		return "is not a valid email address"
+	}
+
+	return ""
+}
+
+// validateUsername checks a login username for a Git hosting service.
+func validateUsername(value string) string {
+	if !usernamePattern.MatchString(value) {
+		return "is not a valid username: use letters, digits, '.', '_', '-', '+' and '@'"
+	}
+
+	return ""
+}
+
+// validateKeyFile checks that a private key exists and, as ssh requires, that only its
+// owner can read it.
+func validateKeyFile(value string) string {
+	info, err := os.Stat(ExpandHome(value))
+
+	switch {
+	case os.IsNotExist(err):
+		return "does not exist"
+	case err != nil:
+		return fmt.Sprintf("cannot be read: %v", err)
+	case info.IsDir():
+		return "is a directory, not a key file"
+	case runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0:
+		return fmt.Sprintf("is accessible by other users (mode %#o); ssh will refuse it until you run 'chmod 600'",
+			info.Mode().Perm())
+	}
+
+	return ""
+}
+
+// validateSigningKey checks that the public key of an SSH signing key can be found.
+func validateSigningKey(value string) string {
+	if _, err := SSHSigningPublicKey(value); err != nil {
+		return "has no readable public key"
+	}
+
+	return ""
+}
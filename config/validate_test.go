@@ -0,0 +1,100 @@
+// config/validate_test.go
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateProfileName(t *testing.T) {
+	for _, name := range []string{"work", "client-abc", "gh.octocat", "A_1"} {
+		if err := ValidateProfileName(name); err != nil {
+			t.Errorf("Expected %q to be a valid profile name, got %v", name, err)
+		}
+	}
+
+	for _, name := range []string{"", ".", "..", "../x", "a b", "a/b", `a\b`, "-rf", "line\nbreak", strings.Repeat("x", 65)} {
+		if err := ValidateProfileName(name); err == nil {
+			t.Errorf("Expected %q to be rejected", name)
+		}
+	}
+
+	err := ValidateProfileName("a\nb")
+	if err == nil || !strings.Contains(err.Error(), `"a\nb" is not a valid profile name`) {
+		t.Errorf("Expected the newline to be escaped in the message, got %v", err)
+	}
+}
+
+// TestValidateProfile verifies that each invalid field is reported once, with its yaml
+// name, and that inherited fields are left to the base.
+func TestValidateProfile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	privateKey := filepath.Join(tempDir, "id_work")
+	openKey := filepath.Join(tempDir, "id_open")
+
+	for path, mode := range map[string]os.FileMode{privateKey: 0600, openKey: 0644} {
+		if err := os.WriteFile(path, []byte("key"), mode); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	valid := &Profile{Name: "Jane Doe", Email: "jane@corp.com", Username: "jane-doe", SSHKey: privateKey}
+	if errs := ValidateProfile("work", valid); errs != nil {
+		t.Errorf("Expected a valid profile, got %v", errs)
+	}
+
+	invalid := &Profile{
+		Name:       "Jane\nDoe",
+		Email:      "Jane <jane@corp.com>",
+		Username:   "jane doe",
+		SSHKey:     filepath.Join(tempDir, "missing"),
+		SigningKey: filepath.Join(tempDir, "missing.pub"),
+	}
+
+	errs := ValidateProfile("a b", invalid)
+
+	var fields []string
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+
+	expected := []string{FieldProfileName, "name", "email", "username", "ssh_key", "signing_key"}
+	if !slices.Equal(fields, expected) {
+		t.Errorf("Expected errors for %v, got %v", expected, errs)
+	}
+
+	if errs[0].Forceable() || !errs[1].Forceable() {
+		t.Error("Expected only the profile name error to be unforceable")
+	}
+
+	if !strings.Contains(errs.Error(), "profile 'a b': email 'Jane <jane@corp.com>' is not a valid email address") {
+		t.Errorf("Unexpected message: %v", errs)
+	}
+
+	if runtime.GOOS != "windows" {
+		errs = ValidateProfile("open", &Profile{SSHKey: openKey})
+		if len(errs) != 1 || !strings.Contains(errs[0].Reason, "chmod 600") {
+			t.Errorf("Expected a key readable by others to be rejected, got %v", errs)
+		}
+	}
+
+	cfg := &Config{Profiles: map[string]*Profile{
+		"base":  {Name: "Jane Doe", Email: "not-an-email"},
+		"child": {Extends: "base", Email: "jane@acme.com"},
+	}}
+	cfg.ResolveProfiles()
+
+	if errs := cfg.ValidateProfiles(); len(errs) != 1 || errs[0].Profile != "base" {
+		t.Errorf("Expected only the base's email to be reported, got %v", errs)
+	}
+}
@@ -0,0 +1,142 @@
+// errors/errors.go
+
+// Package errors defines the kinds of failure gitego commands report and the exit code
+// each kind maps to, so scripts can tell them apart. It also re-exports the standard
+// library functions, so packages importing it don't need both.
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind classifies an error for the exit code of the command that returns it.
+type Kind int
+
+// The kinds of error, in exit code order. The exit codes are documented in the README
+// and must not change.
+const (
+	// KindGeneral is any other failure, including checks such as 'gitego doctor' that
+	// found problems.
+	KindGeneral Kind = iota + 1
+	// KindInvalidInput is a bad argument, flag value or profile field, or a change the
+	// team policy forbids.
+	KindInvalidInput
+	// KindNotFound is a profile, rule, file or repository that doesn't exist.
+	KindNotFound
+	// KindAlreadyExists is a profile, rule or file that would be overwritten.
+	KindAlreadyExists
+	// KindIO is a failure to read or write a file or to run git.
+	KindIO
+	// KindKeyringUnavailable is a failure to reach the OS keychain.
+	KindKeyringUnavailable
+)
+
+// ExitCodes maps each kind to the exit code of a command failing with it. Success is 0.
+var ExitCodes = map[Kind]int{
+	KindGeneral:            1,
+	KindInvalidInput:       2,
+	KindNotFound:           3,
+	KindAlreadyExists:      4,
+	KindIO:                 5,
+	KindKeyringUnavailable: 6,
+}
+
+// Error is an error of a known kind. Hint, if set, tells the user how to fix it.
+type Error struct {
+	Kind    Kind
+	Message string
+	Hint    string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithHint sets the error's hint and returns the error.
+func (e *Error) WithHint(format string, args ...any) *Error {
+	e.Hint = fmt.Sprintf(format, args...)
+
+	return e
+}
+
+// NotFound returns a KindNotFound error.
+func NotFound(format string, args ...any) *Error {
+	return &Error{Kind: KindNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+// AlreadyExists returns a KindAlreadyExists error.
+func AlreadyExists(format string, args ...any) *Error {
+	return &Error{Kind: KindAlreadyExists, Message: fmt.Sprintf(format, args...)}
+}
+
+// InvalidInput returns a KindInvalidInput error.
+func InvalidInput(format string, args ...any) *Error {
+	return &Error{Kind: KindInvalidInput, Message: fmt.Sprintf(format, args...)}
+}
+
+// IO returns a KindIO error wrapping err.
+func IO(err error, format string, args ...any) *Error {
+	return &Error{Kind: KindIO, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// KeyringUnavailable returns a KindKeyringUnavailable error wrapping err.
+func KeyringUnavailable(err error, format string, args ...any) *Error {
+	return &Error{Kind: KindKeyringUnavailable, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// General returns a KindGeneral error wrapping err, which may be nil.
+func General(err error, format string, args ...any) *Error {
+	return &Error{Kind: KindGeneral, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// KindOf returns the kind of the first Error in err's chain. Errors of no known kind
+// are KindGeneral.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+
+	return KindGeneral
+}
+
+// ExitCode returns the exit code for err: 0 for nil, otherwise the code of its kind.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	return ExitCodes[KindOf(err)]
+}
+
+// HintOf returns the hint of the first Error in err's chain that has one.
+func HintOf(err error) string {
+	var e *Error
+	for errors.As(err, &e) {
+		if e.Hint != "" {
+			return e.Hint
+		}
+
+		err = e.Err
+	}
+
+	return ""
+}
+
+// New, Is, As and Join are the standard library functions.
+var (
+	New  = errors.New
+	Is   = errors.Is
+	As   = errors.As
+	Join = errors.Join
+)
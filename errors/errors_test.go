@@ -0,0 +1,59 @@
+// errors/errors_test.go
+
+package errors
+
+import (
+	"fmt"
+	"io/fs"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{nil, 0},
+		{New("plain"), 1},
+		{General(nil, "3 problem(s) found"), 1},
+		{InvalidInput("bad flag"), 2},
+		{NotFound("profile 'work' not found"), 3},
+		{AlreadyExists("profile 'work' already exists"), 4},
+		{IO(fs.ErrPermission, "could not save configuration"), 5},
+		{KeyringUnavailable(New("no dbus"), "could not store token"), 6},
+		// The kind of a wrapped Error is kept.
+		{fmt.Errorf("rename: %w", NotFound("profile 'work' not found")), 3},
+	}
+
+	for _, tt := range tests {
+		if code := ExitCode(tt.err); code != tt.expected {
+			t.Errorf("ExitCode(%v) = %d, expected %d", tt.err, code, tt.expected)
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	err := IO(fs.ErrNotExist, "could not read %s", "bundle.yaml").WithHint("Check the path.")
+
+	if err.Error() != "could not read bundle.yaml: file does not exist" {
+		t.Errorf("Unexpected message: %v", err)
+	}
+
+	if !Is(err, fs.ErrNotExist) {
+		t.Error("Expected the cause to be unwrapped")
+	}
+
+	if hint := HintOf(fmt.Errorf("import: %w", err)); hint != "Check the path." {
+		t.Errorf("Expected the hint of a wrapped error, got %q", hint)
+	}
+
+	// The hint of an Error further down the chain is found too.
+	outer := General(NotFound("profile 'work' not found").WithHint("Run 'gitego list'."), "could not switch")
+	if hint := HintOf(outer); hint != "Run 'gitego list'." {
+		t.Errorf("Expected the inner hint, got %q", hint)
+	}
+
+	if KindOf(outer) != KindGeneral {
+		t.Errorf("Expected the outermost kind, got %v", KindOf(outer))
+	}
+}
@@ -3,15 +3,15 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/bgreenwell/gitego/cmd"
+	"github.com/bgreenwell/gitego/errors"
 )
 
 func main() {
+	// Execute has already printed the error.
 	if err := cmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Whoops. There was an error while executing your command: %s", err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
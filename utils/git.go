@@ -58,3 +58,222 @@ func UnsetGlobalGitConfig(key string) error {
 
 	return nil
 }
+
+// GetGlobalGitConfigAll runs 'git config --global --get-all <key>' and returns every value.
+// A key that is not set yields no values and no error.
+func GetGlobalGitConfigAll(key string) ([]string, error) {
+	cmd := execCommand("git", "config", "--global", "--get-all", key)
+
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("git command failed: %w", err)
+	}
+
+	var values []string
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			values = append(values, line)
+		}
+	}
+
+	return values, nil
+}
+
+// ReplaceGlobalGitConfigAll replaces every value of a multi-valued key in the global
+// .gitconfig with values, in order.
+func ReplaceGlobalGitConfigAll(key string, values []string) error {
+	cmd := execCommand("git", "config", "--global", "--unset-all", key)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		// Status 5 means the key wasn't set, which is fine.
+		if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 5 {
+			return fmt.Errorf("git command failed: %w\nOutput: %s", err, string(output))
+		}
+	}
+
+	for _, value := range values {
+		cmd := execCommand("git", "config", "--global", "--add", key, value)
+
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git command failed: %w\nOutput: %s", err, string(output))
+		}
+	}
+
+	return nil
+}
+
+// GetRemoteURLs returns the URLs of every remote of the current repository.
+// Outside a repository, or with no remotes, it returns no URLs and no error.
+func GetRemoteURLs() ([]string, error) {
+	return remoteURLs(execCommand("git", "config", "--get-regexp", `^remote\..*\.url$`))
+}
+
+// GetRepoRemoteURLs returns the URLs of every remote of the repository in dir.
+func GetRepoRemoteURLs(dir string) ([]string, error) {
+	return remoteURLs(execCommand("git", "-C", dir, "config", "--get-regexp", `^remote\..*\.url$`))
+}
+
+func remoteURLs(cmd *exec.Cmd) ([]string, error) {
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("git command failed: %w", err)
+	}
+
+	var urls []string
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if _, url, found := strings.Cut(line, " "); found {
+			urls = append(urls, strings.TrimSpace(url))
+		}
+	}
+
+	return urls, nil
+}
+
+// GetRepoGitConfig runs 'git config <key>' in the repository in dir, resolving the value
+// from local > global > system like GetEffectiveGitConfig. A key that is not set yields
+// an empty value and no error.
+func GetRepoGitConfig(dir, key string) (string, error) {
+	return repoGitConfig(execCommand("git", "-C", dir, "config", key))
+}
+
+// GetLocalGitConfig runs 'git config --local <key>' in the repository in dir, returning
+// only a value set in the repository's own .git/config.
+func GetLocalGitConfig(dir, key string) (string, error) {
+	return repoGitConfig(execCommand("git", "-C", dir, "config", "--local", key))
+}
+
+// GitConfigEntry is a configuration value together with where git found it.
+type GitConfigEntry struct {
+	Scope  string
+	Origin string
+	Key    string
+	Value  string
+}
+
+// GetGitConfigOrigins returns every user.* value git sees in dir, in the order git reads
+// them, with the scope and file each comes from. The last value of a key is the one in
+// effect. A directory with no user.* values yields no entries and no error.
+func GetGitConfigOrigins(dir string) ([]GitConfigEntry, error) {
+	cmd := execCommand("git", "-C", dir, "config", "--show-scope", "--show-origin", "--get-regexp", `^user\.`)
+
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("git command failed: %w", err)
+	}
+
+	var entries []GitConfigEntry
+
+	for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		key, value, _ := strings.Cut(fields[2], " ")
+		entries = append(entries, GitConfigEntry{Scope: fields[0], Origin: fields[1], Key: key, Value: value})
+	}
+
+	return entries, nil
+}
+
+// UnsetLocalGitConfig runs 'git config --local --unset-all <key>' in the repository in dir.
+// If the key is not set, git exits with status code 5; this is ignored.
+func UnsetLocalGitConfig(dir, key string) error {
+	cmd := execCommand("git", "-C", dir, "config", "--local", "--unset-all", key)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 5 {
+			return nil
+		}
+
+		return fmt.Errorf("git command failed: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+func repoGitConfig(cmd *exec.Cmd) (string, error) {
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("git command failed: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetAuthorIdent runs 'git var GIT_AUTHOR_IDENT' and returns the name and email Git will
+// record as the author of the next commit, honoring GIT_AUTHOR_NAME and GIT_AUTHOR_EMAIL.
+func GetAuthorIdent() (name, email string, err error) {
+	output, err := execCommand("git", "var", "GIT_AUTHOR_IDENT").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("git command failed: %w", err)
+	}
+
+	// The identity has the form "Name <email> timestamp timezone".
+	ident := strings.TrimSpace(string(output))
+
+	start := strings.Index(ident, "<")
+	end := strings.LastIndex(ident, ">")
+
+	if start < 0 || end < start {
+		return "", "", fmt.Errorf("unexpected author identity: %q", ident)
+	}
+
+	return strings.TrimSpace(ident[:start]), ident[start+1 : end], nil
+}
+
+// ParseTrailers runs 'git interpret-trailers --parse' on a commit message file and returns
+// its trailers as "Key: value" lines.
+func ParseTrailers(messageFile string) ([]string, error) {
+	output, err := execCommand("git", "interpret-trailers", "--parse", messageFile).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git command failed: %w", err)
+	}
+
+	var trailers []string
+
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			trailers = append(trailers, line)
+		}
+	}
+
+	return trailers, nil
+}
+
+// AddTrailers runs 'git interpret-trailers --in-place' to add "Key: value" trailers to a
+// commit message file. A trailer the message already has is not added again.
+func AddTrailers(messageFile string, trailers []string) error {
+	args := []string{"interpret-trailers", "--in-place", "--if-exists", "addIfDifferent"}
+	for _, trailer := range trailers {
+		args = append(args, "--trailer", trailer)
+	}
+
+	cmd := execCommand("git", append(args, messageFile)...)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git command failed: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
@@ -52,6 +52,117 @@ func TestSetGlobalGitConfig(t *testing.T) {
 	}
 }
 
+func TestReplaceGlobalGitConfigAll(t *testing.T) {
+	originalExecCommand := execCommand
+	execCommand = mockExecCommand
+
+	defer func() { execCommand = originalExecCommand }()
+
+	if err := ReplaceGlobalGitConfigAll("credential.helper", []string{"", "!gitego credential"}); err != nil {
+		t.Errorf("expected no error, but got %v", err)
+	}
+}
+
+func TestGetRemoteURLs(t *testing.T) {
+	originalExecCommand := execCommand
+	execCommand = mockExecCommand
+
+	defer func() { execCommand = originalExecCommand }()
+
+	urls, err := GetRemoteURLs()
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	expected := []string{"git@github.com:corp/app.git", "https://github.com/upstream/app"}
+	if len(urls) != len(expected) || urls[0] != expected[0] || urls[1] != expected[1] {
+		t.Errorf("expected %v, but got %v", expected, urls)
+	}
+}
+
+func TestGetRepoGitConfig(t *testing.T) {
+	originalExecCommand := execCommand
+	execCommand = mockExecCommand
+
+	defer func() { execCommand = originalExecCommand }()
+
+	email, err := GetRepoGitConfig("/src/app", "user.email")
+	if err != nil || email != "test@example.com" {
+		t.Errorf("expected 'test@example.com', but got %q (%v)", email, err)
+	}
+
+	local, err := GetLocalGitConfig("/src/app", "user.email")
+	if err != nil || local != "local@example.com" {
+		t.Errorf("expected 'local@example.com', but got %q (%v)", local, err)
+	}
+
+	if err := UnsetLocalGitConfig("/src/app", "user.email"); err != nil {
+		t.Errorf("expected no error, but got %v", err)
+	}
+
+	urls, err := GetRepoRemoteURLs("/src/app")
+	if err != nil || len(urls) != 2 {
+		t.Errorf("expected 2 remote URLs, but got %v (%v)", urls, err)
+	}
+}
+
+func TestGetGitConfigOrigins(t *testing.T) {
+	originalExecCommand := execCommand
+	execCommand = mockExecCommand
+
+	defer func() { execCommand = originalExecCommand }()
+
+	entries, err := GetGitConfigOrigins("/src/app")
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, but got %v", entries)
+	}
+
+	last := entries[1]
+	if last.Scope != "local" || last.Origin != "file:.git/config" || last.Key != "user.email" || last.Value != "work@corp.com" {
+		t.Errorf("unexpected entry: %+v", last)
+	}
+}
+
+func TestGetAuthorIdent(t *testing.T) {
+	originalExecCommand := execCommand
+	execCommand = mockExecCommand
+
+	defer func() { execCommand = originalExecCommand }()
+
+	name, email, err := GetAuthorIdent()
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	if name != "Jane Doe" || email != "jane@corp.com" {
+		t.Errorf("expected 'Jane Doe' <jane@corp.com>, but got '%s' <%s>", name, email)
+	}
+}
+
+func TestTrailers(t *testing.T) {
+	originalExecCommand := execCommand
+	execCommand = mockExecCommand
+
+	defer func() { execCommand = originalExecCommand }()
+
+	trailers, err := ParseTrailers("COMMIT_EDITMSG")
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	if len(trailers) != 2 || trailers[0] != "Signed-off-by: Jane Doe <jane@corp.com>" {
+		t.Errorf("unexpected trailers: %q", trailers)
+	}
+
+	if err := AddTrailers("COMMIT_EDITMSG", []string{"Co-authored-by: Sam Roe <sam@corp.com>"}); err != nil {
+		t.Errorf("expected no error, but got %v", err)
+	}
+}
+
 // TestHelperProcess remains the same.
 func TestHelperProcess(t *testing.T) {
 	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
@@ -69,6 +180,18 @@ func TestHelperProcess(t *testing.T) {
 		return
 	}
 
+	if handleGitTrailerCommands(args) {
+		return
+	}
+
+	if handleSSHCommands(args) {
+		return
+	}
+
+	if handleGPGCommands(args) {
+		return
+	}
+
 	fmt.Fprintf(os.Stderr, "unhandled mock command: %s\n", strings.Join(args, " "))
 	os.Exit(1)
 }
@@ -87,10 +210,29 @@ func extractCommandArgs() []string {
 }
 
 func handleGitConfigCommands(args []string) bool {
+	// 'git -C <dir> config ...' behaves like 'git config ...' here.
+	if len(args) > 3 && args[0] == "git" && args[1] == "-C" {
+		args = append([]string{"git"}, args[3:]...)
+	}
+
 	if len(args) < 2 || args[0] != "git" || args[1] != "config" {
 		return false
 	}
 
+	if len(args) == 4 && args[2] == "--local" && args[3] == "user.email" {
+		fmt.Fprint(os.Stdout, "local@example.com\n")
+
+		return true
+	}
+
+	if len(args) == 5 && args[2] == "--local" && args[3] == "--unset-all" {
+		return true
+	}
+
+	if len(args) >= 5 && args[2] == "--global" && (args[3] == "--unset-all" || args[3] == "--add") {
+		return true
+	}
+
 	if len(args) == 3 && args[2] == "user.email" {
 		if _, err := fmt.Fprint(os.Stdout, "test@example.com"); err != nil {
 			panic("Failed to write to stdout: " + err.Error())
@@ -103,5 +245,37 @@ func handleGitConfigCommands(args []string) bool {
 		return true
 	}
 
+	if len(args) == 6 && args[2] == "--show-scope" && args[4] == "--get-regexp" {
+		fmt.Fprint(os.Stdout, "global\tfile:/home/me/.gitconfig\tuser.email me@gmail.com\n"+
+			"local\tfile:.git/config\tuser.email work@corp.com\n")
+
+		return true
+	}
+
+	if len(args) == 4 && args[2] == "--get-regexp" {
+		fmt.Fprint(os.Stdout, "remote.origin.url git@github.com:corp/app.git\n"+
+			"remote.upstream.url https://github.com/upstream/app\n")
+
+		return true
+	}
+
 	return false
 }
+
+func handleGitTrailerCommands(args []string) bool {
+	if len(args) < 3 || args[0] != "git" {
+		return false
+	}
+
+	switch {
+	case args[1] == "var" && args[2] == "GIT_AUTHOR_IDENT":
+		fmt.Fprint(os.Stdout, "Jane Doe <jane@corp.com> 1700000000 +0100\n")
+	case args[1] == "interpret-trailers" && args[2] == "--parse":
+		fmt.Fprint(os.Stdout, "Signed-off-by: Jane Doe <jane@corp.com>\nReviewed-by: Sam Roe <sam@corp.com>\n")
+	case args[1] == "interpret-trailers" && args[2] == "--in-place":
+	default:
+		return false
+	}
+
+	return true
+}
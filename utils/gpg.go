@@ -0,0 +1,194 @@
+// utils/gpg.go
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrGPGKeyNotFound is returned when a key ID does not match any secret key.
+var ErrGPGKeyNotFound = errors.New("no secret key found")
+
+// ErrGPGKeyIDTooShort is returned for a key ID shorter than a short key ID, which could
+// match the wrong key.
+var ErrGPGKeyIDTooShort = errors.New("key ID is too short")
+
+// minGPGKeyIDLength is the length of a short key ID, the shortest suffix Matches accepts.
+const minGPGKeyIDLength = 8
+
+// GPGKey is a secret key as reported by 'gpg --list-secret-keys --with-colons'.
+type GPGKey struct {
+	KeyID       string
+	Fingerprint string
+	Validity    string
+	Expires     time.Time
+	UIDs        []string
+}
+
+// ListGPGSecretKeys returns the secret keys in the user's GPG keyring.
+func ListGPGSecretKeys() ([]GPGKey, error) {
+	cmd := execCommand("gpg", "--list-secret-keys", "--with-colons")
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("gpg command failed: %w", err)
+	}
+
+	return ParseGPGColons(string(output)), nil
+}
+
+// ParseGPGColons parses the machine-readable output of gpg --with-colons.
+// Only primary secret keys are returned; subkeys are ignored.
+func ParseGPGColons(output string) []GPGKey {
+	var keys []GPGKey
+
+	var current *GPGKey
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimRight(line, "\r"), ":")
+		if len(fields) < 10 {
+			continue
+		}
+
+		switch fields[0] {
+		case "sec":
+			keys = append(keys, GPGKey{
+				KeyID:    fields[4],
+				Validity: fields[1],
+				Expires:  parseGPGTime(fields[6]),
+			})
+			current = &keys[len(keys)-1]
+		case "ssb":
+			current = nil
+		case "fpr":
+			if current != nil && current.Fingerprint == "" {
+				current.Fingerprint = fields[9]
+			}
+		case "uid":
+			if current != nil && fields[1] != "r" {
+				current.UIDs = append(current.UIDs, unescapeGPGField(fields[9]))
+			}
+		}
+	}
+
+	return keys
+}
+
+// parseGPGTime parses a gpg timestamp, which is either seconds since the epoch or ISO 8601.
+func parseGPGTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0)
+	}
+
+	if t, err := time.Parse("20060102T150405", value); err == nil {
+		return t
+	}
+
+	return time.Time{}
+}
+
+// unescapeGPGField decodes the \xNN escapes gpg uses in colon-delimited fields.
+func unescapeGPGField(value string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+3 < len(value) && value[i+1] == 'x' {
+			if n, err := strconv.ParseUint(value[i+2:i+4], 16, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+
+				continue
+			}
+		}
+
+		b.WriteByte(value[i])
+	}
+
+	return b.String()
+}
+
+// Emails returns the email addresses of the key's user IDs.
+func (k GPGKey) Emails() []string {
+	var emails []string
+
+	for _, uid := range k.UIDs {
+		if addr, err := mail.ParseAddress(uid); err == nil {
+			emails = append(emails, addr.Address)
+		} else if start, end := strings.LastIndex(uid, "<"), strings.LastIndex(uid, ">"); start >= 0 && end > start {
+			emails = append(emails, uid[start+1:end])
+		}
+	}
+
+	return emails
+}
+
+// normalizeGPGKeyID strips the "0x" prefix and "!" suffix gpg allows and uppercases the rest.
+func normalizeGPGKeyID(keyID string) string {
+	return strings.ToUpper(strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(keyID, "0x"), "0X"), "!"))
+}
+
+// Matches reports whether a key ID, long key ID or fingerprint refers to this key.
+// Anything shorter than a short key ID never matches.
+func (k GPGKey) Matches(keyID string) bool {
+	id := normalizeGPGKeyID(keyID)
+	if len(id) < minGPGKeyIDLength {
+		return false
+	}
+
+	return strings.HasSuffix(strings.ToUpper(k.Fingerprint), id) || strings.HasSuffix(strings.ToUpper(k.KeyID), id)
+}
+
+// Validate checks that the key can be used to sign commits for an email address:
+// it must not be expired or revoked, and one of its user IDs must carry the email.
+func (k GPGKey) Validate(email string, now time.Time) error {
+	switch {
+	case k.Validity == "r":
+		return fmt.Errorf("key %s has been revoked", k.KeyID)
+	case k.Validity == "e" || (!k.Expires.IsZero() && k.Expires.Before(now)):
+		return fmt.Errorf("key %s expired on %s", k.KeyID, k.Expires.Format("2006-01-02"))
+	}
+
+	for _, keyEmail := range k.Emails() {
+		if strings.EqualFold(keyEmail, email) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("key %s has no user ID for '%s' (found: %s)", k.KeyID, email, strings.Join(k.Emails(), ", "))
+}
+
+// FindGPGKey returns the key that keyID refers to.
+func FindGPGKey(keys []GPGKey, keyID string) (GPGKey, error) {
+	if len(normalizeGPGKeyID(keyID)) < minGPGKeyIDLength {
+		return GPGKey{}, fmt.Errorf("%w: use at least the %d-digit short key ID or the fingerprint instead of '%s'",
+			ErrGPGKeyIDTooShort, minGPGKeyIDLength, keyID)
+	}
+
+	for _, key := range keys {
+		if key.Matches(keyID) {
+			return key, nil
+		}
+	}
+
+	return GPGKey{}, fmt.Errorf("%w for '%s'", ErrGPGKeyNotFound, keyID)
+}
+
+// FindGPGKeyForEmail returns the first usable key with a user ID for email.
+func FindGPGKeyForEmail(keys []GPGKey, email string, now time.Time) (GPGKey, error) {
+	for _, key := range keys {
+		if key.Validate(email, now) == nil {
+			return key, nil
+		}
+	}
+
+	return GPGKey{}, fmt.Errorf("%w with a valid user ID for '%s'", ErrGPGKeyNotFound, email)
+}
@@ -0,0 +1,110 @@
+// utils/gpg_test.go
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// gpgColonsOutput is a trimmed 'gpg --list-secret-keys --with-colons' listing with
+// one valid key, one expired key and one revoked key.
+const gpgColonsOutput = `sec:u:255:22:1111AAAA2222BBBB:1700000000:::u:::scESC:::+:::ed25519:::0:
+fpr:::::::::0123456789ABCDEF01231111AAAA2222BBBB:
+grp:::::::::AAAA:
+uid:u::::1700000000::HASH::Work User <work@example.com>::::::::::0:
+ssb:u:255:18:3333CCCC4444DDDD:1700000000::::::e:::+:::cv25519::
+fpr:::::::::FFFFFFFFFFFFFFFFFFFF3333CCCC4444DDDD:
+sec:e:255:22:5555EEEE6666FFFF:1500000000:1600000000::u:::sc:::+:::ed25519:::0:
+fpr:::::::::99999999999999999999E5555EEEE6666FFFF:
+uid:e::::1500000000::HASH::Old Key <work@example.com>::::::::::0:
+sec:r:255:22:7777AAAA8888BBBB:1500000000:::u:::sc:::+:::ed25519:::0:
+fpr:::::::::888888888888888888887777AAAA8888BBBB:
+uid:r::::1500000000::HASH::Revoked\x3a Key <revoked@example.com>::::::::::0:
+`
+
+func TestListGPGSecretKeys(t *testing.T) {
+	originalExecCommand := execCommand
+	execCommand = mockExecCommand
+
+	defer func() { execCommand = originalExecCommand }()
+
+	keys, err := ListGPGSecretKeys()
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	if len(keys) != 3 {
+		t.Fatalf("expected 3 secret keys, but got %d", len(keys))
+	}
+
+	if keys[0].Fingerprint != "0123456789ABCDEF01231111AAAA2222BBBB" {
+		t.Errorf("expected the primary key fingerprint, but got '%s'", keys[0].Fingerprint)
+	}
+
+	if emails := keys[0].Emails(); len(emails) != 1 || emails[0] != "work@example.com" {
+		t.Errorf("expected uid email 'work@example.com', but got %v", emails)
+	}
+}
+
+func TestGPGKeyValidation(t *testing.T) {
+	keys := ParseGPGColons(gpgColonsOutput)
+	now := time.Unix(1750000000, 0)
+
+	key, err := FindGPGKey(keys, "0x2222BBBB")
+	if err != nil {
+		t.Fatalf("expected short key ID to match, but got %v", err)
+	}
+
+	if err := key.Validate("work@example.com", now); err != nil {
+		t.Errorf("expected key to be valid, but got %v", err)
+	}
+
+	if err := key.Validate("other@example.com", now); err == nil {
+		t.Error("expected an error for an email without a matching user ID")
+	}
+
+	expired, _ := FindGPGKey(keys, "5555EEEE6666FFFF")
+	if err := expired.Validate("work@example.com", now); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("expected an expiry error, but got %v", err)
+	}
+
+	revoked, _ := FindGPGKey(keys, "7777AAAA8888BBBB")
+	if err := revoked.Validate("revoked@example.com", now); err == nil || !strings.Contains(err.Error(), "revoked") {
+		t.Errorf("expected a revocation error, but got %v", err)
+	}
+
+	if _, err := FindGPGKey(keys, "DEADBEEF"); !errors.Is(err, ErrGPGKeyNotFound) {
+		t.Errorf("expected ErrGPGKeyNotFound, but got %v", err)
+	}
+
+	// "BBBB" is a suffix of the valid key, but too short to identify it.
+	if _, err := FindGPGKey(keys, "0xBBBB"); !errors.Is(err, ErrGPGKeyIDTooShort) {
+		t.Errorf("expected ErrGPGKeyIDTooShort, but got %v", err)
+	}
+
+	if key.Matches("2BBBB") {
+		t.Error("expected a key ID shorter than 8 digits not to match")
+	}
+
+	match, err := FindGPGKeyForEmail(keys, "work@example.com", now)
+	if err != nil || match.KeyID != "1111AAAA2222BBBB" {
+		t.Errorf("expected the valid key to be picked for the email, but got '%s' (%v)", match.KeyID, err)
+	}
+}
+
+func handleGPGCommands(args []string) bool {
+	if len(args) < 2 || args[0] != "gpg" || args[1] != "--list-secret-keys" {
+		return false
+	}
+
+	if _, err := fmt.Fprint(os.Stdout, gpgColonsOutput); err != nil {
+		panic("Failed to write to stdout: " + err.Error())
+	}
+
+	return true
+}
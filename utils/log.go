@@ -0,0 +1,92 @@
+// utils/log.go
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Commit is a commit as reported by 'git log', including its signature details.
+type Commit struct {
+	Hash           string
+	Parents        []string
+	AuthorName     string
+	AuthorEmail    string
+	CommitterName  string
+	CommitterEmail string
+	Date           string
+	Subject        string
+	// SignatureStatus is git's %G? code: N for unsigned, G for good, and so on.
+	SignatureStatus string
+	// SignatureKeys holds the signing key ID, key fingerprint and primary key fingerprint
+	// reported by git, any of which may be empty.
+	SignatureKeys []string
+	// Signer is the signer git reports: a GPG user ID or an SSH principal.
+	Signer string
+}
+
+// IsSigned reports whether the commit carries a signature.
+func (c Commit) IsSigned() bool {
+	return c.SignatureStatus != "" && c.SignatureStatus != "N"
+}
+
+const (
+	logFieldSeparator  = "\x1f"
+	logRecordSeparator = "\x1e"
+)
+
+// logFormat asks git for one record per commit with unit-separated fields.
+var logFormat = strings.Join([]string{
+	"%H", "%P", "%an", "%ae", "%cn", "%ce", "%cI", "%s", "%G?", "%GK", "%GF", "%GP", "%GS",
+}, "%x1f") + "%x1e"
+
+// GetCommits runs 'git log' with the given extra arguments (revisions and filters) and
+// returns the commits it lists.
+func GetCommits(args ...string) ([]Commit, error) {
+	cmdArgs := append([]string{"log", "--format=" + logFormat}, args...)
+	cmd := execCommand("git", cmdArgs...)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	return ParseGitLog(string(output)), nil
+}
+
+// ParseGitLog parses the output of 'git log' in the format GetCommits requests.
+func ParseGitLog(output string) []Commit {
+	var commits []Commit
+
+	for _, record := range strings.Split(output, logRecordSeparator) {
+		fields := strings.Split(strings.TrimLeft(record, "\r\n"), logFieldSeparator)
+		if len(fields) < 13 {
+			continue
+		}
+
+		var keys []string
+
+		for _, key := range fields[9:12] {
+			if key != "" {
+				keys = append(keys, key)
+			}
+		}
+
+		commits = append(commits, Commit{
+			Hash:            fields[0],
+			Parents:         strings.Fields(fields[1]),
+			AuthorName:      fields[2],
+			AuthorEmail:     fields[3],
+			CommitterName:   fields[4],
+			CommitterEmail:  fields[5],
+			Date:            fields[6],
+			Subject:         fields[7],
+			SignatureStatus: fields[8],
+			SignatureKeys:   keys,
+			Signer:          strings.TrimSpace(fields[12]),
+		})
+	}
+
+	return commits
+}
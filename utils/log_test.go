@@ -0,0 +1,38 @@
+// utils/log_test.go
+
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGitLog(t *testing.T) {
+	record := func(fields ...string) string {
+		return strings.Join(fields, logFieldSeparator) + logRecordSeparator + "\n"
+	}
+
+	output := record("abc123", "def456", "Work User", "work@corp.com", "Work User", "work@corp.com",
+		"2025-06-01T10:00:00+00:00", "Add feature", "G", "1234ABCD", "FFFF1234ABCD", "EEEE5678", "Work User <work@corp.com>") +
+		record("def456", "", "Me", "me@gmail.com", "GitHub", "noreply@github.com",
+			"2025-06-02T10:00:00+00:00", "Fix typo", "N", "", "", "", "")
+
+	commits := ParseGitLog(output)
+	if len(commits) != 2 {
+		t.Fatalf("expected 2 commits, got %d", len(commits))
+	}
+
+	first := commits[0]
+	if first.Hash != "abc123" || len(first.Parents) != 1 || first.AuthorEmail != "work@corp.com" || first.Subject != "Add feature" {
+		t.Errorf("unexpected first commit: %+v", first)
+	}
+
+	if !first.IsSigned() || len(first.SignatureKeys) != 3 || first.Signer != "Work User <work@corp.com>" {
+		t.Errorf("expected a signed commit with 3 key IDs, got %+v", first)
+	}
+
+	second := commits[1]
+	if second.IsSigned() || len(second.SignatureKeys) != 0 || len(second.Parents) != 0 || second.CommitterEmail != "noreply@github.com" {
+		t.Errorf("unexpected second commit: %+v", second)
+	}
+}
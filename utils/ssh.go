@@ -0,0 +1,55 @@
+// utils/ssh.go
+
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+// ResolveSSHConfig runs 'ssh -G' against a host and returns the options ssh would use,
+// keyed by lowercase option name. Options that ssh prints more than once, such as
+// identityfile, are joined with newlines.
+func ResolveSSHConfig(env []string, configPath, host string) (map[string]string, error) {
+	cmd := execCommand("ssh", "-G", "-F", configPath, host)
+	cmd.Env = append(cmd.Environ(), env...)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ssh -G failed: %w", err)
+	}
+
+	options := make(map[string]string)
+
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), " ")
+		if !found {
+			continue
+		}
+
+		if existing, ok := options[key]; ok {
+			value = existing + "\n" + value
+		}
+
+		options[key] = value
+	}
+
+	return options, nil
+}
+
+// TestSSHAuth opens a non-interactive ssh session to a host and returns whatever the
+// server printed. Git hosts such as GitHub greet the authenticated account and then
+// close the connection with a non-zero status, so the exit code alone is not an error.
+func TestSSHAuth(env []string, configPath, target string) (string, error) {
+	cmd := execCommand("ssh", "-T", "-o", "BatchMode=yes", "-F", configPath, target)
+	cmd.Env = append(cmd.Environ(), env...)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil && len(output) == 0 {
+		return "", fmt.Errorf("ssh connection failed: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,47 @@
+// utils/ssh_test.go
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestResolveSSHConfig(t *testing.T) {
+	originalExecCommand := execCommand
+	execCommand = mockExecCommand
+
+	defer func() { execCommand = originalExecCommand }()
+
+	options, err := ResolveSSHConfig([]string{"GITEGO_SSH_PROFILE=work"}, "/tmp/ssh_config", "github.com")
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	if options["identitiesonly"] != "yes" {
+		t.Errorf("expected identitiesonly 'yes', but got '%s'", options["identitiesonly"])
+	}
+
+	if options["identityfile"] != "~/.ssh/id_work\n~/.ssh/id_rsa" {
+		t.Errorf("expected both identity files, but got '%s'", options["identityfile"])
+	}
+}
+
+func handleSSHCommands(args []string) bool {
+	if len(args) < 2 || args[0] != "ssh" || args[1] != "-G" {
+		return false
+	}
+
+	// The profile selector must reach ssh through the environment.
+	if os.Getenv("GITEGO_SSH_PROFILE") != "work" {
+		os.Exit(1)
+	}
+
+	if _, err := fmt.Fprint(os.Stdout,
+		"user git\nport 22\nidentitiesonly yes\nidentityfile ~/.ssh/id_work\nidentityfile ~/.ssh/id_rsa\n"); err != nil {
+		panic("Failed to write to stdout: " + err.Error())
+	}
+
+	return true
+}